@@ -0,0 +1,157 @@
+// Package playground holds what the tools know about the Go Playground links
+// in the training markdown and the cache of playground ids that mpl keeps.
+package playground
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Link pulls out the title and source code file name.
+	// [Title](example1/example1.go)
+	Link = regexp.MustCompile("\\[([^\\]]+)\\]\\(([^\\)]+)\\)")
+
+	// LinkInfo matches what source code files have a bound playground link.
+	// [Title](example1/example1.go) ([Go Playground](http://play.golang.org/p/CoBIh_6Hjj))
+	LinkInfo = regexp.MustCompile("\\[[^\\]]+\\]\\([^\\)]+\\.go\\) +\\(\\[Go Playground\\]\\(([^\\)]*)\\)\\)")
+)
+
+// DefaultEndpoint is where code is shared to make a playground link.
+const DefaultEndpoint = "https://play.golang.org/share"
+
+// Key returns the key for source code in the cache.
+func Key(srcCode []byte) string {
+	sum := sha256.Sum256(srcCode)
+	return hex.EncodeToString(sum[:])
+}
+
+// URL returns the playground link for an id from the share endpoint.
+// https://play.golang.org/share gives links like https://play.golang.org/p/id.
+func URL(endpoint, id string) string {
+	return strings.TrimSuffix(endpoint, "/share") + "/p/" + id
+}
+
+// =============================================================================
+
+// Cache remembers the playground ids of code that has been shared, for each
+// playground endpoint.
+type Cache struct {
+	path string
+	IDs  map[string]map[string]string `json:"ids"` // Ids by Key, by endpoint.
+}
+
+// CachePath is where the cache is committed, from the root of the
+// repository. Keeping it with the markdown means a fresh clone or a CI run
+// knows every link that has been made.
+const CachePath = "tools/mpl/links.json"
+
+// DefaultCachePath returns the cache committed in the repository, found by
+// looking for it from the working directory up. It returns "" when the
+// working directory isn't in the repository.
+func DefaultCachePath() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, filepath.FromSlash(CachePath))
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadCache reads the cache from the file. A missing file is an empty cache
+// and an empty path is a cache that isn't saved.
+func LoadCache(path string) (*Cache, error) {
+	c := Cache{path: path, IDs: make(map[string]map[string]string)}
+	if path == "" {
+		return &c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return &c, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("reading cache %s: %w", path, err)
+	}
+	if c.IDs == nil {
+		c.IDs = make(map[string]map[string]string)
+	}
+
+	return &c, nil
+}
+
+// Endpoint returns the ids cached for a playground endpoint. Ids added to
+// the map are saved with the cache.
+func (c *Cache) Endpoint(url string) map[string]string {
+	if c.IDs[url] == nil {
+		c.IDs[url] = make(map[string]string)
+	}
+	return c.IDs[url]
+}
+
+// Save writes the cache back to its file.
+func (c *Cache) Save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	return WriteFile(c.path, data, 0644)
+}
+
+// =============================================================================
+
+// WriteFile writes data to a temporary file and renames it over the named
+// file, so the file is never left half written.
+func WriteFile(name string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), name)
+}
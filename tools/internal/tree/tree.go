@@ -0,0 +1,22 @@
+// Package tree holds what the tools agree on about walking the training
+// material.
+package tree
+
+import "strings"
+
+// skipDirs are never walked.
+var skipDirs = map[string]bool{
+	"vendor":       true,
+	"testdata":     true,
+	"node_modules": true,
+}
+
+// Skip reports whether the tools should skip the directory with the
+// specified name. Like the go command, it skips vendor and testdata and the
+// directories starting with a dot or an underscore.
+func Skip(name string) bool {
+	if name == "." || name == ".." {
+		return false
+	}
+	return skipDirs[name] || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
@@ -0,0 +1,27 @@
+// Package treetest provides a helper for the tools tests to lay out
+// training material to work on.
+package treetest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Write writes the files, keyed by their slash separated names, under a
+// temporary directory and returns it.
+func Write(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
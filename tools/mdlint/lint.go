@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ardanlabs/gotraining/tools/internal/playground"
+	"github.com/ardanlabs/gotraining/tools/internal/tree"
+)
+
+// Set of rules a finding can break.
+const (
+	ruleMissingFile        = "missing-file"
+	ruleUnreferenced       = "unreferenced-example"
+	ruleDuplicateTitle     = "duplicate-title"
+	ruleStalePlayground    = "stale-playground"
+	ruleUncachedPlayground = "uncached-playground"
+)
+
+// Set of severities. Only errors make the command fail.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// Finding is a problem found in the markdown.
+type Finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// String formats the finding the way compilers do.
+func (f Finding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s (%s)", f.File, f.Severity, f.Message, f.Rule)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s (%s)", f.File, f.Line, f.Severity, f.Message, f.Rule)
+}
+
+var (
+	// rgxMdLink matches any markdown link or image and pulls out the target.
+	// [Title](example1/example1.go) or ![Diagram](diagram.png "title")
+	rgxMdLink = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+	// rgxCode matches inline code, which can hold things that look like links.
+	rgxCode = regexp.MustCompile("`[^`]*`")
+
+	// rgxScheme matches targets that aren't local files.
+	// https://golang.org, mailto:bill@ardanlabs.com
+	rgxScheme = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+
+	// rgxExampleDir matches the names of directories holding code for the
+	// markdown to link to.
+	rgxExampleDir = regexp.MustCompile(`^(example|exercise|template)[0-9]+$`)
+)
+
+// =============================================================================
+
+// linter checks the markdown under a set of directories. References are
+// collected across all of them, so markdown in one directory can link to an
+// example in another.
+type linter struct {
+	roots    []string
+	offline  bool
+	endpoint string
+	ids      map[string]string // Recorded playground ids by playground.Key.
+
+	findings   []Finding
+	referenced map[string]bool // Absolute paths some link points at or into.
+}
+
+// mdFile is a markdown file and the root it was found under.
+type mdFile struct {
+	path string
+	root string
+}
+
+// lint walks the trees and returns what it found, sorted by file and line.
+func (l *linter) lint() ([]Finding, error) {
+	l.referenced = make(map[string]bool)
+
+	var mdFiles []mdFile
+	var exampleDirs []string
+	seen := make(map[string]bool) // Roots can overlap.
+
+	for _, root := range l.roots {
+		walk := func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			if seen[abs] {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			seen[abs] = true
+
+			name := d.Name()
+			if d.IsDir() {
+				if path != root && tree.Skip(name) {
+					return filepath.SkipDir
+				}
+				if rgxExampleDir.MatchString(name) {
+					exampleDirs = append(exampleDirs, path)
+				}
+				return nil
+			}
+
+			if strings.EqualFold(filepath.Ext(name), ".md") {
+				mdFiles = append(mdFiles, mdFile{path: path, root: root})
+			}
+			return nil
+		}
+
+		if err := filepath.WalkDir(root, walk); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, md := range mdFiles {
+		if err := l.lintFile(md); err != nil {
+			return nil, err
+		}
+	}
+
+	// A directory of code that nothing links to is either dead or missing
+	// from the material.
+	for _, dir := range exampleDirs {
+		if abs, _ := filepath.Abs(dir); l.referenced[abs] {
+			continue
+		}
+		if code, _ := filepath.Glob(filepath.Join(dir, "*.go")); len(code) > 0 {
+			l.report(dir, 0, ruleUnreferenced, severityWarning, "no markdown links to this example")
+		}
+	}
+
+	slices.SortFunc(l.findings, func(a, b Finding) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Rule, b.Rule))
+	})
+
+	return l.findings, nil
+}
+
+// report records a finding.
+func (l *linter) report(file string, line int, rule, severity, format string, args ...any) {
+	l.findings = append(l.findings, Finding{
+		File:     file,
+		Line:     line,
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// lintFile checks the links in one markdown file.
+func (l *linter) lintFile(md mdFile) error {
+	file := md.path
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	// Titles of playground links in the current section, and the line each
+	// was first seen on. Exercises reuse titles like "Answer" under their
+	// own headings.
+	titles := make(map[string]int)
+
+	var fenced bool
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fenced = !fenced
+			continue
+		case fenced:
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			clear(titles)
+		}
+
+		line = rgxCode.ReplaceAllString(line, "")
+
+		for _, m := range rgxMdLink.FindAllStringSubmatch(line, -1) {
+			l.checkTarget(md, n, m[1])
+		}
+
+		for _, m := range playground.LinkInfo.FindAllStringSubmatch(line, -1) {
+			lm := playground.Link.FindStringSubmatch(m[0])
+			title, srcFile, playLink := lm[1], lm[2], m[1]
+
+			if first, dup := titles[title]; dup {
+				l.report(file, n, ruleDuplicateTitle, severityWarning, "title %q is also used on line %d", title, first)
+			} else {
+				titles[title] = n
+			}
+
+			if l.offline {
+				l.checkPlayground(file, n, srcFile, playLink)
+			}
+		}
+	}
+
+	return s.Err()
+}
+
+// checkTarget checks a link points at a file that exists, if it's local.
+func (l *linter) checkTarget(md mdFile, line int, target string) {
+	file := md.path
+
+	if rgxScheme.MatchString(target) || strings.HasPrefix(target, "#") {
+		return
+	}
+
+	target, _, _ = strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+
+	// Absolute paths are from the top of the tree the file is in.
+	path := filepath.Join(filepath.Dir(file), filepath.FromSlash(target))
+	if strings.HasPrefix(target, "/") {
+		path = filepath.Join(md.root, filepath.FromSlash(target))
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		l.report(file, line, ruleMissingFile, severityError, "link to missing file %s", target)
+		return
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	for p := path; !l.referenced[p]; p = filepath.Dir(p) {
+		l.referenced[p] = true
+		if p == filepath.Dir(p) {
+			break
+		}
+	}
+}
+
+// checkPlayground checks a playground link against the one recorded for the
+// code in the cache.
+func (l *linter) checkPlayground(file string, line int, srcFile, playLink string) {
+	code, err := os.ReadFile(filepath.Join(filepath.Dir(file), filepath.FromSlash(srcFile)))
+	if err != nil {
+
+		// Already reported as a missing file.
+		return
+	}
+
+	id, recorded := l.ids[playground.Key(code)]
+	switch {
+	case !recorded:
+		l.report(file, line, ruleUncachedPlayground, severityWarning, "no recorded playground link for %s, run mpl to record one", srcFile)
+	case playground.URL(l.endpoint, id) != playLink:
+		l.report(file, line, ruleStalePlayground, severityError, "playground link for %s is %q, want %q", srcFile, playLink, playground.URL(l.endpoint, id))
+	}
+}
@@ -0,0 +1,99 @@
+// Package main provides a CLI tool to check the links in the training
+// markdown. It reports links to files that don't exist, example directories
+// no markdown links to, repeated titles and, offline, playground links that
+// don't match the code they were made from.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ardanlabs/gotraining/tools/internal/playground"
+)
+
+// =============================================================================
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run lints the directories named in the arguments and returns the exit
+// status: 1 if there were errors and 2 if the tool couldn't run.
+func run(args []string, stdout, stderr io.Writer) int {
+
+	// Parse the flags from the command line call.
+	fs := flag.NewFlagSet("mdlint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "write the findings as JSON")
+	offline := fs.Bool("offline", false, "check playground links against the ids mpl recorded")
+	cachePath := fs.String("cache", playground.DefaultCachePath(), "file of playground ids recorded by mpl")
+	endpoint := fs.String("endpoint", playground.DefaultEndpoint, "playground share endpoint the ids came from")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mdlint [options] [dir...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	roots := fs.Args()
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	var ids map[string]string
+	if *offline {
+		if *cachePath == "" {
+			fmt.Fprintf(stderr, "mdlint: -offline needs the cache mpl commits to %s, run from the repository or use -cache\n", playground.CachePath)
+			return 2
+		}
+		c, err := playground.LoadCache(*cachePath)
+		if err != nil {
+			fmt.Fprintln(stderr, "mdlint:", err)
+			return 2
+		}
+		ids = c.Endpoint(*endpoint)
+	}
+
+	l := linter{roots: roots, offline: *offline, endpoint: *endpoint, ids: ids}
+
+	findings, err := l.lint()
+	if err != nil {
+		fmt.Fprintln(stderr, "mdlint:", err)
+		return 2
+	}
+	if findings == nil {
+		findings = []Finding{}
+	}
+
+	var errors, warnings int
+	for _, f := range findings {
+		if f.Severity == severityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			fmt.Fprintln(stderr, "mdlint:", err)
+			return 2
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Fprintln(stdout, f)
+		}
+		fmt.Fprintf(stderr, "%d errors, %d warnings\n", errors, warnings)
+	}
+
+	if errors > 0 {
+		return 1
+	}
+	return 0
+}
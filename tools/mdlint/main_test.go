@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/tools/internal/playground"
+	"github.com/ardanlabs/gotraining/tools/internal/treetest"
+)
+
+// lint runs the command and returns its status and the findings as JSON.
+func lint(t *testing.T, args ...string) (int, []Finding) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	status := run(append([]string{"-json"}, args...), &stdout, &stderr)
+
+	var findings []Finding
+	if err := json.Unmarshal(stdout.Bytes(), &findings); err != nil {
+		t.Fatalf("decoding output: %v\n%s%s", err, stdout.String(), stderr.String())
+	}
+	return status, findings
+}
+
+// rules returns "line:rule" for each finding in a file.
+func rules(findings []Finding, dir, name string) []string {
+	var got []string
+	for _, f := range findings {
+		if f.File != filepath.Join(dir, filepath.FromSlash(name)) {
+			continue
+		}
+		if f.Line == 0 {
+			got = append(got, f.Rule)
+			continue
+		}
+		got = append(got, fmt.Sprintf("%d:%s", f.Line, f.Rule))
+	}
+	return got
+}
+
+const (
+	succeed = "\u2713"
+	failed  = "\u2717"
+)
+
+const code = "package main\n\nfunc main() {}\n"
+
+// TestLint validates the problems found in the markdown and examples.
+func TestLint(t *testing.T) {
+	dir := treetest.Write(t, map[string]string{
+		"README.md": "# Topic\n\n" +
+			"[Declare](example1/example1.go) ([Go Playground](https://play.golang.org/p/a))  \n" +
+			"[Declare](example2/example2.go) ([Go Playground](https://play.golang.org/p/b))  \n" +
+			"[Missing](example9/example9.go) ([Go Playground](https://play.golang.org/p/c))  \n" +
+			"[Diagram](diagram.png \"the design\") and [section](#exercises) and [site](https://golang.org)  \n" +
+			"Use `[not](a/link)` to write a link.\n" +
+			"```\n[also not](a/link)\n```\n" +
+			"## Exercises\n\n" +
+			"### Exercise 1\n\n" +
+			"[Answer](exercises/exercise1/exercise1.go) ([Go Playground](https://play.golang.org/p/d))\n\n" +
+			"### Exercise 2\n\n" +
+			"[Answer](exercises/exercise2/exercise2.go) ([Go Playground](https://play.golang.org/p/e))\n",
+		"diagram.png":                      "",
+		"example1/example1.go":             code,
+		"example2/example2.go":             code,
+		"example3/example3.go":             code,
+		"exercises/exercise1/exercise1.go": code,
+		"exercises/exercise2/exercise2.go": code,
+		"other/README.md":                  "See [the topic](/README.md) and [missing](../nope.md#top).\n",
+		"vendor/pkg/README.md":             "[vendored](missing.go)\n",
+		"testdata/example4/example4.go":    code,
+		"notes/example5/README.md":         "No code in this example.\n",
+	})
+
+	tt := []struct {
+		file string
+		exp  []string
+	}{
+		{"README.md", []string{"4:duplicate-title", "5:missing-file"}},
+		{"other/README.md", []string{"1:missing-file"}},
+		{"example3", []string{"unreferenced-example"}},
+		{"vendor/pkg/README.md", nil},
+		{"testdata/example4", nil},
+		{"notes/example5", nil},
+	}
+
+	t.Log("Given the need to find the problems in the markdown.")
+	{
+		status, findings := lint(t, dir)
+
+		t.Log("\tTest 0:\tWhen linting a topic with problems.")
+		{
+			if status != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould exit with status 1 : got %d", failed, status)
+			}
+			t.Logf("\t%s\tTest 0:\tShould exit with status 1.", succeed)
+
+			if len(findings) != 4 {
+				t.Fatalf("\t%s\tTest 0:\tShould find 4 problems : got %+v", failed, findings)
+			}
+			t.Logf("\t%s\tTest 0:\tShould find 4 problems.", succeed)
+		}
+
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen checking %s.", i+1, tst.file)
+			{
+				if got := rules(findings, dir, tst.file); !slices.Equal(got, tst.exp) {
+					t.Errorf("\t%s\tTest %d:\tShould find %v : got %v", failed, i+1, tst.exp, got)
+					continue
+				}
+				t.Logf("\t%s\tTest %d:\tShould find %v.", succeed, i+1, tst.exp)
+			}
+		}
+	}
+}
+
+// TestClean validates nothing is reported for markdown without problems.
+func TestClean(t *testing.T) {
+	dir := treetest.Write(t, map[string]string{
+		"README.md":            "[Declare](example1/example1.go) ([Go Playground](https://play.golang.org/p/a))\n",
+		"example1/example1.go": code,
+	})
+
+	t.Log("Given the need to lint markdown without problems.")
+	{
+		t.Log("\tTest 0:\tWhen reporting the findings as JSON.")
+		{
+			if status, findings := lint(t, dir); status != 0 || len(findings) != 0 {
+				t.Fatalf("\t%s\tTest 0:\tShould exit with status 0 and no findings : got %d %v", failed, status, findings)
+			}
+			t.Logf("\t%s\tTest 0:\tShould exit with status 0 and no findings.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen reporting the findings as text.")
+		{
+			var stdout, stderr bytes.Buffer
+			if status := run([]string{dir}, &stdout, &stderr); status != 0 || stdout.Len() != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould exit with status 0 and no output : got %d %q", failed, status, stdout.String())
+			}
+			t.Logf("\t%s\tTest 1:\tShould exit with status 0 and no output.", succeed)
+
+			if got := stderr.String(); got != "0 errors, 0 warnings\n" {
+				t.Fatalf("\t%s\tTest 1:\tShould summarize no problems : got %q", failed, got)
+			}
+			t.Logf("\t%s\tTest 1:\tShould summarize no problems.", succeed)
+		}
+	}
+}
+
+// TestOffline validates the playground links are checked against the cache.
+func TestOffline(t *testing.T) {
+	const (
+		stale = "package main\n\nfunc main() { println() }\n"
+		other = "package main\n\nfunc main() { panic(0) }\n"
+	)
+
+	dir := treetest.Write(t, map[string]string{
+		"README.md": "[Fresh](example1/example1.go) ([Go Playground](https://play.golang.org/p/fresh))\n" +
+			"[Stale](example2/example2.go) ([Go Playground](https://play.golang.org/p/old))\n" +
+			"[Uncached](example3/example3.go) ([Go Playground]())\n",
+		"example1/example1.go": code,
+		"example2/example2.go": stale,
+		"example3/example3.go": other,
+	})
+
+	cachePath := filepath.Join(t.TempDir(), "links.json")
+	c, err := playground.LoadCache(cachePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := c.Endpoint(playground.DefaultEndpoint)
+	ids[playground.Key([]byte(code))] = "fresh"
+	ids[playground.Key([]byte(stale))] = "new"
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("Given the need to check the playground links without a network.")
+	{
+		t.Log("\tTest 0:\tWhen linting offline with a cache.")
+		{
+			status, findings := lint(t, "-offline", "-cache", cachePath, dir)
+			if status != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould exit with status 1 : got %d", failed, status)
+			}
+			t.Logf("\t%s\tTest 0:\tShould exit with status 1.", succeed)
+
+			exp := []string{"2:stale-playground", "3:uncached-playground"}
+			if got := rules(findings, dir, "README.md"); !slices.Equal(got, exp) {
+				t.Fatalf("\t%s\tTest 0:\tShould find the stale and uncached links : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould find the stale and uncached links.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen linting without -offline.")
+		{
+			if status, findings := lint(t, dir); status != 0 || len(findings) != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould not check the playground links : got %d %v", failed, status, findings)
+			}
+			t.Logf("\t%s\tTest 1:\tShould not check the playground links.", succeed)
+		}
+	}
+}
+
+// TestRoots validates references are collected across all the roots.
+func TestRoots(t *testing.T) {
+	dir := treetest.Write(t, map[string]string{
+		"a/README.md":            "[Declare](../b/example1/example1.go) ([Go Playground](https://play.golang.org/p/a))\n",
+		"b/example1/example1.go": code,
+		"b/example2/example2.go": code,
+	})
+
+	t.Log("Given the need to lint several directories at once.")
+	{
+		t.Log("\tTest 0:\tWhen the roots link to each other and overlap.")
+		{
+			_, findings := lint(t, filepath.Join(dir, "a"), filepath.Join(dir, "b"), dir)
+
+			if got := rules(findings, dir, "b/example1"); got != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould accept an example linked from another root : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould accept an example linked from another root.", succeed)
+
+			if got := rules(findings, dir, "b/example2"); !slices.Equal(got, []string{"unreferenced-example"}) {
+				t.Fatalf("\t%s\tTest 0:\tShould report the unlinked example : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould report the unlinked example.", succeed)
+
+			if len(findings) != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould report each problem once : got %+v", failed, findings)
+			}
+			t.Logf("\t%s\tTest 0:\tShould report each problem once.", succeed)
+		}
+	}
+}
+
+// TestCommittedCache validates the cache committed in the repo is used.
+func TestCommittedCache(t *testing.T) {
+	dir := treetest.Write(t, map[string]string{
+		"README.md":            "[Stale](example1/example1.go) ([Go Playground](https://play.golang.org/p/old))\n",
+		"example1/example1.go": code,
+	})
+
+	c, err := playground.LoadCache(filepath.Join(dir, filepath.FromSlash(playground.CachePath)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Endpoint(playground.DefaultEndpoint)[playground.Key([]byte(code))] = "new"
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("Given the need to lint offline with the cache in the repo.")
+	{
+		t.Log("\tTest 0:\tWhen running from a directory inside the repo.")
+		{
+			// The cache is found from the working directory, anywhere in the tree.
+			t.Chdir(filepath.Join(dir, "example1"))
+
+			_, findings := lint(t, "-offline", dir)
+			if got := rules(findings, dir, "README.md"); !slices.Equal(got, []string{"1:stale-playground"}) {
+				t.Fatalf("\t%s\tTest 0:\tShould find the stale link from the committed cache : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould find the stale link from the committed cache.", succeed)
+		}
+	}
+}
@@ -4,114 +4,229 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"flag"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"net/http"
+	"os"
 	"path"
-	"regexp"
-)
-
-var (
-	// rgxLink pulls out the title and source code file name.
-	// [Title](example1/example1.go)
-	rgxLink = regexp.MustCompile("\\[([^\\]]+)\\]\\(([^\\)]+)\\)")
+	"strings"
+	"time"
 
-	// rgxLinkInfo matches what source code files have a bound playground link.
-	// [Title](example1/example1.go) ([Go Playground](http://play.golang.org/p/CoBIh_6Hjj))
-	rgxLinkInfo = regexp.MustCompile("\\[[^\\]]+\\]\\([^\\)]+\\.go\\) +\\(\\[Go Playground\\]\\([^\\)]*\\)\\)")
+	"github.com/ardanlabs/gotraining/tools/internal/playground"
 )
 
 // =============================================================================
 
 func main() {
-	const version = "1.1"
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run processes the markdown files named in the arguments and returns the
+// exit status.
+func run(args []string, stderr io.Writer) int {
+	const version = "1.2"
+
+	log := log.New(stderr, "", log.LstdFlags)
 
 	// Parse the flags from the command line call.
-	flag.Parse()
+	fs := flag.NewFlagSet("mpl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	endpoint := fs.String("endpoint", playground.DefaultEndpoint, "playground share endpoint")
+	cachePath := fs.String("cache", playground.DefaultCachePath(), "file caching playground ids, empty to disable")
+	check := fs.Bool("check", false, "report stale links and exit 1 without changing files")
+	dryRun := fs.Bool("dry-run", false, "report the links that would change without changing files")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
 
 	// For sanity checks with multiple versions of the tool.
-	if len(flag.Args()) == 0 {
+	if fs.NArg() == 0 {
 		log.SetFlags(0)
 		log.Println("Ver", version)
-		return
+		return 0
+	}
+
+	c, err := playground.LoadCache(*cachePath)
+	if err != nil {
+		log.Println("ERROR:", err)
+		return 1
+	}
+
+	// Checking never shares code, so a link without a cached id is
+	// reported as stale instead.
+	offline := *check || *dryRun
+
+	l := linker{
+		endpoint: *endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		ids:      c.Endpoint(*endpoint),
+		offline:  offline,
 	}
 
 	// A list of files are expected to be passed on the command line.
 	// If you run zsh you can use this support: mpl **/*.md
+	// A file that fails is left as it was and the rest are still processed.
+
+	var failed, stale bool
+	for _, file := range fs.Args() {
+		log.Println("Processing", file)
+
+		changes, err := process(file, &l, offline)
+		if err != nil {
+			log.Println("ERROR:", err)
+			failed = true
+			continue
+		}
 
-	for _, file := range flag.Args() {
-		if err := process(file); err != nil {
-			log.Fatal(err)
+		for _, ch := range changes {
+			switch {
+			case *check:
+				log.Printf("STALE: %s: %s", file, ch)
+			case *dryRun:
+				log.Printf("WOULD UPDATE: %s: %s", file, ch)
+			default:
+				log.Println("UPDATE:", ch)
+			}
 		}
+		stale = stale || len(changes) > 0
+	}
+
+	if !offline {
+		if err := c.Save(); err != nil {
+			log.Println("ERROR: saving cache:", err)
+			failed = true
+		}
+	}
+
+	if failed || (*check && stale) {
+		return 1
 	}
+	return 0
 }
 
 // process looks for source code links in a given markdown file. For every
 // link that is found, the source code is run through the playground to generate
-// a new playground link. Then the markdown file is updated.
-func process(mdFile string) error {
-	log.Println("Processing", mdFile)
+// a new playground link. Then the markdown file is updated, unless dryRun is
+// set. It returns the links that changed. The file is only written once every
+// link is known, so an error leaves it as it was.
+func process(mdFile string, l *linker, dryRun bool) ([]string, error) {
 
 	// Read in the entire markdown file.
-	srcMd, err := ioutil.ReadFile(mdFile)
+	srcMd, err := os.ReadFile(mdFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// This function will be used to process every source code link that
-	// is found inside the markdown file based on the "rgxLinkInfo" regular exp.
-	f := func(linkInfo []byte) []byte {
+	var res bytes.Buffer
+	var changes []string
+	last := 0
+
+	// Find all matches for the link info regex against the markdown file.
+	// For every match, replace the current playground link with an updated
+	// one.
+	for _, loc := range playground.LinkInfo.FindAllIndex(srcMd, -1) {
+		linkInfo := srcMd[loc[0]:loc[1]]
 
 		// linkInfo : [Title](example1/example1.go) ([Go Playground](http://play.golang.org/p/CoBIh_6Hjj))
 
 		// Create a match value to extract the title and source code file name.
 		// [Title](example1/example1.go)
-		m := rgxLink.FindAllSubmatch(linkInfo, 1)
+		m := playground.Link.FindSubmatch(linkInfo)
 
 		// Extract the title and source file name from the match.
-		title := string(m[0][1])
-		srcFile := string(m[0][2])
+		title := string(m[1])
+		srcFile := string(m[2])
 
 		// Read in the contents of the source code file.
-		srcCode, err := ioutil.ReadFile(path.Join(path.Dir(mdFile), srcFile))
+		srcCode, err := os.ReadFile(path.Join(path.Dir(mdFile), srcFile))
 		if err != nil {
-			log.Fatalf("ERROR: Reading Title[%s] ScrFile[%s] : %v", title, srcFile, err)
+			return nil, fmt.Errorf("reading Title[%s] SrcFile[%s] : %w", title, srcFile, err)
 		}
 
-		// Send the contents of the source code file to the playground.
-		// Generate a new link for this code.
-		playLink, err := generatePlayLink(srcCode)
-		if err != nil {
-			log.Fatalf("ERROR: Generating Link Title[%s] ScrFile[%s] : %v", title, srcFile, err)
+		// Find the playground link for this code, generating a new one if
+		// the code has changed. Offline, code that isn't cached can't have
+		// a current link, so the link is stale but left as it is.
+		playLink, err := l.link(srcCode)
+		switch {
+		case errors.Is(err, errNotCached):
+			changes = append(changes, fmt.Sprintf("[%s](%s) has no cached playground link", title, srcFile))
+			playLink = string(playground.LinkInfo.FindSubmatch(linkInfo)[1])
+
+		case err != nil:
+			return nil, fmt.Errorf("generating link Title[%s] SrcFile[%s] : %w", title, srcFile, err)
 		}
 
-		// Generate the link information to replace the existing link information
-		// in the markdown file.
-		l := fmt.Sprintf("[%s](%s) ([Go Playground](%s))", title, srcFile, playLink)
-		log.Println("UPDATE:", l)
+		// Generate the link information to replace the existing link
+		// information in the markdown file.
+		newInfo := fmt.Sprintf("[%s](%s) ([Go Playground](%s))", title, srcFile, playLink)
+		if newInfo != string(linkInfo) {
+			changes = append(changes, newInfo)
+		}
 
-		return []byte(l)
+		res.Write(srcMd[last:loc[0]])
+		res.WriteString(newInfo)
+		last = loc[1]
 	}
+	res.Write(srcMd[last:])
 
-	// Find all matches for the link info regex against the markdown file.
-	// For every match, execute the anonymous function above to replace the
-	// current playground link with an updated one inside the markdown file.
-	res := rgxLinkInfo.ReplaceAllFunc(srcMd, f)
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
 
-	// Write the new markdown file back to disk with proper permissions.
-	return ioutil.WriteFile(mdFile, res, 077)
+	// Write the new markdown file back to disk with its permissions.
+	info, err := os.Stat(mdFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, playground.WriteFile(mdFile, res.Bytes(), info.Mode().Perm())
 }
 
-// generatePlayLink returns the URL for the playground link based on the
-// source code that is provided.
-func generatePlayLink(srcCode []byte) (string, error) {
-	const url = "https://play.golang.org/share"
+// =============================================================================
+
+// errNotCached is returned by an offline linker for code it has no id for.
+var errNotCached = errors.New("no cached playground id")
+
+// linker finds the playground link for source code.
+type linker struct {
+	endpoint string
+	client   *http.Client
+	ids      map[string]string // Playground ids by playground.Key.
+	offline  bool              // Never share code, only use the cache.
+}
+
+// link returns the URL for the playground link based on the source code that
+// is provided. Code that has been shared before gets the link from the cache
+// without going to the network.
+func (l *linker) link(srcCode []byte) (string, error) {
+	key := playground.Key(srcCode)
+
+	id, cached := l.ids[key]
+	switch {
+	case !cached && l.offline:
+		return "", errNotCached
+
+	case !cached:
+		var err error
+		if id, err = l.share(srcCode); err != nil {
+			return "", err
+		}
+		l.ids[key] = id
+	}
+
+	return playground.URL(l.endpoint, id), nil
+}
+
+// share posts the source code to the playground and returns the id of the
+// snippet.
+func (l *linker) share(srcCode []byte) (string, error) {
 	const mime = "application/x-www-form-urlencoded; charset=UTF-8"
 
 	// Make a call to the playground, posting the source code file contents.
-	res, err := http.Post(url, mime, bytes.NewReader(srcCode))
+	res, err := l.client.Post(l.endpoint, mime, bytes.NewReader(srcCode))
 	if err != nil {
 		return "", err
 	}
@@ -123,10 +238,15 @@ func generatePlayLink(srcCode []byte) (string, error) {
 	}
 
 	// Read back the generated URL GUID for this source code.
-	b, err := ioutil.ReadAll(res.Body)
+	b, err := io.ReadAll(res.Body)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("https://play.golang.org/p/%s", string(b)), nil
+	id := strings.TrimSpace(string(b))
+	if id == "" || strings.ContainsAny(id, "/?# ") {
+		return "", fmt.Errorf("unexpected playground id %q", id)
+	}
+
+	return id, nil
 }
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	succeed = "\u2713"
+	failed  = "\u2717"
+)
+
+// fakePlayground is a fake of the playground's share endpoint.
+type fakePlayground struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	shared int  // Snippets posted.
+	fail   bool // Respond with an error.
+}
+
+// newFakePlayground starts a fake playground that gives each snippet an id
+// made from its hash, like the real one does.
+func newFakePlayground(t *testing.T) *fakePlayground {
+	var p fakePlayground
+
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if p.fail || r.Method != http.MethodPost || r.URL.Path != "/share" {
+			http.Error(w, "no", http.StatusInternalServerError)
+			return
+		}
+		p.shared++
+
+		b, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%x", sha256.Sum256(b))
+	}))
+	t.Cleanup(p.Close)
+
+	return &p
+}
+
+// posts returns the number of snippets shared and resets the count.
+func (p *fakePlayground) posts() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := p.shared
+	p.shared = 0
+	return n
+}
+
+// link returns the link the fake playground gives the code.
+func (p *fakePlayground) link(code string) string {
+	return fmt.Sprintf("%s/p/%x", p.URL, sha256.Sum256([]byte(code)))
+}
+
+// write creates a file in dir.
+func write(t *testing.T, dir, name, content string, perm os.FileMode) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read returns the contents of a file in dir.
+func read(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestRun validates the playground links are checked and updated.
+func TestRun(t *testing.T) {
+	const (
+		code1 = "package main\n\nfunc main() {}\n"
+		code2 = "package main\n\nfunc main() { println() }\n"
+		md    = "# Examples\n\n" +
+			"[Declare](example1/example1.go) ([Go Playground](https://play.golang.org/p/old))\n" +
+			"[Use](example2/example2.go) ([Go Playground]())\n" +
+			"[Not code](notes.txt)\n"
+	)
+
+	p := newFakePlayground(t)
+	dir := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "cache", "links.json")
+
+	write(t, dir, "README.md", md, 0640)
+	write(t, dir, "example1/example1.go", code1, 0644)
+	write(t, dir, "example2/example2.go", code1, 0644)
+
+	mpl := func(flags ...string) (int, string) {
+		var stderr bytes.Buffer
+		args := append([]string{"-endpoint", p.URL + "/share", "-cache", cachePath}, flags...)
+		status := run(append(args, filepath.Join(dir, "README.md")), &stderr)
+		return status, stderr.String()
+	}
+
+	want := "# Examples\n\n" +
+		"[Declare](example1/example1.go) ([Go Playground](" + p.link(code1) + "))\n" +
+		"[Use](example2/example2.go) ([Go Playground](" + p.link(code1) + "))\n" +
+		"[Not code](notes.txt)\n"
+
+	t.Log("Given the need to keep the playground links up to date.")
+	{
+		t.Log("\tTest 0:\tWhen checking links that aren't cached.")
+		{
+			if status, out := mpl("-check"); status != 1 || !strings.Contains(out, "STALE") || !strings.Contains(out, "no cached playground link") {
+				t.Fatalf("\t%s\tTest 0:\tShould report the links as stale : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 0:\tShould report the links as stale.", succeed)
+
+			if got := read(t, dir, "README.md"); got != md {
+				t.Fatalf("\t%s\tTest 0:\tShould not change the file : got\n%s", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not change the file.", succeed)
+
+			if n := p.posts(); n != 0 {
+				t.Fatalf("\t%s\tTest 0:\tShould not share any code : shared %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not share any code.", succeed)
+
+			if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+				t.Fatalf("\t%s\tTest 0:\tShould not write the cache : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not write the cache.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen updating the links.")
+		{
+			if status, out := mpl(); status != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould exit with status 0 : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 1:\tShould exit with status 0.", succeed)
+
+			if got := read(t, dir, "README.md"); got != want {
+				t.Fatalf("\t%s\tTest 1:\tShould write the new links : got\n%s", failed, got)
+			}
+			t.Logf("\t%s\tTest 1:\tShould write the new links.", succeed)
+
+			if n := p.posts(); n != 1 {
+				t.Fatalf("\t%s\tTest 1:\tShould share the same code once : shared %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 1:\tShould share the same code once.", succeed)
+
+			info, err := os.Stat(filepath.Join(dir, "README.md"))
+			if err != nil || info.Mode().Perm() != 0640 {
+				t.Fatalf("\t%s\tTest 1:\tShould keep the file mode : got %v err[%v]", failed, info.Mode().Perm(), err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould keep the file mode.", succeed)
+
+			if status, out := mpl("-check"); status != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould pass a check afterwards : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 1:\tShould pass a check afterwards.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen a dry run finds a changed example.")
+		{
+			write(t, dir, "example2/example2.go", code2, 0644)
+
+			if status, out := mpl("-dry-run"); status != 0 || !strings.Contains(out, "[Use](example2/example2.go) has no cached playground link") {
+				t.Fatalf("\t%s\tTest 2:\tShould report the stale link : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 2:\tShould report the stale link.", succeed)
+
+			if got := read(t, dir, "README.md"); got != want {
+				t.Fatalf("\t%s\tTest 2:\tShould not change the file : got\n%s", failed, got)
+			}
+			t.Logf("\t%s\tTest 2:\tShould not change the file.", succeed)
+
+			if n := p.posts(); n != 0 {
+				t.Fatalf("\t%s\tTest 2:\tShould not share any code : shared %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 2:\tShould not share any code.", succeed)
+		}
+
+		t.Log("\tTest 3:\tWhen a dry run finds code that is already cached.")
+		{
+			if status, out := mpl(); status != 0 {
+				t.Fatalf("\t%s\tTest 3:\tShould share the changed example : got %d\n%s", failed, status, out)
+			}
+			if n := p.posts(); n != 1 {
+				t.Fatalf("\t%s\tTest 3:\tShould share the changed example : shared %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 3:\tShould share the changed example.", succeed)
+
+			write(t, dir, "example2/example2.go", code1, 0644)
+			if status, out := mpl("-dry-run"); status != 0 || !strings.Contains(out, "WOULD UPDATE") || !strings.Contains(out, p.link(code1)) {
+				t.Fatalf("\t%s\tTest 3:\tShould report the cached link : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 3:\tShould report the cached link.", succeed)
+
+			if status, out := mpl(); status != 0 || read(t, dir, "README.md") != want {
+				t.Fatalf("\t%s\tTest 3:\tShould update back to the cached link : got %d\n%s", failed, status, out)
+			}
+			if n := p.posts(); n != 0 {
+				t.Fatalf("\t%s\tTest 3:\tShould not share cached code : shared %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 3:\tShould update back to the cached link.", succeed)
+		}
+
+		t.Log("\tTest 4:\tWhen the playground fails.")
+		{
+			write(t, dir, "example1/example1.go", code2+"\n", 0644)
+			p.mu.Lock()
+			p.fail = true
+			p.mu.Unlock()
+
+			if status, out := mpl(); status != 1 || !strings.Contains(out, "ERROR") {
+				t.Fatalf("\t%s\tTest 4:\tShould report the error : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 4:\tShould report the error.", succeed)
+
+			if got := read(t, dir, "README.md"); got != want {
+				t.Fatalf("\t%s\tTest 4:\tShould not change the file : got\n%s", failed, got)
+			}
+			t.Logf("\t%s\tTest 4:\tShould not change the file.", succeed)
+
+			if tmp, _ := filepath.Glob(filepath.Join(dir, ".*.tmp-*")); len(tmp) != 0 {
+				t.Fatalf("\t%s\tTest 4:\tShould not leave temp files behind : %v", failed, tmp)
+			}
+			t.Logf("\t%s\tTest 4:\tShould not leave temp files behind.", succeed)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/ardanlabs/gotraining/tools/internal/tree"
+)
+
+// Set of directives an example can use in a comment to change how it's
+// run. They're written like //go:generate, without a space.
+//
+//	//runexamples:skip needs a network connection
+//	//runexamples:filter \d+ requests => N requests
+//	//runexamples:stderr
+const (
+	directiveSkip   = "//runexamples:skip"
+	directiveFilter = "//runexamples:filter"
+	directiveStderr = "//runexamples:stderr"
+)
+
+// example is a main package that can be run.
+type example struct {
+	dir      string
+	golden   string   // File of the expected output, next to the source with main.
+	skip     string   // Why the example isn't run, if it isn't.
+	filters  []string // Filters from directives.
+	stderr   bool     // Check stderr too, for examples that use println.
+	parseErr error    // Directives that couldn't be understood.
+}
+
+// discover finds the main packages under the roots. Directories that hold
+// no main package are ignored.
+func discover(ctx build.Context, roots []string) ([]example, error) {
+	var examples []example
+
+	walk := func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if tree.Skip(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		ex, found := inspect(ctx, path)
+		if found {
+			examples = append(examples, ex)
+		}
+		return nil
+	}
+
+	for _, root := range roots {
+		if err := filepath.WalkDir(root, walk); err != nil {
+			return nil, err
+		}
+	}
+
+	return examples, nil
+}
+
+// inspect reports if the directory holds a main package with func main, and
+// if it does how to run it.
+func inspect(ctx build.Context, dir string) (example, bool) {
+	ex := example{dir: dir}
+
+	pkg, err := ctx.ImportDir(dir, 0)
+	var noGo *build.NoGoError
+	switch {
+	case errors.As(err, &noGo) && pkg != nil && hasMain(dir, pkg.IgnoredGoFiles):
+
+		// Every file has a build constraint this build doesn't satisfy,
+		// like //go:build windows.
+		ex.skip = "excluded by build constraints"
+		return ex, true
+
+	case err != nil || pkg.Name != "main":
+		return ex, false
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range pkg.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+
+			// Leave it for the build to report.
+			ex.golden = filepath.Join(dir, filepath.Base(dir)+".golden")
+			continue
+		}
+
+		if declaresMain(f) {
+			ex.golden = filepath.Join(dir, strings.TrimSuffix(name, ".go")+".golden")
+		}
+
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				switch {
+				case strings.HasPrefix(c.Text, directiveSkip):
+					ex.skip = strings.TrimSpace(strings.TrimPrefix(c.Text, directiveSkip))
+					if ex.skip == "" {
+						ex.skip = "skipped by directive"
+					}
+
+				case c.Text == directiveStderr:
+					ex.stderr = true
+
+				case strings.HasPrefix(c.Text, directiveFilter):
+					s := strings.TrimSpace(strings.TrimPrefix(c.Text, directiveFilter))
+					if _, err := parseFilter(s); err != nil && ex.parseErr == nil {
+						ex.parseErr = err
+					}
+					ex.filters = append(ex.filters, s)
+				}
+			}
+		}
+	}
+
+	// Files of package main without func main are helpers, like the
+	// generators of go:generate.
+	return ex, ex.golden != ""
+}
+
+// hasMain reports if any of the files is in package main.
+func hasMain(dir string, files []string) bool {
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if err == nil && f.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// declaresMain reports if the file declares func main.
+func declaresMain(f *ast.File) bool {
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == "main" {
+			return true
+		}
+	}
+	return false
+}
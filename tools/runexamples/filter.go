@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// filter rewrites output that changes from run to run, like addresses and
+// timestamps, so it can be compared with a golden file.
+type filter struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// defaultFilters normalize what most examples print differently each run.
+var defaultFilters = []string{
+
+	// 0xc000012345 pointers and addresses.
+	`0x[0-9a-f]{6,} => 0xADDR`,
+
+	// 2009-11-10 23:00:00.123 +0000 UTC m=+0.000000001 and 2009-11-10T23:00:00Z
+	`\d{4}[-/]\d{2}[-/]\d{2}[ T]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[ ]?[+-]\d{2}:?\d{2})?( [A-Z]{3,4})?( m=[+-]\d+\.\d+)? => TIME`,
+
+	// 15:04:05.000000 from the log package.
+	`\b\d{2}:\d{2}:\d{2}(\.\d+)?\b => CLOCK`,
+
+	// 1.234ms, 250µs and 1.5s
+	`\b\d+(\.\d+)?(ns|µs|us|ms)\b => DURATION`,
+	`\b\d+\.\d+s\b => DURATION`,
+
+	// goroutine 18 [running]:
+	`goroutine \d+ => goroutine N`,
+}
+
+// parseFilter parses a filter written like "regexp => replacement". The
+// replacement can refer to groups in the regexp like $1.
+func parseFilter(s string) (filter, error) {
+	expr, repl, found := strings.Cut(s, " => ")
+	if !found {
+		return filter{}, fmt.Errorf("filter %q: want \"regexp => replacement\"", s)
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return filter{}, fmt.Errorf("filter %q: %w", s, err)
+	}
+
+	return filter{re: re, repl: repl}, nil
+}
+
+// parseFilters parses a list of filters.
+func parseFilters(ss []string) ([]filter, error) {
+	fs := make([]filter, 0, len(ss))
+	for _, s := range ss {
+		f, err := parseFilter(s)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// normalize applies the filters to the output in order.
+func normalize(output string, filters []filter) string {
+	for _, f := range filters {
+		output = f.re.ReplaceAllString(output, f.repl)
+	}
+	return output
+}
+
+// filterFlag collects -filter flags.
+type filterFlag []string
+
+// String implements the flag.Value interface.
+func (f *filterFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+// Set implements the flag.Value interface.
+func (f *filterFlag) Set(s string) error {
+	if _, err := parseFilter(s); err != nil {
+		return err
+	}
+	*f = append(*f, s)
+	return nil
+}
@@ -0,0 +1,163 @@
+// Package main provides a CLI tool that runs every example program in the
+// training material and compares what it prints with a golden file kept next
+// to its source, so examples that stop working or change their output are
+// noticed.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go/build"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the examples under the directories named in the arguments and
+// returns the exit status: 1 if any failed and 2 if the tool couldn't run.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	var filters filterFlag
+
+	// Parse the flags from the command line call.
+	fs := flag.NewFlagSet("runexamples", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	update := fs.Bool("update", false, "rewrite the golden files with what the examples print")
+	all := fs.Bool("all", false, "also run examples without golden files to check they build and exit")
+	timeout := fs.Duration("timeout", 10*time.Second, "time each example can run")
+	workers := fs.Int("j", runtime.NumCPU(), "examples to run at once")
+	tags := fs.String("tags", "", "comma separated build tags")
+	match := fs.String("run", "", "only run examples whose directory matches this regexp")
+	noDefaults := fs.Bool("no-default-filters", false, "don't normalize addresses, times and durations")
+	verbose := fs.Bool("v", false, "report examples that pass or are skipped")
+	fs.Var(&filters, "filter", `normalize output with "regexp => replacement", can be repeated`)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: runexamples [options] [dir...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	roots := fs.Args()
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	var matchRE *regexp.Regexp
+	if *match != "" {
+		var err error
+		if matchRE, err = regexp.Compile(*match); err != nil {
+			fmt.Fprintln(stderr, "runexamples:", err)
+			return 2
+		}
+	}
+
+	exprs := []string(filters)
+	if !*noDefaults {
+		exprs = append(defaultFilters[:len(defaultFilters):len(defaultFilters)], exprs...)
+	}
+	parsed, err := parseFilters(exprs)
+	if err != nil {
+		fmt.Fprintln(stderr, "runexamples:", err)
+		return 2
+	}
+
+	// Find the examples the build tags allow.
+	bctx := build.Default
+	if *tags != "" {
+		bctx.BuildTags = strings.Split(*tags, ",")
+	}
+
+	examples, err := discover(bctx, roots)
+	if err != nil {
+		fmt.Fprintln(stderr, "runexamples:", err)
+		return 2
+	}
+	if matchRE != nil {
+		var matched []example
+		for _, ex := range examples {
+			if matchRE.MatchString(ex.dir) {
+				matched = append(matched, ex)
+			}
+		}
+		examples = matched
+	}
+
+	binDir, err := os.MkdirTemp("", "runexamples-")
+	if err != nil {
+		fmt.Fprintln(stderr, "runexamples:", err)
+		return 2
+	}
+	defer os.RemoveAll(binDir)
+
+	r := runner{tags: *tags, timeout: *timeout, filters: parsed, update: *update, all: *all, binDir: binDir}
+
+	// Run the examples in parallel but report them in order.
+	results := make([]result, len(examples))
+	sem := make(chan struct{}, max(*workers, 1))
+	var wg sync.WaitGroup
+	for i, ex := range examples {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = r.run(ctx, i, ex)
+		})
+	}
+	wg.Wait()
+
+	counts := make(map[string]int)
+	for _, res := range results {
+		counts[res.status]++
+		report(stdout, res, *verbose)
+	}
+
+	fmt.Fprintf(stderr, "%d passed, %d failed, %d skipped, %d without golden files", counts[statusOK], counts[statusFail], counts[statusSkip], counts[statusNoGolden])
+	if *update {
+		fmt.Fprintf(stderr, ", %d updated", counts[statusUpdated])
+	}
+	fmt.Fprintln(stderr)
+
+	if counts[statusFail] > 0 {
+		return 1
+	}
+	return 0
+}
+
+// report prints the result of an example the way go test does.
+func report(w io.Writer, res result, verbose bool) {
+	switch res.status {
+	case statusFail:
+		fmt.Fprintf(w, "%s\t%s\t%.2fs\n", res.status, res.ex.dir, res.elapsed.Seconds())
+		for line := range strings.Lines(res.detail) {
+			fmt.Fprintf(w, "\t%s", line)
+		}
+		if !strings.HasSuffix(res.detail, "\n") {
+			fmt.Fprintln(w)
+		}
+
+	case statusOK, statusUpdated:
+		if verbose || res.status == statusUpdated {
+			fmt.Fprintf(w, "%s\t%s\t%.2fs\n", res.status, res.ex.dir, res.elapsed.Seconds())
+		}
+
+	default:
+		if verbose {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", res.status, res.ex.dir, res.detail)
+		}
+	}
+}
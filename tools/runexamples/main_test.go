@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/tools/internal/treetest"
+)
+
+const (
+	succeed = "\u2713"
+	failed  = "\u2717"
+)
+
+// module writes a module of examples under a temporary directory and
+// returns it.
+func module(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	files["go.mod"] = "module example.com/examples\n\ngo 1.26\n"
+	return treetest.Write(t, files)
+}
+
+// runexamples runs the command in dir and returns its status and output.
+func runexamples(t *testing.T, dir string, args ...string) (int, string) {
+	t.Helper()
+
+	t.Chdir(dir)
+
+	var stdout, stderr bytes.Buffer
+	status := run(context.Background(), args, &stdout, &stderr)
+	return status, stdout.String() + stderr.String()
+}
+
+// TestRun validates the examples are run and compared to their golden files.
+func TestRun(t *testing.T) {
+	dir := module(t, map[string]string{
+		"hello/hello.go": `package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func main() {
+	x := 10
+	fmt.Println("hello", &x)
+	fmt.Println("at", time.Now())
+}
+`,
+		"hello/hello.golden": "hello 0xADDR\nat TIME\n",
+
+		"changed/changed.go":     "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"new\") }\n",
+		"changed/changed.golden": "old\n",
+
+		"panics/panics.go":     "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"before\")\n\tpanic(\"boom\")\n}\n",
+		"panics/panics.golden": "before\nexit status 2\n",
+
+		"stderr/stderr.go":     "package main\n\n//runexamples:stderr\n\nfunc main() { println(\"to stderr\") }\n",
+		"stderr/stderr.golden": "to stderr\n",
+
+		"filtered/filtered.go":     "package main\n\nimport \"fmt\"\n\n//runexamples:filter \\d+ requests => N requests\n\nfunc main() { fmt.Println(\"served 42 requests\") }\n",
+		"filtered/filtered.golden": "served N requests\n",
+
+		"slow/slow.go":     "package main\n\nimport \"time\"\n\nfunc main() { time.Sleep(time.Minute) }\n",
+		"slow/slow.golden": "",
+
+		"network/network.go":     "// This example listens on port 3000.\npackage main\n\n//runexamples:skip needs a network connection\n\nfunc main() { select {} }\n",
+		"network/network.golden": "",
+
+		"windows/windows.go": "//go:build windows\n\npackage main\n\nfunc main() {}\n",
+
+		"template/template.go": "package main\n\nfunc main() { undefined() }\n",
+
+		"lib/lib.go":         "package lib\n",
+		"testdata/td/td.go":  "package main\n\nfunc main() {}\n",
+		"main/sub/helper.go": "package main\n\nfunc helper() {}\n",
+	})
+
+	tt := []struct {
+		name string
+		exp  string
+	}{
+		{"hello", "ok\thello\t"},
+		{"changed", "FAIL\tchanged\t"},
+		{"changed diff", "\t+new\n"},
+		{"panics", "ok\tpanics\t"},
+		{"stderr", "ok\tstderr\t"},
+		{"filtered", "ok\tfiltered\t"},
+		{"slow", "FAIL\tslow\t"},
+		{"slow timeout", "\ttimed out after 1s\n"},
+		{"network", "skip\tnetwork\tneeds a network connection\n"},
+		{"windows", "skip\twindows\texcluded by build constraints\n"},
+		{"template", "nogolden\ttemplate\t"},
+		{"summary", "4 passed, 2 failed, 2 skipped, 1 without golden files\n"},
+	}
+
+	t.Log("Given the need to run the examples and check their output.")
+	{
+		status, out := runexamples(t, dir, "-v", "-timeout", "1s")
+
+		t.Log("\tTest 0:\tWhen some of the examples fail.")
+		{
+			if status != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould exit with status 1 : got %d", failed, status)
+			}
+			t.Logf("\t%s\tTest 0:\tShould exit with status 1.", succeed)
+
+			for _, notExample := range []string{"lib", "testdata", "main/sub"} {
+				if strings.Contains(out, "\t"+notExample) {
+					t.Fatalf("\t%s\tTest 0:\tShould not run %s :\n%s", failed, notExample, out)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould only run the main packages.", succeed)
+		}
+
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen checking %s.", i+1, tst.name)
+			{
+				if !strings.Contains(out, tst.exp) {
+					t.Errorf("\t%s\tTest %d:\tShould report %q :\n%s", failed, i+1, tst.exp, out)
+					continue
+				}
+				t.Logf("\t%s\tTest %d:\tShould report %q.", succeed, i+1, tst.exp)
+			}
+		}
+	}
+}
+
+// TestUpdate validates the golden files are written with -update.
+func TestUpdate(t *testing.T) {
+	dir := module(t, map[string]string{
+		"changed/changed.go":     "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Printf(\"new %#x\\n\", 0xc000012345) }\n",
+		"changed/changed.golden": "old\n",
+		"fresh/main.go":          "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(\"fresh\") }\n",
+		"broken/broken.go":       "package main\n\nfunc main() { undefined() }\n",
+	})
+
+	t.Log("Given the need to update the golden files.")
+	{
+		t.Log("\tTest 0:\tWhen updating the examples that match -run.")
+		{
+			if status, out := runexamples(t, dir, "-update", "-run", "changed|fresh"); status != 0 {
+				t.Fatalf("\t%s\tTest 0:\tShould exit with status 0 : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 0:\tShould exit with status 0.", succeed)
+
+			for name, exp := range map[string]string{"changed/changed.golden": "new 0xADDR\n", "fresh/main.golden": "fresh\n"} {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil || string(got) != exp {
+					t.Fatalf("\t%s\tTest 0:\tShould write %s : got %q err[%v]", failed, name, got, err)
+				}
+				t.Logf("\t%s\tTest 0:\tShould write %s.", succeed, name)
+			}
+		}
+
+		t.Log("\tTest 1:\tWhen running after the update.")
+		{
+			if status, out := runexamples(t, dir); status != 0 || !strings.Contains(out, "2 passed, 0 failed") {
+				t.Fatalf("\t%s\tTest 1:\tShould pass : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 1:\tShould pass.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen running all the examples.")
+		{
+			if status, out := runexamples(t, dir, "-all"); status != 1 || !strings.Contains(out, "FAIL\tbroken\t") {
+				t.Fatalf("\t%s\tTest 2:\tShould fail the broken example : got %d\n%s", failed, status, out)
+			}
+			t.Logf("\t%s\tTest 2:\tShould fail the broken example.", succeed)
+		}
+	}
+}
+
+// TestInterrupt validates an interrupted run isn't reported as a timeout.
+func TestInterrupt(t *testing.T) {
+	dir := module(t, map[string]string{
+		"slow/slow.go": "package main\n\nimport \"time\"\n\nfunc main() { time.Sleep(time.Minute) }\n",
+	})
+
+	bin := filepath.Join(t.TempDir(), "slow")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	build.Dir = filepath.Join(dir, "slow")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("build: %v\n%s", err, out)
+	}
+
+	t.Log("Given the need to stop the examples when interrupted.")
+	{
+		t.Log("\tTest 0:\tWhen the run is cancelled before the example times out.")
+		{
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(100*time.Millisecond, cancel)
+
+			r := runner{timeout: time.Minute}
+			_, _, err := r.exec(ctx, example{dir: build.Dir}, bin)
+			if !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "timed out") {
+				t.Fatalf("\t%s\tTest 0:\tShould report an interruption and not a timeout : got %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould report an interruption and not a timeout.", succeed)
+		}
+	}
+}
+
+// TestFilters validates the output is normalized by the filters.
+func TestFilters(t *testing.T) {
+	filters, err := parseFilters(defaultFilters)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tt := []struct {
+		name string
+		in   string
+		exp  string
+	}{
+		{"address", "p=0xc000012345 &{1}", "p=0xADDR &{1}"},
+		{"small hex", "0xff", "0xff"},
+		{"time", "2009-11-10 23:00:00.123456 +0000 UTC m=+0.000012345", "TIME"},
+		{"rfc3339", "at 2009-11-10T23:00:00Z.", "at TIME."},
+		{"log", "2009/11/10 23:00:00 starting", "TIME starting"},
+		{"clock", "15:04:05.000123 done", "CLOCK done"},
+		{"duration", "took 1.234ms and 250µs and 1.5s", "took DURATION and DURATION and DURATION"},
+		{"whole seconds", "wait 5s", "wait 5s"},
+		{"goroutine", "goroutine 18 [running]:", "goroutine N [running]:"},
+	}
+
+	t.Log("Given the need to normalize output that changes between runs.")
+	{
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen filtering a %s.", i, tst.name)
+			{
+				if got := normalize(tst.in, filters); got != tst.exp {
+					t.Errorf("\t%s\tTest %d:\tShould get %q : got %q", failed, i, tst.exp, got)
+					continue
+				}
+				t.Logf("\t%s\tTest %d:\tShould get %q.", succeed, i, tst.exp)
+			}
+		}
+	}
+
+	t.Log("Given the need to parse the filter directives.")
+	{
+		t.Log("\tTest 0:\tWhen the filter has no =>.")
+		{
+			if _, err := parseFilter("no arrow"); err == nil {
+				t.Fatalf("\t%s\tTest 0:\tShould return an error.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould return an error.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the filter has a bad regexp.")
+		{
+			if _, err := parseFilter("( => x"); err == nil {
+				t.Fatalf("\t%s\tTest 1:\tShould return an error.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould return an error.", succeed)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Set of outcomes of running an example.
+const (
+	statusOK       = "ok"
+	statusFail     = "FAIL"
+	statusSkip     = "skip"
+	statusNoGolden = "nogolden"
+	statusUpdated  = "updated"
+)
+
+// result is the outcome of running an example.
+type result struct {
+	ex      example
+	status  string
+	detail  string // Why it failed or was skipped, or the diff.
+	elapsed time.Duration
+}
+
+// runner builds and runs examples.
+type runner struct {
+	tags    string
+	timeout time.Duration
+	filters []filter
+	update  bool
+	all     bool   // Run examples without golden files too.
+	binDir  string // Where the programs are built.
+}
+
+// run builds and runs one example and compares what it printed with its
+// golden file. The n is used to name the program.
+func (r *runner) run(ctx context.Context, n int, ex example) result {
+	start := time.Now()
+	res := func(status, format string, args ...any) result {
+		return result{ex: ex, status: status, detail: fmt.Sprintf(format, args...), elapsed: time.Since(start)}
+	}
+
+	// Examples opt in to being checked by having a golden file.
+	_, err := os.Stat(ex.golden)
+	noGolden := errors.Is(err, os.ErrNotExist)
+
+	switch {
+	case ex.skip != "":
+		return res(statusSkip, "%s", ex.skip)
+	case ex.parseErr != nil:
+		return res(statusFail, "%v", ex.parseErr)
+	case noGolden && !r.update && !r.all:
+		return res(statusNoGolden, "run with -update to create %s", ex.golden)
+	}
+
+	bin, err := filepath.Abs(filepath.Join(r.binDir, fmt.Sprintf("example%d", n)))
+	if err != nil {
+		return res(statusFail, "%v", err)
+	}
+
+	build := exec.CommandContext(ctx, "go", "build", "-tags", r.tags, "-o", bin, ".")
+	build.Dir = ex.dir
+	if out, err := build.CombinedOutput(); err != nil {
+		return res(statusFail, "build failed: %v\n%s", err, out)
+	}
+	defer os.Remove(bin)
+
+	output, stderr, err := r.exec(ctx, ex, bin)
+	if err != nil {
+		return res(statusFail, "%v\n%s", err, stderr)
+	}
+
+	exFilters, err := parseFilters(ex.filters)
+	if err != nil {
+		return res(statusFail, "%v", err)
+	}
+	output = normalize(normalize(output, r.filters), exFilters)
+
+	if r.update {
+		if err := os.WriteFile(ex.golden, []byte(output), 0644); err != nil {
+			return res(statusFail, "%v", err)
+		}
+		return res(statusUpdated, "")
+	}
+
+	if noGolden {
+		return res(statusNoGolden, "ran, run with -update to create %s", ex.golden)
+	}
+
+	want, err := os.ReadFile(ex.golden)
+	switch {
+	case err != nil:
+		return res(statusFail, "%v", err)
+	case string(want) != output:
+		return res(statusFail, "%s%s", diff(ex.golden, string(want), output), stderr)
+	}
+
+	return res(statusOK, "")
+}
+
+// exec runs the program in the example's directory with nothing on stdin.
+// It returns what was printed to stdout followed by the exit status, if it
+// wasn't zero, so examples that panic on purpose can still be checked. When
+// the example asks, stderr is included in the output in the order written.
+func (r *runner) exec(ctx context.Context, ex example, bin string) (string, string, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(timeoutCtx, bin)
+	cmd.Dir = ex.dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+	if ex.stderr {
+		cmd.Stderr = &stdout
+	}
+
+	// Only the example's own deadline is a timeout. The tool being
+	// interrupted stops the example too.
+	err := cmd.Run()
+	switch {
+	case ctx.Err() != nil:
+		return "", stderr.String(), fmt.Errorf("interrupted: %w", context.Cause(ctx))
+	case timeoutCtx.Err() != nil:
+		return "", stderr.String(), fmt.Errorf("timed out after %s", r.timeout)
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		fmt.Fprintf(&stdout, "exit status %d\n", exitErr.ExitCode())
+	case err != nil:
+		return "", stderr.String(), err
+	}
+
+	return stdout.String(), stderr.String(), nil
+}
+
+// diff returns a unified diff of the golden file and the output.
+func diff(golden, want, got string) string {
+	d, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        lines(want),
+		B:        lines(got),
+		FromFile: golden,
+		ToFile:   "output",
+		Context:  2,
+	})
+	if err != nil {
+		return err.Error()
+	}
+	return strings.TrimSuffix(d, "\n") + "\n"
+}
+
+// lines splits text into lines that keep their newlines.
+func lines(s string) []string {
+	return slices.Collect(strings.Lines(s))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/ardanlabs/gotraining/topics/go/algorithms/fun/barber/shop"
 )
 
+//runexamples:skip waits for a signal to shut down
 func main() {
 	const maxChairs = 10
 	s := shop.Open(maxChairs)
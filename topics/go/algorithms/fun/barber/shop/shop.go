@@ -18,10 +18,14 @@ package shop
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"os"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
 )
 
 var (
@@ -37,20 +41,60 @@ type customer struct {
 	name string
 }
 
+// Option allows the shop to be configured when it is opened.
+type Option func(*Shop)
+
+// WithClock sets the clock the barber uses to time a haircut. Tests can
+// provide a fake clock so haircuts don't take real time.
+func WithClock(c clock.Clock) Option {
+	return func(s *Shop) {
+		s.clock = c
+	}
+}
+
+// WithHaircut sets how long each haircut takes, which is random up to half a
+// second by default. Tests can provide fixed times so they're repeatable.
+func WithHaircut(fn func() time.Duration) Option {
+	return func(s *Shop) {
+		s.haircut = fn
+	}
+}
+
+// WithOutput sets where the shop reports what is happening.
+func WithOutput(w io.Writer) Option {
+	return func(s *Shop) {
+		s.out = w
+	}
+}
+
 // Shop represents the barber's shop which contains chairs for customers
 // that customers can occupy and the barber can service. The shop can
 // be closed for business.
 type Shop struct {
-	open    int32          // Determines if the shop is open for business.
-	chairs  chan customer  // The set of chairs in the shop.
-	wgClose sync.WaitGroup // Provides support for closing the shop.
-	wgEnter sync.WaitGroup // Tracks customers entering the shop.
+	open    int32                // Determines if the shop is open for business.
+	chairs  chan customer        // The set of chairs in the shop.
+	wgClose sync.WaitGroup       // Provides support for closing the shop.
+	wgEnter sync.WaitGroup       // Tracks customers entering the shop.
+	clock   clock.Clock          // Used by the barber to time a haircut.
+	haircut func() time.Duration // How long the next haircut takes.
+	out     io.Writer            // Where the shop reports what is happening.
+}
+
+// randomHaircut takes up to half a second.
+func randomHaircut() time.Duration {
+	return time.Duration(rand.Intn(500)) * time.Millisecond
 }
 
 // Open creates a new shop for business and gets the barber working.
-func Open(maxChairs int) *Shop {
+func Open(maxChairs int, opts ...Option) *Shop {
 	s := Shop{
-		chairs: make(chan customer, maxChairs),
+		chairs:  make(chan customer, maxChairs),
+		clock:   clock.Real(),
+		haircut: randomHaircut,
+		out:     os.Stdout,
+	}
+	for _, opt := range opts {
+		opt(&s)
 	}
 	atomic.StoreInt32(&s.open, 1)
 
@@ -59,9 +103,9 @@ func Open(maxChairs int) *Shop {
 	go func() {
 		defer s.wgClose.Done()
 		for cust := range s.chairs {
-			fmt.Printf("Barber servicing customer %q\n", cust.name)
-			time.Sleep(time.Duration(rand.Intn(500)) * time.Millisecond)
-			fmt.Printf("Barber finished  customer %q\n", cust.name)
+			fmt.Fprintf(s.out, "Barber servicing customer %q\n", cust.name)
+			s.clock.Sleep(s.haircut())
+			fmt.Fprintf(s.out, "Barber finished  customer %q\n", cust.name)
 		}
 	}()
 
@@ -97,7 +141,7 @@ func (s *Shop) EnterCustomer(name string) error {
 		select {
 		case s.chairs <- customer{name: name}:
 		default:
-			fmt.Printf("No chair for customer %q\n", name)
+			fmt.Fprintf(s.out, "No chair for customer %q\n", name)
 		}
 	}()
 
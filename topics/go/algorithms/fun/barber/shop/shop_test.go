@@ -0,0 +1,144 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package shop_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/fun/barber/shop"
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+// output provides a goroutine safe buffer for capturing what the shop
+// reports.
+type output struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	buf  bytes.Buffer
+}
+
+func newOutput() *output {
+	var o output
+	o.cond = sync.NewCond(&o.mu)
+	return &o
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	defer o.cond.Broadcast()
+	return o.buf.Write(p)
+}
+
+func (o *output) count(s string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return strings.Count(o.buf.String(), s)
+}
+
+// wait blocks until the shop has reported s.
+func (o *output) wait(s string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for !strings.Contains(o.buf.String(), s) {
+		o.cond.Wait()
+	}
+}
+
+// haircut is how long every haircut takes in the tests.
+const haircut = 100 * time.Millisecond
+
+// fixedHaircut makes every haircut take the same time.
+func fixedHaircut() time.Duration {
+	return haircut
+}
+
+// closeShop closes the shop while advancing the clock so the barber can
+// finish the specified number of haircuts. The clock is only advanced once
+// the barber is sleeping on it.
+func closeShop(s *shop.Shop, f *clock.Fake, haircuts int) {
+	closed := make(chan struct{})
+	go func() {
+		s.Close()
+		close(closed)
+	}()
+
+	for range haircuts {
+		f.BlockUntil(1)
+		f.Advance(haircut)
+	}
+
+	<-closed
+}
+
+// TestShop validates the barber services customers in virtual time.
+func TestShop(t *testing.T) {
+	t.Log("Given the need to service customers in the barber shop.")
+	{
+		t.Log("\tTest 0:\tWhen there are enough chairs for every customer.")
+		{
+			out := newOutput()
+			f := clock.NewFake(time.Now())
+			s := shop.Open(3, shop.WithClock(f), shop.WithHaircut(fixedHaircut), shop.WithOutput(out))
+
+			for _, name := range []string{"bill", "jill", "lisa"} {
+				if err := s.EnterCustomer(name); err != nil {
+					t.Fatalf("\t%s\tTest 0:\tShould be able to enter the shop : %v", failed, err)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould be able to enter the shop.", succeed)
+
+			closeShop(s, f, 3)
+
+			if n := out.count("Barber finished"); n != 3 {
+				t.Fatalf("\t%s\tTest 0:\tShould service 3 customers : %d\n%s", failed, n, out.buf.String())
+			}
+			t.Logf("\t%s\tTest 0:\tShould service 3 customers.", succeed)
+
+			if err := s.EnterCustomer("late"); !errors.Is(err, shop.ErrShopClosed) {
+				t.Fatalf("\t%s\tTest 0:\tShould turn away customers once closed : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould turn away customers once closed.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen there are more customers than chairs.")
+		{
+			out := newOutput()
+			f := clock.NewFake(time.Now())
+			s := shop.Open(1, shop.WithClock(f), shop.WithHaircut(fixedHaircut), shop.WithOutput(out))
+
+			s.EnterCustomer("bill")
+
+			// Once the barber is cutting bill's hair the waiting chair
+			// is empty, so only one of the next two customers can sit.
+			f.BlockUntil(1)
+			s.EnterCustomer("jill")
+			s.EnterCustomer("lisa")
+
+			// Time doesn't move until we say so, which means one of the
+			// customers must be turned away.
+			out.wait("No chair")
+
+			closeShop(s, f, 2)
+
+			if n := out.count("Barber finished"); n != 2 {
+				t.Fatalf("\t%s\tTest 1:\tShould service 2 customers : %d\n%s", failed, n, out.buf.String())
+			}
+			t.Logf("\t%s\tTest 1:\tShould service 2 customers.", succeed)
+
+			if n := out.count("No chair"); n != 1 {
+				t.Fatalf("\t%s\tTest 1:\tShould turn away 1 customer : %d\n%s", failed, n, out.buf.String())
+			}
+			t.Logf("\t%s\tTest 1:\tShould turn away 1 customer.", succeed)
+		}
+	}
+}
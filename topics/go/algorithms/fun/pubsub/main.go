@@ -8,12 +8,15 @@ import (
 	"os"
 	"os/signal"
 	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
 )
 
+//runexamples:skip waits for a signal to shut down
 func main() {
 	clients := NewClients()
 
-	publisher := NewPublisher(clients)
+	publisher := NewPublisher(clients, clock.Real())
 	defer publisher.Shutdown()
 
 	clients.Add("1")
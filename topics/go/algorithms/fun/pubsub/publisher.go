@@ -3,30 +3,46 @@ package main
 import (
 	"fmt"
 	"log"
+	"sync"
 	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
 )
 
 // Publisher is consuming messages and publishing them.
 type Publisher struct {
-	clients *Clients
+	clients  *Clients
+	shutdown chan struct{}
+	wg       sync.WaitGroup
 }
 
-// NewPublisher connects to the publisher can receives messages.
-func NewPublisher(clients *Clients) *Publisher {
+// NewPublisher connects to the publisher can receives messages. The clock
+// is used to pace the stream of messages.
+func NewPublisher(clients *Clients, clk clock.Clock) *Publisher {
 	pub := Publisher{
-		clients: clients,
+		clients:  clients,
+		shutdown: make(chan struct{}),
 	}
 
-	// This is not production ready since it can't be shutdown.
 	// For now we just want a stream of messages.
-
+	pub.wg.Add(1)
 	go func() {
+		defer pub.wg.Done()
+
+		ticker := clk.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+
 		var counter int
 		for {
-			time.Sleep(100 * time.Millisecond)
-			counter++
-			log.Println("publisher: mesage received : sending to clients")
-			clients.Send(fmt.Sprintf("message %d", counter))
+			select {
+			case <-ticker.C():
+				counter++
+				log.Println("publisher: mesage received : sending to clients")
+				clients.Send(fmt.Sprintf("message %d", counter))
+
+			case <-pub.shutdown:
+				return
+			}
 		}
 	}()
 
@@ -35,6 +51,6 @@ func NewPublisher(clients *Clients) *Publisher {
 
 // Shutdown disconnects the publisher and stop messages.
 func (p *Publisher) Shutdown() {
-
-	// TO BE IMPLEMENTED
+	close(p.shutdown)
+	p.wg.Wait()
 }
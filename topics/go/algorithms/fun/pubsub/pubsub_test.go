@@ -0,0 +1,69 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+// TestPublisher validates messages are delivered to the current clients.
+func TestPublisher(t *testing.T) {
+	t.Log("Given the need to publish messages to clients.")
+	{
+		t.Log("\tTest 0:\tWhen clients come and go between messages.")
+		{
+			f := clock.NewFake(time.Now())
+			clients := NewClients()
+			clients.Add("1")
+			clients.Add("2")
+
+			pub := NewPublisher(clients, f)
+
+			// Wait for the publisher to start its ticker.
+			f.BlockUntil(1)
+
+			ch1 := clients.clients["1"]
+			ch2 := clients.clients["2"]
+
+			f.Advance(100 * time.Millisecond)
+			if m := <-ch1; m != "message 1" {
+				t.Fatalf("\t%s\tTest 0:\tShould deliver the first message to client 1 : %q", failed, m)
+			}
+			if m := <-ch2; m != "message 1" {
+				t.Fatalf("\t%s\tTest 0:\tShould deliver the first message to client 2 : %q", failed, m)
+			}
+			t.Logf("\t%s\tTest 0:\tShould deliver the first message to every client.", succeed)
+
+			clients.Remove("2")
+
+			f.Advance(100 * time.Millisecond)
+			if m := <-ch1; m != "message 2" {
+				t.Fatalf("\t%s\tTest 0:\tShould deliver the second message to client 1 : %q", failed, m)
+			}
+			t.Logf("\t%s\tTest 0:\tShould deliver the second message to client 1.", succeed)
+
+			// Shutdown waits for the publisher goroutine, so any message
+			// sent to client 2 would be in its channel by now.
+			pub.Shutdown()
+
+			select {
+			case m := <-ch2:
+				t.Fatalf("\t%s\tTest 0:\tShould not deliver to a removed client : %q", failed, m)
+			default:
+			}
+			t.Logf("\t%s\tTest 0:\tShould not deliver to a removed client.", succeed)
+		}
+	}
+}
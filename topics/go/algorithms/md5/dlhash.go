@@ -50,6 +50,7 @@ func urlSig(ctx context.Context, url string) (string, error) {
 	return fmt.Sprintf("%x", hash), nil
 }
 
+//runexamples:skip needs a network connection
 func main() {
 	url := "https://storage.googleapis.com/gcp-public-data-landsat/LC08/01/044/034/LC08_L1GT_044034_20130330_20170310_01_T2/LC08_L1GT_044034_20130330_20170310_01_T2_B2.TIF"
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -0,0 +1,169 @@
+package download
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+// hashes maps the name of each supported algorithm to its constructor.
+var hashes = map[string]func() hash.Hash{
+	"crc32c": func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) },
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// Checksum is the expected sum of a file.
+type Checksum struct {
+	Algorithm string // One of crc32c, md5, sha1, sha256 or sha512.
+	Sum       []byte
+}
+
+// ParseChecksum parses a checksum written like "md5:eec1fa5ce8077d7030e194eb5989c937".
+func ParseChecksum(s string) (Checksum, error) {
+	alg, hexSum, _ := strings.Cut(s, ":")
+	sum, err := hex.DecodeString(hexSum)
+	if err != nil {
+		return Checksum{}, fmt.Errorf("checksum %q: %w", s, err)
+	}
+
+	c := Checksum{Algorithm: alg, Sum: sum}
+	if err := c.validate(); err != nil {
+		return Checksum{}, err
+	}
+
+	return c, nil
+}
+
+// String implements the fmt.Stringer interface.
+func (c Checksum) String() string {
+	return fmt.Sprintf("%s:%x", c.Algorithm, c.Sum)
+}
+
+// validate checks the algorithm is known and the sum is the right size.
+func (c Checksum) validate() error {
+	h, exists := hashes[c.Algorithm]
+	switch {
+	case !exists:
+		return fmt.Errorf("checksum %s: unknown algorithm %q", c, c.Algorithm)
+	case len(c.Sum) != h().Size():
+		return fmt.Errorf("checksum %s: wrong size for %s", c, c.Algorithm)
+	}
+	return nil
+}
+
+// digestNames maps the algorithm names used by the Digest and Repr-Digest
+// headers to ours.
+var digestNames = map[string]string{
+	"md5":     "md5",
+	"sha":     "sha1",
+	"sha-256": "sha256",
+	"sha-512": "sha512",
+}
+
+// headerChecksums returns the checksums of the whole file found in the
+// headers of a HEAD response. Values that can't be understood are ignored.
+//
+//	x-goog-hash: crc32c=n03x6A==, md5=7sH6XOgHfXAw4ZTrWYnJNw==
+//	Content-MD5: 7sH6XOgHfXAw4ZTrWYnJNw==
+//	Digest: SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=
+//	Repr-Digest: sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:
+func headerChecksums(h http.Header) []Checksum {
+	var sums []Checksum
+
+	add := func(alg, b64 string) {
+		sum, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return
+		}
+		c := Checksum{Algorithm: alg, Sum: sum}
+		if c.validate() == nil {
+			sums = append(sums, c)
+		}
+	}
+
+	// Each of these headers is a list of name=value pairs.
+	lists := []struct {
+		header string
+		names  map[string]string
+	}{
+		{"X-Goog-Hash", map[string]string{"crc32c": "crc32c", "md5": "md5"}},
+		{"Digest", digestNames},
+		{"Repr-Digest", digestNames},
+	}
+
+	for _, l := range lists {
+		for _, v := range h.Values(l.header) {
+			for item := range strings.SplitSeq(v, ",") {
+				name, value, found := strings.Cut(strings.TrimSpace(item), "=")
+				if alg, known := l.names[strings.ToLower(name)]; found && known {
+					add(alg, strings.Trim(value, ":"))
+				}
+			}
+		}
+	}
+
+	if v := h.Get("Content-MD5"); v != "" {
+		add("md5", strings.TrimSpace(v))
+	}
+
+	return sums
+}
+
+// verify reads the file once and checks it against every checksum.
+func verify(path string, sums []Checksum) error {
+	hs := make(map[string]hash.Hash)
+	var ws []io.Writer
+	for _, c := range sums {
+		if _, exists := hs[c.Algorithm]; !exists {
+			hs[c.Algorithm] = hashes[c.Algorithm]()
+			ws = append(ws, hs[c.Algorithm])
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(io.MultiWriter(ws...), f); err != nil {
+		return err
+	}
+
+	for _, c := range sums {
+		got := Checksum{Algorithm: c.Algorithm, Sum: hs[c.Algorithm].Sum(nil)}
+		if !bytes.Equal(got.Sum, c.Sum) {
+			return fmt.Errorf("%w: want %s, got %s", ErrChecksum, c, got)
+		}
+	}
+
+	return nil
+}
+
+// unique removes repeated checksums, such as the md5 given by both
+// x-goog-hash and Content-MD5.
+func unique(sums []Checksum) []Checksum {
+	var out []Checksum
+	for _, c := range sums {
+		same := func(o Checksum) bool { return o.Algorithm == c.Algorithm && bytes.Equal(o.Sum, c.Sum) }
+		if !slices.ContainsFunc(out, same) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
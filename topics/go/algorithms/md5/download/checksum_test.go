@@ -0,0 +1,64 @@
+package download
+
+import (
+	"net/http"
+	"testing"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestHeaderChecksums(t *testing.T) {
+
+	// The sums of "hello".
+	const (
+		md5Hex    = "md5:5d41402abc4b2a76b9719d911017c592"
+		md5B64    = "XUFAKrxLKna5cZ2REBfFkg=="
+		sha256Hex = "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+		sha256B64 = "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ="
+		crc32cHex = "crc32c:9a71bb4c"
+		crc32cB64 = "mnG7TA=="
+	)
+
+	tt := []struct {
+		name   string
+		header http.Header
+		want   []string
+	}{
+		{"none", http.Header{}, nil},
+		{"x-goog-hash", http.Header{"X-Goog-Hash": {"crc32c=" + crc32cB64 + ", md5=" + md5B64}}, []string{crc32cHex, md5Hex}},
+		{"x-goog-hash repeated", http.Header{"X-Goog-Hash": {"crc32c=" + crc32cB64, "md5=" + md5B64}}, []string{crc32cHex, md5Hex}},
+		{"content-md5", http.Header{"Content-Md5": {md5B64}}, []string{md5Hex}},
+		{"digest", http.Header{"Digest": {"SHA-256=" + sha256B64 + ",MD5=" + md5B64}}, []string{sha256Hex, md5Hex}},
+		{"repr-digest", http.Header{"Repr-Digest": {"sha-256=:" + sha256B64 + ":"}}, []string{sha256Hex}},
+		{"unknown algorithm", http.Header{"Digest": {"UNIXsum=30"}}, nil},
+		{"bad base64", http.Header{"Content-Md5": {"not base64"}}, nil},
+		{"wrong size", http.Header{"Digest": {"MD5=" + sha256B64}}, nil},
+	}
+
+	t.Log("Given the need to find checksums in response headers.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen reading the headers %v.", testID, test.header)
+				{
+					var got []string
+					for _, c := range headerChecksums(test.header) {
+						got = append(got, c.String())
+					}
+
+					if len(got) != len(test.want) {
+						t.Fatalf("\t%s\tTest %d:\tShould have found the checksums : got %v, want %v", failed, testID, got, test.want)
+					}
+					for i := range got {
+						if got[i] != test.want[i] {
+							t.Fatalf("\t%s\tTest %d:\tShould have found the checksums : got %v, want %v", failed, testID, got, test.want)
+						}
+					}
+					t.Logf("\t%s\tTest %d:\tShould have found the checksums.", succeed, testID)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// This program downloads a file, resuming where it left off if it was
+// interrupted, and verifies it against the checksums the server publishes.
+//
+// ./download -segments 4 https://storage.googleapis.com/gcp-public-data-landsat/LC08/01/044/034/LC08_L1GT_044034_20130330_20170310_01_T2/LC08_L1GT_044034_20130330_20170310_01_T2_B2.TIF
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/md5/download"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "download:", err)
+		os.Exit(1)
+	}
+}
+
+// run downloads the url given in the arguments.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("download", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var (
+		out      = fs.String("o", "", "output file (default: the last part of the url)")
+		segments = fs.Int("segments", 4, "number of parallel segments")
+		minSize  = fs.Int64("min-segment", 1<<20, "smallest segment in bytes")
+		retries  = fs.Int("retries", 5, "failed requests in a row before giving up")
+		checksum = fs.String("checksum", "", "expected checksum, like md5:eec1fa5ce8077d7030e194eb5989c937")
+		require  = fs.Bool("require", false, "fail if there's no checksum to verify against")
+		quiet    = fs.Bool("q", false, "don't report progress")
+	)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: download [options] <url>")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("a url is required")
+	}
+	rawURL := fs.Arg(0)
+
+	dest := *out
+	if dest == "" {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return err
+		}
+		if dest = path.Base(u.Path); dest == "/" || dest == "." {
+			return errors.New("can't name the file from the url, use -o")
+		}
+	}
+
+	var want []download.Checksum
+	if *checksum != "" {
+		c, err := download.ParseChecksum(*checksum)
+		if err != nil {
+			return err
+		}
+		want = append(want, c)
+	}
+
+	opts := []download.Option{
+		download.WithSegments(*segments, *minSize),
+		download.WithRetries(*retries, 500*time.Millisecond),
+	}
+	if *require {
+		opts = append(opts, download.RequireChecksum())
+	}
+	if !*quiet {
+		opts = append(opts, download.WithProgress(progress(stderr)))
+	}
+
+	res, err := download.New(opts...).Download(ctx, rawURL, dest, want...)
+	if !*quiet {
+		fmt.Fprintln(stderr)
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("interrupted, run again to resume: %w", err)
+		}
+		return err
+	}
+
+	if res.Resumed > 0 {
+		fmt.Fprintf(stderr, "resumed after %s\n", size(res.Resumed))
+	}
+	if len(res.Verified) == 0 {
+		fmt.Fprintf(stderr, "warning: %s has no checksum to verify\n", dest)
+	}
+	for _, c := range res.Verified {
+		fmt.Fprintf(stdout, "%s  %s\n", c, dest)
+	}
+
+	return nil
+}
+
+// progress returns a function that rewrites a progress line at most every
+// tenth of a second.
+func progress(w io.Writer) func(download.Progress) {
+	var last time.Time
+
+	return func(p download.Progress) {
+		if time.Since(last) < 100*time.Millisecond && p.Done != p.Total {
+			return
+		}
+		last = time.Now()
+
+		if p.Total < 0 {
+			fmt.Fprintf(w, "\r%s", size(p.Done))
+			return
+		}
+
+		pct := 100.0
+		if p.Total > 0 {
+			pct = 100 * float64(p.Done) / float64(p.Total)
+		}
+		fmt.Fprintf(w, "\r%5.1f%% %s of %s", pct, size(p.Done), size(p.Total))
+	}
+}
+
+// size formats a number of bytes for people.
+func size(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
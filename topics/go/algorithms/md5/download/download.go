@@ -0,0 +1,607 @@
+// Package download fetches files over HTTP. A download that's interrupted is
+// resumed with Range requests, large files are fetched in parallel segments,
+// and the file is verified against the checksums the server publishes.
+//
+// While a file downloads it's written to dest.part, and what has been
+// fetched is recorded in dest.part.json so a later call can pick up where
+// the last one stopped. The file is renamed to dest once it's verified.
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Set of errors returned by Download.
+var (
+	ErrChecksum   = errors.New("checksum mismatch")
+	ErrNoChecksum = errors.New("no checksum to verify")
+)
+
+// errChanged is returned by a segment when the file changed on the server
+// since the download started.
+var errChanged = errors.New("file changed on the server")
+
+// maxBackoff caps the wait between retries.
+const maxBackoff = 30 * time.Second
+
+// Progress describes how much of a file has been downloaded.
+type Progress struct {
+	Done  int64 // Bytes written, including those kept from an earlier attempt.
+	Total int64 // Size of the file, or -1 if the server didn't say.
+}
+
+// Result describes a finished download.
+type Result struct {
+	Size     int64      // Size of the file.
+	Resumed  int64      // Bytes kept from an earlier, interrupted download.
+	Segments int        // Number of segments fetched in parallel.
+	Verified []Checksum // Checksums the file was verified against.
+}
+
+// Downloader knows how to fetch files.
+type Downloader struct {
+	client     *http.Client
+	segments   int
+	minSegment int64
+	retries    int
+	backoff    time.Duration
+	progress   func(Progress)
+	require    bool
+}
+
+// Option changes the behavior of a Downloader.
+type Option func(*Downloader)
+
+// WithClient sets the HTTP client used for requests. The default is
+// http.DefaultClient.
+func WithClient(c *http.Client) Option {
+	return func(d *Downloader) {
+		d.client = c
+	}
+}
+
+// WithSegments splits a file into as many as n segments that are fetched in
+// parallel, each at least minSize bytes. The default is a single segment.
+// Servers that don't support Range requests always get one.
+func WithSegments(n int, minSize int64) Option {
+	return func(d *Downloader) {
+		d.segments = max(n, 1)
+		d.minSegment = max(minSize, 1)
+	}
+}
+
+// WithRetries sets how many times in a row a request can fail without
+// fetching anything before the download gives up, and how long to wait
+// before the first retry. The wait doubles with each retry. The default is
+// 5 retries starting at half a second.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(d *Downloader) {
+		d.retries = retries
+		d.backoff = backoff
+	}
+}
+
+// WithProgress calls fn as the file is written. Calls are never concurrent.
+func WithProgress(fn func(Progress)) Option {
+	return func(d *Downloader) {
+		d.progress = fn
+	}
+}
+
+// RequireChecksum makes a download fail with ErrNoChecksum when there's no
+// checksum to verify the file against.
+func RequireChecksum() Option {
+	return func(d *Downloader) {
+		d.require = true
+	}
+}
+
+// New builds a Downloader.
+func New(opts ...Option) *Downloader {
+	d := Downloader{
+		client:     http.DefaultClient,
+		segments:   1,
+		minSegment: 1,
+		retries:    5,
+		backoff:    500 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	return &d
+}
+
+// Download fetches the url into the file dest, resuming an earlier download
+// if one was interrupted. The file is verified against the checksums given
+// and those the server sends in the x-goog-hash, Content-MD5, Digest and
+// Repr-Digest headers. A file that doesn't match is removed.
+func (d *Downloader) Download(ctx context.Context, url, dest string, want ...Checksum) (Result, error) {
+	for _, c := range want {
+		if err := c.validate(); err != nil {
+			return Result{}, err
+		}
+	}
+
+	r, err := d.probe(ctx, url)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res, err := d.fetch(ctx, url, dest, r)
+	if errors.Is(err, errChanged) {
+
+		// What we have is from an older version of the file, so throw it
+		// away and start again.
+		discard(dest)
+		if r, err = d.probe(ctx, url); err != nil {
+			return Result{}, err
+		}
+		res, err = d.fetch(ctx, url, dest, r)
+	}
+	if err != nil {
+		return res, err
+	}
+
+	sums := unique(slices.Concat(want, r.checksums))
+	if len(sums) == 0 && d.require {
+		discard(dest)
+		return res, fmt.Errorf("%s: %w", url, ErrNoChecksum)
+	}
+
+	if err := verify(dest+".part", sums); err != nil {
+		discard(dest)
+		return res, fmt.Errorf("%s: %w", url, err)
+	}
+	res.Verified = sums
+
+	if err := os.Rename(dest+".part", dest); err != nil {
+		return res, err
+	}
+	os.Remove(dest + ".part.json")
+
+	return res, nil
+}
+
+// discard removes a partial download.
+func discard(dest string) {
+	os.Remove(dest + ".part")
+	os.Remove(dest + ".part.json")
+}
+
+// remote describes the file on the server.
+type remote struct {
+	size      int64  // -1 when unknown.
+	ranges    bool   // Server accepts Range requests.
+	validator string // ETag or Last-Modified, sent with If-Range.
+	checksums []Checksum
+}
+
+// probe asks the server about the file with a HEAD request. A server that
+// doesn't allow HEAD is asked with a GET instead, and only the headers of
+// the response are used.
+func (d *Downloader) probe(ctx context.Context, url string) (remote, error) {
+	var r remote
+	method := http.MethodHead
+	var noHead bool
+
+	ask := func() (bool, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return false, err
+		}
+
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return false, err
+		}
+		resp.Body.Close()
+
+		// A GET without a Range header has to be answered with the whole
+		// file, so anything but a 200 can't describe it.
+		switch {
+		case resp.StatusCode == http.StatusOK:
+		case method == http.MethodHead && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented):
+			noHead = true
+			return false, nil
+
+		default:
+			return false, newStatusError(resp)
+		}
+
+		r = remote{
+			size:      resp.ContentLength,
+			ranges:    resp.Header.Get("Accept-Ranges") == "bytes" && resp.ContentLength >= 0,
+			checksums: headerChecksums(resp.Header),
+		}
+
+		// A weak ETag can't be used with If-Range.
+		r.validator = resp.Header.Get("ETag")
+		if len(r.validator) > 1 && r.validator[:2] == "W/" {
+			r.validator = resp.Header.Get("Last-Modified")
+		}
+
+		return false, nil
+	}
+
+	err := d.retry(ctx, ask)
+	if err == nil && noHead {
+		method = http.MethodGet
+		err = d.retry(ctx, ask)
+	}
+	if err != nil {
+		return remote{}, fmt.Errorf("%s: %w", url, err)
+	}
+
+	return r, nil
+}
+
+// segment is a range of the file fetched by one request at a time.
+type segment struct {
+	Start int64 `json:"start"`
+	Next  int64 `json:"next"` // First byte not yet written.
+	End   int64 `json:"end"`  // Just past the last byte, or -1 when the size is unknown.
+}
+
+// done reports if the segment has been fetched.
+func (s segment) done() bool {
+	return s.End >= 0 && s.Next >= s.End
+}
+
+// state is what's recorded about a partial download.
+type state struct {
+	URL       string    `json:"url"`
+	Validator string    `json:"validator"`
+	Size      int64     `json:"size"`
+	Segments  []segment `json:"segments"`
+}
+
+// written returns the bytes written for the segments.
+func (st *state) written() int64 {
+	var n int64
+	for _, s := range st.Segments {
+		n += s.Next - s.Start
+	}
+	return n
+}
+
+// plan returns the state of a new download, or of the earlier one if it was
+// for the same file and can be resumed.
+func (d *Downloader) plan(url, dest string, r remote) *state {
+	if r.ranges {
+		var st state
+		data, err := os.ReadFile(dest + ".part.json")
+		if err == nil && json.Unmarshal(data, &st) == nil &&
+			st.URL == url && st.Validator == r.validator && st.Size == r.size {
+			if _, err := os.Stat(dest + ".part"); err == nil {
+				return &st
+			}
+		}
+	}
+
+	st := state{URL: url, Validator: r.validator, Size: r.size}
+
+	n := int64(1)
+	if r.ranges {
+		n = max(min(int64(d.segments), r.size/d.minSegment), 1)
+	}
+	if !r.ranges || r.size < 0 {
+		st.Segments = []segment{{Start: 0, Next: 0, End: r.size}}
+		return &st
+	}
+
+	for i := range n {
+		start, end := r.size*i/n, r.size*(i+1)/n
+		st.Segments = append(st.Segments, segment{Start: start, Next: start, End: end})
+	}
+
+	return &st
+}
+
+// save records the state of the download.
+func (st *state) save(dest string) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dest+".part.json", data, 0644)
+}
+
+// fetch writes the file to dest.part, fetching the segments not yet done in
+// parallel. The state is saved when it returns so an error can be resumed.
+func (d *Downloader) fetch(ctx context.Context, url, dest string, r remote) (Result, error) {
+	st := d.plan(url, dest, r)
+	res := Result{Size: r.size, Resumed: st.written(), Segments: len(st.Segments)}
+
+	f, err := os.OpenFile(dest+".part", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return res, err
+	}
+	defer f.Close()
+
+	if res.Resumed == 0 && r.size >= 0 {
+		if err := f.Truncate(r.size); err != nil {
+			return res, err
+		}
+	}
+
+	// Whatever happens, record how far we got. The state is written after
+	// the data it describes, so it never claims more than is on disk.
+	t := tracker{st: st, progress: d.progress}
+	if err := st.save(dest); err != nil {
+		return res, err
+	}
+	defer func() {
+		t.mu.Lock()
+		st.save(dest)
+		t.mu.Unlock()
+	}()
+	t.report()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(st.Segments))
+
+	for i := range st.Segments {
+		wg.Go(func() {
+			get := func() (bool, error) {
+				before := t.next(i)
+				err := d.get(ctx, url, f, &t, i, r)
+				return t.next(i) > before, err
+			}
+			if errs[i] = d.retry(ctx, get); errs[i] != nil {
+				cancel()
+			}
+		})
+	}
+	wg.Wait()
+
+	// Report the error that caused the others.
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return res, fmt.Errorf("%s: %w", url, err)
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return res, fmt.Errorf("%s: %w", url, err)
+		}
+	}
+
+	// When the size wasn't known it's whatever we were sent.
+	if r.size < 0 {
+		res.Size = t.next(0)
+		if err := f.Truncate(res.Size); err != nil {
+			return res, err
+		}
+	}
+
+	return res, f.Sync()
+}
+
+// tracker guards the state shared by the segments and reports progress.
+type tracker struct {
+	mu       sync.Mutex
+	st       *state
+	progress func(Progress)
+}
+
+// next returns the first byte of a segment not yet written.
+func (t *tracker) next(i int) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.st.Segments[i].Next
+}
+
+// segment returns a copy of a segment.
+func (t *tracker) segment(i int) segment {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.st.Segments[i]
+}
+
+// advance records that n more bytes of a segment were written, or when n is
+// negative that the segment starts over.
+func (t *tracker) advance(i int, n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &t.st.Segments[i]
+	s.Next = max(s.Next+n, s.Start)
+	t.reportLocked()
+}
+
+// report calls the progress function.
+func (t *tracker) report() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reportLocked()
+}
+
+// reportLocked calls the progress function with the lock held.
+func (t *tracker) reportLocked() {
+	if t.progress != nil {
+		t.progress(Progress{Done: t.st.written(), Total: t.st.Size})
+	}
+}
+
+// get makes one request for the rest of a segment and writes what it's
+// sent to the file.
+func (d *Downloader) get(ctx context.Context, url string, f *os.File, t *tracker, i int, r remote) error {
+	seg := t.segment(i)
+	if seg.done() {
+		return nil
+	}
+
+	// Without Range requests every attempt starts from the beginning.
+	if !r.ranges && seg.Next > seg.Start {
+		t.advance(i, seg.Start-seg.Next)
+		seg.Next = seg.Start
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	ranged := r.ranges && (seg.Next > 0 || seg.End < r.size)
+	if ranged {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", seg.Next, seg.End-1))
+		if r.validator != "" {
+			req.Header.Set("If-Range", r.validator)
+		}
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && ranged:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != seg.Next {
+			return fmt.Errorf("asked for bytes from %d, sent Content-Range %q", seg.Next, resp.Header.Get("Content-Range"))
+		}
+
+	case resp.StatusCode == http.StatusOK && ranged:
+
+		// The If-Range validator didn't match, so the whole of the new
+		// version of the file was sent.
+		return errChanged
+
+	case resp.StatusCode != http.StatusOK:
+		return newStatusError(resp)
+	}
+
+	next := seg.Next
+	buf := make([]byte, 32<<10)
+	for seg.End < 0 || next < seg.End {
+		n, err := resp.Body.Read(buf)
+		if seg.End >= 0 {
+			n = int(min(int64(n), seg.End-next))
+		}
+
+		if n > 0 {
+			if _, err := f.WriteAt(buf[:n], next); err != nil {
+				return err
+			}
+			next += int64(n)
+			t.advance(i, int64(n))
+		}
+
+		switch {
+		case err == nil:
+		case errors.Is(err, io.EOF) && (seg.End < 0 || next >= seg.End):
+			return nil
+		case errors.Is(err, io.EOF):
+			return fmt.Errorf("body ended at byte %d of %d: %w", next, seg.End, io.ErrUnexpectedEOF)
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// contentRangeStart returns the first byte from a Content-Range header like
+// "bytes 100-199/1000".
+func contentRangeStart(v string) (int64, bool) {
+	v, found := strings.CutPrefix(v, "bytes ")
+	if !found {
+		return 0, false
+	}
+	first, _, found := strings.Cut(v, "-")
+	if !found {
+		return 0, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	return start, err == nil
+}
+
+// statusError is returned for a response with an unexpected status.
+type statusError struct {
+	code       int
+	status     string
+	retryAfter time.Duration
+}
+
+// newStatusError builds the error for a response.
+func newStatusError(resp *http.Response) *statusError {
+	e := statusError{code: resp.StatusCode, status: resp.Status}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		e.retryAfter = time.Duration(secs) * time.Second
+	}
+	return &e
+}
+
+// Error implements the error interface.
+func (e *statusError) Error() string {
+	return "server responded with " + e.status
+}
+
+// retryable reports if a failed request is worth trying again.
+func retryable(err error) bool {
+	var se *statusError
+	switch {
+	case errors.As(err, &se):
+		return se.code == http.StatusRequestTimeout || se.code == http.StatusTooManyRequests || se.code >= 500
+	case errors.Is(err, errChanged), errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.As(err, new(*fs.PathError)):
+
+		// Writing the file failed, which trying again won't fix.
+		return false
+	}
+
+	// The connection failed or was cut short.
+	return true
+}
+
+// retry calls fn until it succeeds, fails with an error that isn't worth
+// retrying, or fails more times in a row than allowed without progressing.
+func (d *Downloader) retry(ctx context.Context, fn func() (progressed bool, err error)) error {
+	for failures := 0; ; {
+		progressed, err := fn()
+		if err == nil || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		if progressed {
+			failures = 0
+		}
+		failures++
+		if failures > d.retries {
+			return fmt.Errorf("giving up after %d attempts: %w", failures, err)
+		}
+
+		wait := min(d.backoff<<min(failures-1, 16), maxBackoff)
+		var se *statusError
+		if errors.As(err, &se) {
+			wait = max(wait, se.retryAfter)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
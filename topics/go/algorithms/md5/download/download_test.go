@@ -0,0 +1,406 @@
+package download_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/md5/download"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// server serves a file like a static file server does, with Range
+// requests, and can be told to misbehave.
+type server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	content  []byte
+	etag     string
+	next     []byte   // Content served after the next HEAD request.
+	noRanges bool     // Ignore Range requests.
+	noHead   bool     // Respond to HEAD with a 405.
+	noHash   bool     // Don't send x-goog-hash.
+	fails    int      // GET responses to fail with a 503.
+	cuts     int      // GET responses to cut short.
+	cutAfter int64    // Bytes sent before a response is cut.
+	ranges   []string // Range headers of the GETs received.
+}
+
+// newServer starts a server for the content.
+func newServer(t *testing.T, content []byte) *server {
+	s := server{}
+	s.setContent(content)
+	s.Server = httptest.NewServer(&s)
+	t.Cleanup(s.Close)
+
+	return &s
+}
+
+// setContent changes the file being served.
+func (s *server) setContent(content []byte) {
+	s.content = content
+	s.etag = fmt.Sprintf(`"%x"`, md5.Sum(content))
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	content, etag, noRanges := s.content, s.etag, s.noRanges
+	if !s.noHash {
+		sum := md5.Sum(content)
+		w.Header().Set("x-goog-hash", "md5="+base64.StdEncoding.EncodeToString(sum[:]))
+	}
+
+	var fail bool
+	cut := int64(-1)
+	switch {
+	case r.Method == http.MethodHead && s.noHead:
+		s.mu.Unlock()
+		http.Error(w, "use GET", http.StatusMethodNotAllowed)
+		return
+
+	case r.Method == http.MethodHead && s.next != nil:
+		s.setContent(s.next)
+		s.next = nil
+
+	case r.Method == http.MethodGet:
+		s.ranges = append(s.ranges, r.Header.Get("Range"))
+		switch {
+		case s.fails > 0:
+			s.fails--
+			fail = true
+		case s.cuts > 0:
+			s.cuts--
+			cut = s.cutAfter
+		}
+	}
+	s.mu.Unlock()
+
+	if fail {
+		http.Error(w, "try again later", http.StatusServiceUnavailable)
+		return
+	}
+	if cut >= 0 {
+		w = &cutWriter{ResponseWriter: w, left: cut}
+	}
+
+	if noRanges {
+		w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+		if r.Method == http.MethodGet {
+			w.Write(content)
+		}
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+}
+
+// cutWriter drops the connection once enough of the body has been sent.
+type cutWriter struct {
+	http.ResponseWriter
+	left int64
+}
+
+// Write implements the io.Writer interface.
+func (w *cutWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) <= w.left {
+		w.left -= int64(len(p))
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.ResponseWriter.Write(p[:w.left])
+	w.ResponseWriter.(http.Flusher).Flush()
+	panic(http.ErrAbortHandler)
+}
+
+// content returns a file of n random bytes.
+func content(n int) []byte {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(r.Uint32())
+	}
+	return b
+}
+
+func TestDownload(t *testing.T) {
+	const size = 200_000
+
+	tt := []struct {
+		name     string
+		segments int
+		setup    func(s *server)
+	}{
+		{"whole", 1, func(s *server) {}},
+		{"segments", 4, func(s *server) {}},
+		{"disconnects", 1, func(s *server) { s.cuts, s.cutAfter = 3, 40_000 }},
+		{"disconnects in segments", 4, func(s *server) { s.cuts, s.cutAfter = 6, 10_000 }},
+		{"server errors", 4, func(s *server) { s.fails = 3 }},
+		{"no ranges", 4, func(s *server) { s.noRanges = true }},
+		{"no ranges disconnects", 1, func(s *server) { s.noRanges, s.cuts, s.cutAfter = true, 2, 40_000 }},
+		{"no head", 4, func(s *server) { s.noHead = true }},
+		{"no head disconnects", 1, func(s *server) { s.noHead, s.cuts, s.cutAfter = true, 3, 40_000 }},
+	}
+
+	t.Log("Given the need to download a file over an unreliable connection.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen downloading with %d segments.", testID, test.segments)
+				{
+					want := content(size)
+					s := newServer(t, want)
+					s.mu.Lock()
+					test.setup(s)
+					s.mu.Unlock()
+
+					var last download.Progress
+					d := download.New(
+						download.WithClient(s.Client()),
+						download.WithSegments(test.segments, 1000),
+						download.WithRetries(2, time.Millisecond),
+						download.WithProgress(func(p download.Progress) { last = p }),
+					)
+
+					dest := filepath.Join(t.TempDir(), "file")
+					res, err := d.Download(context.Background(), s.URL, dest)
+					if err != nil {
+						t.Fatalf("\t%s\tTest %d:\tShould be able to download the file : %v", failed, testID, err)
+					}
+					t.Logf("\t%s\tTest %d:\tShould be able to download the file.", succeed, testID)
+
+					got, err := os.ReadFile(dest)
+					if err != nil || !bytes.Equal(got, want) {
+						t.Fatalf("\t%s\tTest %d:\tShould have written the file : got %d bytes, %v.", failed, testID, len(got), err)
+					}
+					t.Logf("\t%s\tTest %d:\tShould have written the file.", succeed, testID)
+
+					if res.Size != size || len(res.Verified) != 1 || res.Verified[0].Algorithm != "md5" {
+						t.Errorf("\t%s\tTest %d:\tShould have verified the md5 from x-goog-hash : %+v", failed, testID, res)
+					} else {
+						t.Logf("\t%s\tTest %d:\tShould have verified the md5 from x-goog-hash.", succeed, testID)
+					}
+
+					if last.Done != size || last.Total != size {
+						t.Errorf("\t%s\tTest %d:\tShould have reported all the progress : %+v", failed, testID, last)
+					} else {
+						t.Logf("\t%s\tTest %d:\tShould have reported all the progress.", succeed, testID)
+					}
+
+					if _, err := os.Stat(dest + ".part.json"); !errors.Is(err, os.ErrNotExist) {
+						t.Errorf("\t%s\tTest %d:\tShould have removed the partial download state : %v", failed, testID, err)
+					} else {
+						t.Logf("\t%s\tTest %d:\tShould have removed the partial download state.", succeed, testID)
+					}
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	const size, cutAfter = 100_000, 30_000
+
+	t.Log("Given the need to resume an interrupted download.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the first attempt gives up part way.", testID)
+		{
+			want := content(size)
+			s := newServer(t, want)
+			s.mu.Lock()
+			s.cuts, s.cutAfter = 1, cutAfter
+			s.mu.Unlock()
+
+			dest := filepath.Join(t.TempDir(), "file")
+
+			d := download.New(download.WithClient(s.Client()), download.WithRetries(0, time.Millisecond))
+			if _, err := d.Download(context.Background(), s.URL, dest); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould have failed without retries.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have failed without retries.", succeed, testID)
+
+			d = download.New(download.WithClient(s.Client()))
+			res, err := d.Download(context.Background(), s.URL, dest)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to download the file : %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to download the file.", succeed, testID)
+
+			if res.Resumed != cutAfter {
+				t.Errorf("\t%s\tTest %d:\tShould have kept the first %d bytes : kept %d", failed, testID, cutAfter, res.Resumed)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould have kept the first %d bytes.", succeed, testID, cutAfter)
+			}
+
+			wantRange := fmt.Sprintf("bytes=%d-%d", cutAfter, size-1)
+			s.mu.Lock()
+			last := s.ranges[len(s.ranges)-1]
+			s.mu.Unlock()
+
+			if last != wantRange {
+				t.Errorf("\t%s\tTest %d:\tShould have asked for the rest : got %q, want %q", failed, testID, last, wantRange)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould have asked for the rest.", succeed, testID)
+			}
+
+			if got, _ := os.ReadFile(dest); !bytes.Equal(got, want) {
+				t.Errorf("\t%s\tTest %d:\tShould have written the file.", failed, testID)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould have written the file.", succeed, testID)
+			}
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the file changes before the download resumes.", testID)
+		{
+			s := newServer(t, content(size))
+			s.mu.Lock()
+			s.cuts, s.cutAfter = 1, cutAfter
+			s.mu.Unlock()
+
+			dest := filepath.Join(t.TempDir(), "file")
+
+			d := download.New(download.WithClient(s.Client()), download.WithRetries(0, time.Millisecond))
+			d.Download(context.Background(), s.URL, dest)
+
+			// The HEAD request still sees the old file, but If-Range
+			// catches the change.
+			want := bytes.Repeat([]byte("changed"), size/7)
+			s.mu.Lock()
+			s.next = want
+			s.mu.Unlock()
+
+			d = download.New(download.WithClient(s.Client()))
+			res, err := d.Download(context.Background(), s.URL, dest)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to download the file : %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to download the file.", succeed, testID)
+
+			if got, _ := os.ReadFile(dest); !bytes.Equal(got, want) || res.Resumed != 0 {
+				t.Errorf("\t%s\tTest %d:\tShould have started again with the new file : resumed %d", failed, testID, res.Resumed)
+			} else {
+				t.Logf("\t%s\tTest %d:\tShould have started again with the new file.", succeed, testID)
+			}
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	want := content(10_000)
+	sum := sha256.Sum256(want)
+	good := download.Checksum{Algorithm: "sha256", Sum: sum[:]}
+	bad := download.Checksum{Algorithm: "sha256", Sum: make([]byte, sha256.Size)}
+
+	t.Log("Given the need to verify a downloaded file.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the expected checksum matches.", testID)
+		{
+			s := newServer(t, want)
+			dest := filepath.Join(t.TempDir(), "file")
+
+			res, err := download.New(download.WithClient(s.Client())).Download(context.Background(), s.URL, dest, good)
+			if err != nil || len(res.Verified) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould have verified the sha256 and md5 : %+v, %v", failed, testID, res, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have verified the sha256 and md5.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the expected checksum doesn't match.", testID)
+		{
+			s := newServer(t, want)
+			dest := filepath.Join(t.TempDir(), "file")
+
+			_, err := download.New(download.WithClient(s.Client())).Download(context.Background(), s.URL, dest, bad)
+			if !errors.Is(err, download.ErrChecksum) {
+				t.Fatalf("\t%s\tTest %d:\tShould have failed with ErrChecksum : %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have failed with ErrChecksum.", succeed, testID)
+
+			files, _ := os.ReadDir(filepath.Dir(dest))
+			if len(files) != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould have removed the file : found %d files", failed, testID, len(files))
+			}
+			t.Logf("\t%s\tTest %d:\tShould have removed the file.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a checksum is required but there isn't one.", testID)
+		{
+			s := newServer(t, want)
+			s.mu.Lock()
+			s.noHash = true
+			s.mu.Unlock()
+			dest := filepath.Join(t.TempDir(), "file")
+
+			_, err := download.New(download.WithClient(s.Client()), download.RequireChecksum()).Download(context.Background(), s.URL, dest)
+			if !errors.Is(err, download.ErrNoChecksum) {
+				t.Fatalf("\t%s\tTest %d:\tShould have failed with ErrNoChecksum : %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have failed with ErrNoChecksum.", succeed, testID)
+		}
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		success bool
+	}{
+		{"md5", "md5:eec1fa5ce8077d7030e194eb5989c937", true},
+		{"sha256", "sha256:" + strings.Repeat("ab", 32), true},
+		{"crc32c", "crc32c:9f4df1e8", true},
+		{"unknown", "md4:eec1fa5ce8077d7030e194eb5989c937", false},
+		{"short", "md5:eec1fa5c", false},
+		{"not hex", "md5:" + strings.Repeat("zz", 16), false},
+		{"no algorithm", "eec1fa5ce8077d7030e194eb5989c937", false},
+	}
+
+	t.Log("Given the need to parse expected checksums.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen parsing %q.", testID, test.input)
+				{
+					c, err := download.ParseChecksum(test.input)
+					switch test.success {
+					case true:
+						if err != nil || c.String() != test.input {
+							t.Fatalf("\t%s\tTest %d:\tShould be able to parse the checksum : %v", failed, testID, err)
+						}
+						t.Logf("\t%s\tTest %d:\tShould be able to parse the checksum.", succeed, testID)
+					case false:
+						if err == nil {
+							t.Fatalf("\t%s\tTest %d:\tShould have rejected the checksum.", failed, testID)
+						}
+						t.Logf("\t%s\tTest %d:\tShould have rejected the checksum.", succeed, testID)
+					}
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
@@ -45,6 +45,7 @@ func (d *device) flipProblem() {
 	d.problem = !d.problem
 }
 
+//runexamples:skip waits for a signal to shut down
 func main() {
 
 	// Number of goroutines that will be writing logs.
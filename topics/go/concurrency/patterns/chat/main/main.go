@@ -14,6 +14,7 @@ import (
 	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/chat"
 )
 
+//runexamples:skip waits for a signal to shut down
 func main() {
 	cr := chat.New()
 
@@ -0,0 +1,202 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+var start = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// panics reports if fn panics.
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+// TestSleep validates goroutines sleeping on the fake clock.
+func TestSleep(t *testing.T) {
+	t.Log("Given the need to wake sleeping goroutines with virtual time.")
+	{
+		t.Log("\tTest 0:\tWhen three goroutines sleep for different durations.")
+		{
+			f := clock.NewFake(start)
+			woke := make(chan time.Duration, 3)
+
+			for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+				go func() {
+					f.Sleep(d)
+					woke <- d
+				}()
+			}
+
+			f.BlockUntil(3)
+			t.Logf("\t%s\tTest 0:\tShould report all goroutines are blocked.", succeed)
+
+			f.Advance(1500 * time.Millisecond)
+			if d := <-woke; d != time.Second {
+				t.Fatalf("\t%s\tTest 0:\tShould wake the 1s sleeper first : %v", failed, d)
+			}
+			t.Logf("\t%s\tTest 0:\tShould wake the 1s sleeper first.", succeed)
+
+			if n := f.Waiters(); n != 2 {
+				t.Fatalf("\t%s\tTest 0:\tShould have 2 goroutines still sleeping : %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 0:\tShould have 2 goroutines still sleeping.", succeed)
+
+			f.Advance(time.Hour)
+			<-woke
+			<-woke
+
+			if now := f.Now(); !now.Equal(start.Add(time.Hour + 1500*time.Millisecond)) {
+				t.Fatalf("\t%s\tTest 0:\tShould move the time forward : %v", failed, now)
+			}
+			t.Logf("\t%s\tTest 0:\tShould move the time forward.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen a goroutine sleeps for zero or less.")
+		{
+			f := clock.NewFake(start)
+
+			f.Sleep(0)
+			f.Sleep(-time.Second)
+			<-f.After(0)
+
+			if n := f.Waiters(); n != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould not wait on the clock : %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 1:\tShould return without the time being advanced.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen the time is advanced by a negative duration.")
+		{
+			f := clock.NewFake(start)
+
+			if !panics(func() { f.Advance(-time.Second) }) {
+				t.Fatalf("\t%s\tTest 2:\tShould panic.", failed)
+			}
+			if now := f.Now(); !now.Equal(start) {
+				t.Fatalf("\t%s\tTest 2:\tShould not move the time backwards : %v", failed, now)
+			}
+			t.Logf("\t%s\tTest 2:\tShould panic and not move the time backwards.", succeed)
+		}
+	}
+}
+
+// TestTimer validates timers created from the fake clock.
+func TestTimer(t *testing.T) {
+	t.Log("Given the need to use timers with virtual time.")
+	{
+		t.Log("\tTest 0:\tWhen a timer is stopped and reset.")
+		{
+			f := clock.NewFake(start)
+			tm := f.NewTimer(time.Second)
+
+			if !tm.Stop() {
+				t.Fatalf("\t%s\tTest 0:\tShould stop an active timer.", failed)
+			}
+			f.Advance(time.Minute)
+
+			select {
+			case <-tm.C():
+				t.Fatalf("\t%s\tTest 0:\tShould not fire a stopped timer.", failed)
+			default:
+			}
+			t.Logf("\t%s\tTest 0:\tShould not fire a stopped timer.", succeed)
+
+			if tm.Reset(time.Second) {
+				t.Fatalf("\t%s\tTest 0:\tShould report the timer was not active.", failed)
+			}
+			f.Advance(time.Second)
+
+			if got := <-tm.C(); !got.Equal(start.Add(time.Minute + time.Second)) {
+				t.Fatalf("\t%s\tTest 0:\tShould fire at the reset time : %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould fire at the reset time.", succeed)
+		}
+	}
+}
+
+// TestTicker validates tickers created from the fake clock.
+func TestTicker(t *testing.T) {
+	t.Log("Given the need to use tickers with virtual time.")
+	{
+		t.Log("\tTest 0:\tWhen the time is advanced one interval at a time.")
+		{
+			f := clock.NewFake(start)
+			tk := f.NewTicker(100 * time.Millisecond)
+			defer tk.Stop()
+
+			for i := 1; i <= 5; i++ {
+				f.Advance(100 * time.Millisecond)
+				got := <-tk.C()
+				if exp := start.Add(time.Duration(i) * 100 * time.Millisecond); !got.Equal(exp) {
+					t.Fatalf("\t%s\tTest 0:\tShould tick every interval : got %v, exp %v", failed, got, exp)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould tick every interval.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the receiver falls behind.")
+		{
+			f := clock.NewFake(start)
+			tk := f.NewTicker(time.Second)
+
+			f.Advance(10 * time.Second)
+			<-tk.C()
+
+			select {
+			case <-tk.C():
+				t.Fatalf("\t%s\tTest 1:\tShould drop the ticks that were missed.", failed)
+			default:
+			}
+			t.Logf("\t%s\tTest 1:\tShould drop the ticks that were missed.", succeed)
+
+			tk.Stop()
+			if n := f.Waiters(); n != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould remove a stopped ticker : %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 1:\tShould remove a stopped ticker.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen a ticker is reset to a non-positive interval.")
+		{
+			f := clock.NewFake(start)
+			tk := f.NewTicker(time.Second)
+			defer tk.Stop()
+
+			if !panics(func() { tk.Reset(0) }) {
+				t.Fatalf("\t%s\tTest 2:\tShould panic like time.Ticker.Reset.", failed)
+			}
+			t.Logf("\t%s\tTest 2:\tShould panic like time.Ticker.Reset.", succeed)
+		}
+	}
+}
+
+// TestReal validates the real clock is backed by the time package.
+func TestReal(t *testing.T) {
+	t.Log("Given the need to use the real clock.")
+	{
+		t.Log("\tTest 0:\tWhen sleeping for a short duration.")
+		{
+			c := clock.Real()
+			before := c.Now()
+			c.Sleep(time.Millisecond)
+
+			if c.Now().Sub(before) < time.Millisecond {
+				t.Fatalf("\t%s\tTest 0:\tShould sleep for the duration.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould sleep for the duration.", succeed)
+		}
+	}
+}
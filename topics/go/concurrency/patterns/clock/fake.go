@@ -0,0 +1,244 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake implements the Clock interface with virtual time. Time only moves
+// forward when Advance is called, which fires every timer, ticker and sleep
+// that becomes due in the order of their deadlines.
+type Fake struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter represents a timer, ticker or sleep waiting on the fake clock.
+type waiter struct {
+	when   time.Time
+	period time.Duration // Non-zero for tickers.
+	ch     chan time.Time
+}
+
+// NewFake constructs a fake clock set to the specified time.
+func NewFake(now time.Time) *Fake {
+	f := Fake{
+		now: now,
+	}
+	f.cond = sync.NewCond(&f.mu)
+
+	return &f
+}
+
+// Now returns the current virtual time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Sleep blocks the calling goroutine until the virtual time has been
+// advanced by at least d. Like time.Sleep, it returns at once if d is not
+// positive.
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// After returns a channel that receives the virtual time once it has been
+// advanced by at least d.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer constructs a timer that fires once the virtual time has been
+// advanced by at least d, or at once if d is not positive.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	w := waiter{
+		ch: make(chan time.Time, 1),
+	}
+	f.schedule(&w, d, 0)
+
+	return &fakeTimer{f: f, w: &w}
+}
+
+// NewTicker constructs a ticker that fires every time the virtual time
+// passes another multiple of d.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	w := waiter{
+		ch: make(chan time.Time, 1),
+	}
+	f.schedule(&w, d, d)
+
+	return &fakeTicker{f: f, w: &w}
+}
+
+// Advance moves the virtual time forward by d, firing every waiter that
+// becomes due along the way. Time can't move backwards, so a negative d
+// panics.
+func (f *Fake) Advance(d time.Duration) {
+	if d < 0 {
+		panic("negative duration for Advance")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	end := f.now.Add(d)
+	for {
+		w := f.next(end)
+		if w == nil {
+			break
+		}
+		f.now = w.when
+
+		// Like the time package, a tick is dropped if the receiver
+		// has not taken the previous one.
+		select {
+		case w.ch <- f.now:
+		default:
+		}
+
+		if w.period > 0 {
+			w.when = w.when.Add(w.period)
+			continue
+		}
+		f.remove(w)
+	}
+	f.now = end
+
+	f.cond.Broadcast()
+}
+
+// Waiters returns the number of timers, tickers and sleeping goroutines
+// currently waiting on the clock.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil blocks the caller until at least n timers, tickers or sleeping
+// goroutines are waiting on the clock. Tests use this to know every goroutine
+// under test has reached the point where it needs time to move.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+// schedule places the waiter on the clock to fire after d, replacing any
+// existing schedule, and reports if the waiter was already waiting. A timer
+// that is not given a positive d fires without waiting on the clock.
+func (f *Fake) schedule(w *waiter, d time.Duration, period time.Duration) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	active := f.remove(w)
+
+	if period == 0 && d <= 0 {
+		select {
+		case w.ch <- f.now:
+		default:
+		}
+		return active
+	}
+
+	w.when = f.now.Add(d)
+	w.period = period
+	f.waiters = append(f.waiters, w)
+
+	f.cond.Broadcast()
+
+	return active
+}
+
+// next returns the waiter with the earliest deadline that is due by end.
+// The lock must be held.
+func (f *Fake) next(end time.Time) *waiter {
+	var next *waiter
+	for _, w := range f.waiters {
+		if w.when.After(end) {
+			continue
+		}
+		if next == nil || w.when.Before(next.when) {
+			next = w
+		}
+	}
+
+	return next
+}
+
+// remove takes the waiter off the clock and reports if it was waiting.
+// The lock must be held.
+func (f *Fake) remove(w *waiter) bool {
+	for i, v := range f.waiters {
+		if v == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// =============================================================================
+
+// fakeTimer implements the Timer interface for the fake clock.
+type fakeTimer struct {
+	f *Fake
+	w *waiter
+}
+
+func (ft *fakeTimer) C() <-chan time.Time {
+	return ft.w.ch
+}
+
+func (ft *fakeTimer) Stop() bool {
+	ft.f.mu.Lock()
+	defer ft.f.mu.Unlock()
+
+	return ft.f.remove(ft.w)
+}
+
+func (ft *fakeTimer) Reset(d time.Duration) bool {
+	return ft.f.schedule(ft.w, d, 0)
+}
+
+// fakeTicker implements the Ticker interface for the fake clock.
+type fakeTicker struct {
+	f *Fake
+	w *waiter
+}
+
+func (ft *fakeTicker) C() <-chan time.Time {
+	return ft.w.ch
+}
+
+func (ft *fakeTicker) Stop() {
+	ft.f.mu.Lock()
+	defer ft.f.mu.Unlock()
+
+	ft.f.remove(ft.w)
+}
+
+func (ft *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+
+	ft.f.schedule(ft.w, d, d)
+}
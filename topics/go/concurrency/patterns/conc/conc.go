@@ -0,0 +1,48 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Package conc provides typed, context aware versions of the channel patterns
+// taught in the concurrency/channels and generics/10-channels material. Every
+// function in this package guarantees that the goroutines it creates have
+// terminated, or will terminate without blocking, once the function returns
+// or the context is cancelled.
+package conc
+
+import (
+	"context"
+	"sync"
+)
+
+// Result represents the outcome of a unit of work performed by a goroutine.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// FanOut launches one goroutine for each work function and returns a channel
+// that will receive every result. The channel is buffered to hold all the
+// results so no child goroutine ever blocks on a send, which means a caller
+// can walk away from the channel without leaking goroutines. The channel is
+// closed once all the work has been performed.
+func FanOut[T any](ctx context.Context, work ...func(context.Context) (T, error)) <-chan Result[T] {
+	ch := make(chan Result[T], len(work))
+
+	var wg sync.WaitGroup
+	wg.Add(len(work))
+
+	for _, w := range work {
+		go func() {
+			defer wg.Done()
+			v, err := w(ctx)
+			ch <- Result[T]{Value: v, Err: err}
+		}()
+	}
+
+	// Close the channel once every child goroutine has sent its result.
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+
+	return ch
+}
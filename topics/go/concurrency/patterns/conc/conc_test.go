@@ -0,0 +1,472 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package conc_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/conc"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+// checkLeaks records the number of goroutines running when the test starts
+// and validates that same number is running once the test finishes.
+func checkLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+
+	t.Cleanup(func() {
+		deadline := time.Now().Add(time.Second)
+		for {
+			after := runtime.NumGoroutine()
+			if after <= before {
+				return
+			}
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<16)
+				n := runtime.Stack(buf, true)
+				t.Fatalf("\t%s\tShould not leak goroutines : before[%d] after[%d]\n%s", failed, before, after, buf[:n])
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+// TestFanOut validates the FanOut functionality.
+func TestFanOut(t *testing.T) {
+	t.Log("Given the need to receive results from many goroutines.")
+	{
+		t.Log("\tTest 0:\tWhen all the results are received.")
+		{
+			checkLeaks(t)
+
+			const children = 100
+			work := make([]func(context.Context) (int, error), children)
+			for i := range work {
+				work[i] = func(ctx context.Context) (int, error) { return i, nil }
+			}
+
+			var sum int
+			var count int
+			for r := range conc.FanOut(context.Background(), work...) {
+				sum += r.Value
+				count++
+			}
+
+			if count != children {
+				t.Fatalf("\t%s\tTest 0:\tShould receive %d results : %d", failed, children, count)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive %d results.", succeed, children)
+
+			if exp := children * (children - 1) / 2; sum != exp {
+				t.Fatalf("\t%s\tTest 0:\tShould receive every value : got %d, exp %d", failed, sum, exp)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive every value.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the caller walks away from the channel.")
+		{
+			checkLeaks(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			work := make([]func(context.Context) (int, error), 10)
+			for i := range work {
+				work[i] = func(ctx context.Context) (int, error) {
+					<-ctx.Done()
+					return 0, ctx.Err()
+				}
+			}
+
+			conc.FanOut(ctx, work...)
+			cancel()
+			t.Logf("\t%s\tTest 1:\tShould be able to abandon the results.", succeed)
+		}
+	}
+}
+
+// TestPipeline validates the Generate, Stage and Collect functionality.
+func TestPipeline(t *testing.T) {
+	t.Log("Given the need to chain stages of work together.")
+	{
+		t.Log("\tTest 0:\tWhen every value flows through the pipeline.")
+		{
+			checkLeaks(t)
+
+			ctx := context.Background()
+			src := conc.Generate(ctx, 1, 2, 3, 4)
+			sq := conc.Stage(ctx, src, func(ctx context.Context, v int) int { return v * v })
+			str := conc.Stage(ctx, sq, func(ctx context.Context, v int) string { return string(rune('a' + v)) })
+
+			got, err := conc.Collect(ctx, str)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to collect the values : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould be able to collect the values.", succeed)
+
+			exp := []string{"b", "e", "j", "q"}
+			if len(got) != len(exp) {
+				t.Fatalf("\t%s\tTest 0:\tShould receive the values in order : got %v, exp %v", failed, got, exp)
+			}
+			for i := range exp {
+				if got[i] != exp[i] {
+					t.Fatalf("\t%s\tTest 0:\tShould receive the values in order : got %v, exp %v", failed, got, exp)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive the values in order.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the pipeline is cancelled part way through.")
+		{
+			checkLeaks(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			src := conc.Generate(ctx, make([]int, 1000)...)
+			st := conc.Stage(ctx, src, func(ctx context.Context, v int) int { return v })
+
+			<-st
+			cancel()
+
+			if _, err := conc.Collect(ctx, st); err == nil {
+				// The stage may close the channel before Collect notices
+				// the cancellation, both outcomes are valid.
+				t.Logf("\t%s\tTest 1:\tShould see the stage close.", succeed)
+			} else {
+				t.Logf("\t%s\tTest 1:\tShould see the context cancelled.", succeed)
+			}
+		}
+	}
+}
+
+// TestDrop validates the Drop functionality.
+func TestDrop(t *testing.T) {
+	t.Log("Given the need to drop values when the receiver falls behind.")
+	{
+		t.Log("\tTest 0:\tWhen the buffer fills up.")
+		{
+			checkLeaks(t)
+
+			var dropped []int
+			in := conc.Generate(context.Background(), 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+			out := conc.Drop(context.Background(), in, 3, func(v int) { dropped = append(dropped, v) })
+
+			// Wait for every value to be sent or dropped before receiving.
+			for len(out) < 3 {
+				time.Sleep(time.Millisecond)
+			}
+
+			got, err := conc.Collect(context.Background(), out)
+			if err != nil || !slices.Equal(got, []int{0, 1, 2}) {
+				t.Fatalf("\t%s\tTest 0:\tShould keep the buffered values : got %v err[%v]", failed, got, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould keep the buffered values.", succeed)
+
+			if !slices.Equal(dropped, []int{3, 4, 5, 6, 7, 8, 9}) {
+				t.Fatalf("\t%s\tTest 0:\tShould drop the rest : %v", failed, dropped)
+			}
+			t.Logf("\t%s\tTest 0:\tShould drop the rest.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the context is cancelled.")
+		{
+			checkLeaks(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			in := make(chan int)
+			out := conc.Drop(ctx, in, 1, nil)
+			cancel()
+
+			for range out {
+			}
+			t.Logf("\t%s\tTest 1:\tShould close the channel.", succeed)
+		}
+	}
+}
+
+// TestBoundedPool validates the BoundedPool functionality.
+func TestBoundedPool(t *testing.T) {
+	t.Log("Given the need to process a set of inputs with a fixed pool.")
+	{
+		t.Log("\tTest 0:\tWhen all the work succeeds.")
+		{
+			checkLeaks(t)
+
+			const size = 4
+			var running, peak atomic.Int64
+
+			inputs := make([]int, 200)
+			for i := range inputs {
+				inputs[i] = i
+			}
+
+			work := func(ctx context.Context, v int) (int, error) {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Duration(v%3) * time.Millisecond)
+				running.Add(-1)
+				return v * 2, nil
+			}
+
+			got, err := conc.BoundedPool(context.Background(), size, inputs, work)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould process all the inputs : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould process all the inputs.", succeed)
+
+			for i, v := range got {
+				if v != i*2 {
+					t.Fatalf("\t%s\tTest 0:\tShould return results in order : idx %d got %d", failed, i, v)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould return results in order.", succeed)
+
+			if p := peak.Load(); p > size {
+				t.Fatalf("\t%s\tTest 0:\tShould never run more than %d goroutines : %d", failed, size, p)
+			}
+			t.Logf("\t%s\tTest 0:\tShould never run more than %d goroutines.", succeed, size)
+		}
+
+		t.Log("\tTest 1:\tWhen some of the work fails.")
+		{
+			checkLeaks(t)
+
+			boom := errors.New("boom")
+			var calls atomic.Int64
+
+			work := func(ctx context.Context, v int) (int, error) {
+				calls.Add(1)
+				if v == 5 {
+					return 0, boom
+				}
+				select {
+				case <-ctx.Done():
+					return 0, ctx.Err()
+				case <-time.After(time.Millisecond):
+				}
+				return v, nil
+			}
+
+			inputs := make([]int, 1000)
+			for i := range inputs {
+				inputs[i] = i
+			}
+
+			_, err := conc.BoundedPool(context.Background(), 2, inputs, work)
+			if !errors.Is(err, boom) {
+				t.Fatalf("\t%s\tTest 1:\tShould return the first error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould return the first error.", succeed)
+
+			if n := calls.Load(); n >= int64(len(inputs)) {
+				t.Fatalf("\t%s\tTest 1:\tShould stop starting new work : %d calls", failed, n)
+			}
+			t.Logf("\t%s\tTest 1:\tShould stop starting new work.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen an invalid size is provided.")
+		{
+			if _, err := conc.BoundedPool(context.Background(), 0, []int{1}, func(ctx context.Context, v int) (int, error) { return v, nil }); !errors.Is(err, conc.ErrInvalidSize) {
+				t.Fatalf("\t%s\tTest 2:\tShould receive ErrInvalidSize : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 2:\tShould receive ErrInvalidSize.", succeed)
+		}
+
+		t.Log("\tTest 3:\tWhen the context is cancelled after the last input is handed out.")
+		{
+			checkLeaks(t)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			work := func(ctx context.Context, v int) (int, error) {
+				if v == 3 {
+					cancel()
+				}
+				return v * 2, nil
+			}
+
+			got, err := conc.BoundedPool(ctx, 1, []int{1, 2, 3}, work)
+			if err != nil || !slices.Equal(got, []int{2, 4, 6}) {
+				t.Fatalf("\t%s\tTest 3:\tShould return the finished work : got %v err[%v]", failed, got, err)
+			}
+			t.Logf("\t%s\tTest 3:\tShould return the finished work.", succeed)
+		}
+	}
+}
+
+// TestRetry validates the Retry functionality.
+func TestRetry(t *testing.T) {
+	t.Log("Given the need to retry work that can fail.")
+	{
+		b := conc.Backoff{
+			Initial:    time.Millisecond,
+			Max:        5 * time.Millisecond,
+			Multiplier: 2,
+			Jitter:     0.5,
+		}
+
+		t.Log("\tTest 0:\tWhen the work eventually succeeds.")
+		{
+			checkLeaks(t)
+
+			var calls int
+			err := conc.Retry(context.Background(), b, func(ctx context.Context) error {
+				calls++
+				if calls < 4 {
+					return errors.New("not yet")
+				}
+				return nil
+			})
+
+			if err != nil || calls != 4 {
+				t.Fatalf("\t%s\tTest 0:\tShould succeed on the fourth attempt : calls[%d] err[%v]", failed, calls, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould succeed on the fourth attempt.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the maximum number of attempts is reached.")
+		{
+			checkLeaks(t)
+
+			boom := errors.New("boom")
+			b := b
+			b.MaxAttempts = 3
+
+			var calls int
+			err := conc.Retry(context.Background(), b, func(ctx context.Context) error {
+				calls++
+				return boom
+			})
+
+			if !errors.Is(err, boom) || calls != 3 {
+				t.Fatalf("\t%s\tTest 1:\tShould give up after 3 attempts : calls[%d] err[%v]", failed, calls, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould give up after 3 attempts.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen the context times out.")
+		{
+			checkLeaks(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			err := conc.Retry(ctx, b, func(ctx context.Context) error { return errors.New("always fail") })
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest 2:\tShould receive the deadline error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 2:\tShould receive the deadline error.", succeed)
+		}
+
+		t.Log("\tTest 3:\tWhen calculating the delays.")
+		{
+			b := conc.Backoff{Initial: 10 * time.Millisecond, Max: 50 * time.Millisecond}
+			exp := []time.Duration{10, 20, 40, 50, 50}
+			for i, e := range exp {
+				if d := b.Delay(i + 1); d != e*time.Millisecond {
+					t.Fatalf("\t%s\tTest 3:\tShould grow exponentially to the max : attempt %d got %v", failed, i+1, d)
+				}
+			}
+			t.Logf("\t%s\tTest 3:\tShould grow exponentially to the max.", succeed)
+
+			b.Jitter = 0.5
+			for i := 1; i < 100; i++ {
+				d := b.Delay(3)
+				if d < 20*time.Millisecond || d > 40*time.Millisecond {
+					t.Fatalf("\t%s\tTest 3:\tShould keep jitter within bounds : %v", failed, d)
+				}
+			}
+			t.Logf("\t%s\tTest 3:\tShould keep jitter within bounds.", succeed)
+		}
+
+		t.Log("\tTest 4:\tWhen waiting between attempts on a clock.")
+		{
+			checkLeaks(t)
+
+			f := clock.NewFake(time.Now())
+			b := conc.Backoff{Initial: time.Hour, MaxAttempts: 3, Clock: f}
+
+			var calls atomic.Int64
+			errs := make(chan error, 1)
+			go func() {
+				errs <- conc.Retry(context.Background(), b, func(ctx context.Context) error {
+					calls.Add(1)
+					return errors.New("not yet")
+				})
+			}()
+
+			for attempt := 1; attempt < 3; attempt++ {
+				f.BlockUntil(1)
+				if n := calls.Load(); n != int64(attempt) {
+					t.Fatalf("\t%s\tTest 4:\tShould wait for the delay before attempt %d : %d calls", failed, attempt+1, n)
+				}
+				f.Advance(b.Delay(attempt))
+			}
+			t.Logf("\t%s\tTest 4:\tShould wait for the delay on the clock.", succeed)
+
+			if err := <-errs; err == nil || calls.Load() != 3 {
+				t.Fatalf("\t%s\tTest 4:\tShould give up after 3 attempts : calls[%d] err[%v]", failed, calls.Load(), err)
+			}
+			t.Logf("\t%s\tTest 4:\tShould give up after 3 attempts.", succeed)
+		}
+	}
+}
+
+// TestFirstOf validates the FirstOf functionality.
+func TestFirstOf(t *testing.T) {
+	t.Log("Given the need to use the first result from a set of goroutines.")
+	{
+		t.Log("\tTest 0:\tWhen one goroutine is faster than the others.")
+		{
+			checkLeaks(t)
+
+			slow := func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			fast := func(ctx context.Context) (string, error) { return "fast", nil }
+
+			v, err := conc.FirstOf(context.Background(), slow, slow, fast, slow)
+			if err != nil || v != "fast" {
+				t.Fatalf("\t%s\tTest 0:\tShould receive the fast result : v[%s] err[%v]", failed, v, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive the fast result.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen every goroutine fails.")
+		{
+			checkLeaks(t)
+
+			e1 := errors.New("e1")
+			e2 := errors.New("e2")
+
+			_, err := conc.FirstOf(context.Background(),
+				func(ctx context.Context) (int, error) { return 0, e1 },
+				func(ctx context.Context) (int, error) { return 0, e2 },
+			)
+			if !errors.Is(err, e1) || !errors.Is(err, e2) {
+				t.Fatalf("\t%s\tTest 1:\tShould receive every error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould receive every error.", succeed)
+
+			if _, err := conc.FirstOf[int](context.Background()); !errors.Is(err, conc.ErrNoWork) {
+				t.Fatalf("\t%s\tTest 1:\tShould receive ErrNoWork : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould receive ErrNoWork.", succeed)
+		}
+	}
+}
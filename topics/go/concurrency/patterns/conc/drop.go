@@ -0,0 +1,40 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package conc
+
+import "context"
+
+// Drop receives values from the in channel and sends them on the returned
+// channel, which buffers up to capacity values. When the buffer is full the
+// value is dropped instead of waiting for the receiver, so a slow receiver
+// never holds up the sender. Each dropped value is passed to the dropped
+// function when it isn't nil. The returned channel is closed once the in
+// channel is closed or the context is cancelled.
+func Drop[T any](ctx context.Context, in <-chan T, capacity int, dropped func(T)) <-chan T {
+	out := make(chan T, capacity)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- v:
+				default:
+					if dropped != nil {
+						dropped(v)
+					}
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,46 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package conc
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoWork is returned by FirstOf when no work functions are provided.
+var ErrNoWork = errors.New("no work provided")
+
+// FirstOf performs every work function concurrently and returns the first
+// successful result. Once a result is found the context passed to the
+// remaining work is cancelled. If every work function fails, the errors are
+// joined and returned.
+func FirstOf[T any](ctx context.Context, work ...func(context.Context) (T, error)) (T, error) {
+	var zero T
+	if len(work) == 0 {
+		return zero, ErrNoWork
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// FanOut buffers every result so the goroutines that lose the race
+	// can complete their sends after we return.
+	ch := FanOut(ctx, work...)
+
+	var errs []error
+	for range work {
+		select {
+		case r := <-ch:
+			if r.Err == nil {
+				return r.Value, nil
+			}
+			errs = append(errs, r.Err)
+
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	return zero, errors.Join(errs...)
+}
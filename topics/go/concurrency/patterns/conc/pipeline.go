@@ -0,0 +1,76 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package conc
+
+import "context"
+
+// Generate returns a channel that produces the specified values in order. The
+// channel is closed once all the values have been received or the context is
+// cancelled.
+func Generate[T any](ctx context.Context, values ...T) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Stage represents one step of a pipeline. It receives values from the in
+// channel, applies the work function and sends the result on the returned
+// channel. The returned channel is closed once the in channel is closed and
+// drained, or the context is cancelled. Stages can be chained together by
+// passing the channel returned by one stage into the next.
+func Stage[In, Out any](ctx context.Context, in <-chan In, work func(context.Context, In) Out) <-chan Out {
+	out := make(chan Out)
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case out <- work(ctx, v):
+				case <-ctx.Done():
+					return
+				}
+
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// Collect receives all the values from the in channel until it is closed or
+// the context is cancelled. On cancellation the values received so far are
+// returned with the context error.
+func Collect[T any](ctx context.Context, in <-chan T) ([]T, error) {
+	var values []T
+	for {
+		select {
+		case v, ok := <-in:
+			if !ok {
+				return values, nil
+			}
+			values = append(values, v)
+
+		case <-ctx.Done():
+			return values, ctx.Err()
+		}
+	}
+}
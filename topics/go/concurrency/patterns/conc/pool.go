@@ -0,0 +1,85 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package conc
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrInvalidSize is returned when a pool is requested with a size less
+// than one.
+var ErrInvalidSize = errors.New("pool size must be greater than zero")
+
+// BoundedPool performs the work function for every input using a fixed pool
+// of goroutines. The results are returned in the same order as the inputs. If
+// any work function returns an error, the context passed to the remaining
+// work is cancelled, no new work is started and the first error is returned.
+// The context error is only returned if it stopped some of the inputs from
+// being worked on.
+func BoundedPool[In, Out any](ctx context.Context, size int, inputs []In, work func(context.Context, In) (Out, error)) ([]Out, error) {
+	if size < 1 {
+		return nil, ErrInvalidSize
+	}
+	size = min(size, len(inputs))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Each goroutine writes to its own index in the results slice so
+	// ordering is preserved without any extra synchronization.
+	results := make([]Out, len(inputs))
+
+	var once sync.Once
+	var firstErr error
+
+	var wg sync.WaitGroup
+	wg.Add(size)
+
+	ch := make(chan int)
+
+	for range size {
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				v, err := work(ctx, inputs[idx])
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[idx] = v
+			}
+		}()
+	}
+
+	fed := true
+
+feed:
+	for idx := range inputs {
+		select {
+		case ch <- idx:
+		case <-ctx.Done():
+			fed = false
+			break feed
+		}
+	}
+	close(ch)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	// A context cancelled after the last input was handed out is too late
+	// to matter, since the work still succeeded.
+	if !fed {
+		return nil, ctx.Err()
+	}
+
+	return results, nil
+}
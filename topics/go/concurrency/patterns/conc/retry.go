@@ -0,0 +1,81 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package conc
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// Backoff defines the delays used between attempts by Retry.
+type Backoff struct {
+	Initial     time.Duration // Delay before the second attempt.
+	Max         time.Duration // Upper bound for any delay, zero means no bound.
+	Multiplier  float64       // Growth factor applied after every attempt, defaults to 2.
+	Jitter      float64       // Fraction [0, 1] of each delay that is randomized.
+	MaxAttempts int           // Zero means retry until the context is cancelled.
+	Clock       clock.Clock   // Defaults to the real clock.
+}
+
+// Delay returns the amount of time to wait after the specified attempt has
+// failed. Attempts are counted starting at 1.
+func (b Backoff) Delay(attempt int) time.Duration {
+	mult := b.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+
+	d := float64(b.Initial)
+	for i := 1; i < attempt; i++ {
+		d *= mult
+		if b.Max > 0 && d >= float64(b.Max) {
+			d = float64(b.Max)
+			break
+		}
+	}
+
+	// Randomize a portion of the delay so a group of callers that failed
+	// at the same time don't all retry at the same time.
+	if b.Jitter > 0 {
+		j := min(b.Jitter, 1)
+		d = d*(1-j) + d*j*rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// Retry calls the check function until it succeeds, the maximum number of
+// attempts has been reached or the context is cancelled. Between attempts
+// Retry waits for the delay defined by the backoff. The error from the last
+// attempt is returned when Retry gives up.
+func Retry(ctx context.Context, b Backoff, check func(context.Context) error) error {
+	clk := b.Clock
+	if clk == nil {
+		clk = clock.Real()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if b.MaxAttempts > 0 && attempt >= b.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		t := clk.NewTimer(b.Delay(attempt))
+
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("%w: last error: %w", ctx.Err(), err)
+		case <-t.C():
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package limiter
+
+import (
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// GCRA implements the generic cell rate algorithm. It behaves like a token
+// bucket but only stores a single time per key, the theoretical arrival time
+// (TAT) of the next request if requests arrived exactly at the limit.
+type GCRA struct {
+	burst    int
+	interval time.Duration // Time between requests at the limit.
+	clock    clock.Clock
+	store    *store[time.Time]
+}
+
+// NewGCRA constructs a generic cell rate algorithm limiter.
+func NewGCRA(cfg Config) (*GCRA, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	// A limit of more than one request per nanosecond can't be represented
+	// so the interval is at least a nanosecond.
+	g := GCRA{
+		burst:    cfg.Burst,
+		interval: max(cfg.Period/time.Duration(cfg.Limit), 1),
+		clock:    cfg.Clock,
+	}
+	g.store = newStore(cfg.MaxKeys, cfg.Burst, g.idle)
+
+	return &g, nil
+}
+
+// Allow implements the Limiter interface.
+func (g *GCRA) Allow(key string) Decision {
+	now := g.clock.Now()
+
+	// The amount of time the TAT may run ahead of now.
+	tolerance := g.interval * time.Duration(g.burst)
+
+	return g.store.update(key, now, func(tat *time.Time) Decision {
+		if tat.Before(now) {
+			*tat = now
+		}
+
+		d := Decision{
+			Limit: g.burst,
+		}
+
+		newTAT := tat.Add(g.interval)
+		allowAt := newTAT.Add(-tolerance)
+
+		if now.Before(allowAt) {
+			d.RetryAfter = allowAt.Sub(now)
+		} else {
+			*tat = newTAT
+			d.Allowed = true
+		}
+
+		d.Remaining = int(now.Sub(tat.Add(-tolerance)) / g.interval)
+		d.Reset = tat.Sub(now)
+
+		return d
+	})
+}
+
+// idle returns the time until the TAT is no longer ahead of now.
+func (g *GCRA) idle(tat *time.Time, now time.Time) time.Duration {
+	return max(tat.Sub(now), 0)
+}
+
+// Len returns the number of keys being tracked.
+func (g *GCRA) Len() int {
+	return g.store.len()
+}
@@ -0,0 +1,223 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Package limiter provides per key rate limiting with a choice of algorithms.
+// Each limiter keeps state for every key it has seen, such as a client IP or
+// API key, in a sharded store that tracks up to MaxKeys keys. When the store
+// is full, a least recently used key is evicted for a new key if it's idle,
+// meaning its limit has been fully restored, since an idle key has the same
+// state as a new key.
+//
+// A key that is still limited is never evicted. Instead the request for the
+// new key is denied until a key becomes idle. This fails closed: a client
+// cycling through many keys can't reset the limit of its own key, but it can
+// keep new keys out, so MaxKeys should be larger than the number of keys
+// expected to be active within a Period.
+package limiter
+
+import (
+	"container/list"
+	"errors"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// Limiter represents the behavior of a rate limiting algorithm.
+type Limiter interface {
+	Allow(key string) Decision
+}
+
+// Decision is the outcome of asking a limiter to allow a request.
+type Decision struct {
+	Allowed    bool
+	Limit      int           // Maximum number of requests allowed at once.
+	Remaining  int           // Number of requests still allowed right now.
+	Reset      time.Duration // Time until the limit is fully restored.
+	RetryAfter time.Duration // Time until a denied request may be retried.
+}
+
+// ErrInvalidConfig is returned when a limiter is constructed with a limit
+// or period that is not positive.
+var ErrInvalidConfig = errors.New("limit and period must be greater than zero")
+
+// Config provides the settings shared by every limiter.
+type Config struct {
+	Limit   int           // Number of requests allowed per Period.
+	Period  time.Duration // Period the Limit applies to.
+	Burst   int           // Requests allowed at once, defaults to Limit.
+	MaxKeys int           // Number of keys to track, defaults to 10,000.
+	Clock   clock.Clock   // Defaults to the real clock.
+}
+
+// validate checks the configuration and applies the defaults.
+func (cfg *Config) validate() error {
+	if cfg.Limit <= 0 || cfg.Period <= 0 {
+		return ErrInvalidConfig
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = cfg.Limit
+	}
+	if cfg.MaxKeys <= 0 {
+		cfg.MaxKeys = 10_000
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real()
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// shards is the number of independently locked parts of a store. Spreading
+// the keys over shards reduces lock contention between goroutines.
+const shards = 32
+
+// idleFn returns the time until the state has the same effect as the state
+// of a new key, which is zero if it already does.
+type idleFn[S any] func(state *S, now time.Time) time.Duration
+
+// store maintains the state of type S for a bounded set of keys. The bound
+// applies to the store as a whole, so keys that hash unevenly can't fill a
+// shard while there is room in others.
+type store[S any] struct {
+	seed   maphash.Seed
+	shards [shards]shard[S]
+	max    int          // Number of keys that can be tracked.
+	keys   atomic.Int64 // Number of keys being tracked.
+	limit  int          // Limit reported when a new key can't be tracked.
+	idle   idleFn[S]    // Reports if a key can be evicted.
+}
+
+// shard is a part of the store with its own lock and LRU list.
+type shard[S any] struct {
+	mu    sync.Mutex
+	items map[string]*list.Element
+	lru   list.List
+}
+
+// entry is the value stored in the LRU list.
+type entry[S any] struct {
+	key   string
+	state S
+}
+
+// newStore constructs a store that holds at most maxKeys keys.
+func newStore[S any](maxKeys int, limit int, idle idleFn[S]) *store[S] {
+	s := store[S]{
+		seed:  maphash.MakeSeed(),
+		max:   maxKeys,
+		limit: limit,
+		idle:  idle,
+	}
+
+	for i := range s.shards {
+		s.shards[i].items = make(map[string]*list.Element)
+	}
+
+	return &s
+}
+
+// update calls fn with the state for the key while holding the lock for the
+// key's shard. A new key starts with the zero value of S. If the store is
+// full and no idle key can be evicted, the request is denied without
+// calling fn.
+func (s *store[S]) update(key string, now time.Time, fn func(state *S) Decision) Decision {
+	sh := &s.shards[maphash.String(s.seed, key)%shards]
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	elem, exists := sh.items[key]
+	switch {
+	case exists:
+		sh.lru.MoveToFront(elem)
+
+	default:
+		if !s.reserve() {
+			if wait, ok := s.makeRoom(sh, now); !ok {
+				d := Decision{
+					Limit:      s.limit,
+					Reset:      wait,
+					RetryAfter: wait,
+				}
+				return d
+			}
+		}
+		elem = sh.lru.PushFront(&entry[S]{key: key})
+		sh.items[key] = elem
+	}
+
+	return fn(&elem.Value.(*entry[S]).state)
+}
+
+// reserve takes room for a new key and reports false if the store is full.
+func (s *store[S]) reserve() bool {
+	for {
+		n := s.keys.Load()
+		if n >= int64(s.max) {
+			return false
+		}
+		if s.keys.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// makeRoom evicts an idle key so its room can be used by a new key, looking
+// in the locked shard sh first and then in the other shards. Shards that
+// are locked by other goroutines are passed over, which avoids deadlocks
+// between shards making room at the same time. If no key can be evicted it
+// returns the shortest time seen until one is idle.
+func (s *store[S]) makeRoom(sh *shard[S], now time.Time) (time.Duration, bool) {
+	wait, ok := s.evictIdle(sh, now)
+	if ok {
+		return 0, true
+	}
+
+	for i := range s.shards {
+		other := &s.shards[i]
+		if other == sh || !other.mu.TryLock() {
+			continue
+		}
+		w, ok := s.evictIdle(other, now)
+		other.mu.Unlock()
+
+		switch {
+		case ok:
+			return 0, true
+		case w > 0 && (wait == 0 || w < wait):
+			wait = w
+		}
+	}
+
+	return wait, false
+}
+
+// evictIdle evicts the least recently used key in the shard if it's idle.
+// Otherwise it returns the time until that key is idle, which is zero for
+// an empty shard. The shard's lock must be held.
+func (s *store[S]) evictIdle(sh *shard[S], now time.Time) (time.Duration, bool) {
+	oldest := sh.lru.Back()
+	if oldest == nil {
+		return 0, false
+	}
+
+	e := oldest.Value.(*entry[S])
+	if wait := s.idle(&e.state, now); wait > 0 {
+		return wait, false
+	}
+
+	sh.lru.Remove(oldest)
+	delete(sh.items, e.key)
+	return 0, true
+}
+
+// len returns the number of keys being tracked.
+func (s *store[S]) len() int {
+	return int(s.keys.Load())
+}
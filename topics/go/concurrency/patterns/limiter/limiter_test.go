@@ -0,0 +1,268 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package limiter_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/limiter"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+var start = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// limiterFn constructs a limiter under test.
+type limiterFn func(cfg limiter.Config) (limiter.Limiter, error)
+
+var limiters = []struct {
+	name string
+	new  limiterFn
+}{
+	{"TokenBucket", func(cfg limiter.Config) (limiter.Limiter, error) { return limiter.NewTokenBucket(cfg) }},
+	{"SlidingWindow", func(cfg limiter.Config) (limiter.Limiter, error) { return limiter.NewSlidingWindow(cfg) }},
+	{"GCRA", func(cfg limiter.Config) (limiter.Limiter, error) { return limiter.NewGCRA(cfg) }},
+}
+
+// TestAllow validates every algorithm enforces the limit per key.
+func TestAllow(t *testing.T) {
+	for _, lt := range limiters {
+		t.Logf("Given the need to rate limit with the %s algorithm.", lt.name)
+		{
+			t.Log("\tTest 0:\tWhen a key uses its whole limit.")
+			{
+				f := clock.NewFake(start)
+				l, err := lt.new(limiter.Config{Limit: 5, Period: time.Second, Clock: f})
+				if err != nil {
+					t.Fatalf("\t%s\tTest 0:\tShould be able to construct the limiter : %v", failed, err)
+				}
+
+				for i := range 5 {
+					d := l.Allow("bill")
+					if !d.Allowed || d.Remaining != 4-i || d.Limit != 5 {
+						t.Fatalf("\t%s\tTest 0:\tShould allow request %d : %+v", failed, i, d)
+					}
+				}
+				t.Logf("\t%s\tTest 0:\tShould allow the first 5 requests.", succeed)
+
+				d := l.Allow("bill")
+				if d.Allowed || d.Remaining != 0 || d.RetryAfter <= 0 || d.RetryAfter > time.Second {
+					t.Fatalf("\t%s\tTest 0:\tShould deny the 6th request with a retry time : %+v", failed, d)
+				}
+				t.Logf("\t%s\tTest 0:\tShould deny the 6th request with a retry time.", succeed)
+
+				if d := l.Allow("jill"); !d.Allowed {
+					t.Fatalf("\t%s\tTest 0:\tShould allow a different key : %+v", failed, d)
+				}
+				t.Logf("\t%s\tTest 0:\tShould allow a different key.", succeed)
+
+				f.Advance(d.RetryAfter)
+				if d := l.Allow("bill"); !d.Allowed {
+					t.Fatalf("\t%s\tTest 0:\tShould allow a request after the retry time : %+v", failed, d)
+				}
+				t.Logf("\t%s\tTest 0:\tShould allow a request after the retry time.", succeed)
+
+				f.Advance(time.Second)
+				for i := range 5 {
+					if d := l.Allow("bill"); !d.Allowed {
+						t.Fatalf("\t%s\tTest 0:\tShould restore the limit after the period : request %d : %+v", failed, i, d)
+					}
+				}
+				t.Logf("\t%s\tTest 0:\tShould restore the limit after the period.", succeed)
+			}
+
+			t.Log("\tTest 1:\tWhen more keys are used than can be tracked.")
+			{
+				f := clock.NewFake(start)
+				l, _ := lt.new(limiter.Config{Limit: 1, Period: time.Hour, MaxKeys: 64, Clock: f})
+
+				l.Allow("bill")
+				for i := range 10_000 {
+					l.Allow(strconv.Itoa(i))
+				}
+
+				n := l.(interface{ Len() int }).Len()
+				if n > 64 {
+					t.Fatalf("\t%s\tTest 1:\tShould track no more than 64 keys : %d", failed, n)
+				}
+				t.Logf("\t%s\tTest 1:\tShould track no more than 64 keys : %d", succeed, n)
+
+				// Cycling through keys must not reset a key that is limited.
+				if d := l.Allow("bill"); d.Allowed {
+					t.Fatalf("\t%s\tTest 1:\tShould keep a key that is still limited : %+v", failed, d)
+				}
+				t.Logf("\t%s\tTest 1:\tShould keep a key that is still limited.", succeed)
+
+				if d := l.Allow("jill"); d.Allowed || d.RetryAfter <= 0 || d.RetryAfter > time.Hour {
+					t.Fatalf("\t%s\tTest 1:\tShould deny a new key while every key is limited : %+v", failed, d)
+				}
+				t.Logf("\t%s\tTest 1:\tShould deny a new key while every key is limited.", succeed)
+
+				f.Advance(time.Hour)
+				if d := l.Allow("jill"); !d.Allowed {
+					t.Fatalf("\t%s\tTest 1:\tShould evict an idle key for a new key : %+v", failed, d)
+				}
+				t.Logf("\t%s\tTest 1:\tShould evict an idle key for a new key.", succeed)
+			}
+
+			t.Log("\tTest 2:\tWhen fewer keys can be tracked than there are shards.")
+			{
+				f := clock.NewFake(start)
+				l, _ := lt.new(limiter.Config{Limit: 1, Period: time.Hour, MaxKeys: 5, Clock: f})
+
+				for i := range 100 {
+					l.Allow(strconv.Itoa(i))
+				}
+
+				n := l.(interface{ Len() int }).Len()
+				if n != 5 {
+					t.Fatalf("\t%s\tTest 2:\tShould track exactly 5 keys : %d", failed, n)
+				}
+				t.Logf("\t%s\tTest 2:\tShould track exactly 5 keys.", succeed)
+			}
+
+			t.Log("\tTest 3:\tWhen as many keys are used as can be tracked.")
+			{
+				f := clock.NewFake(start)
+				l, _ := lt.new(limiter.Config{Limit: 1, Period: time.Hour, MaxKeys: 10_000, Clock: f})
+
+				for i := range 10_000 {
+					if d := l.Allow(strconv.Itoa(i)); !d.Allowed {
+						t.Fatalf("\t%s\tTest 3:\tShould admit every key up to MaxKeys : key %d : %+v", failed, i, d)
+					}
+				}
+				t.Logf("\t%s\tTest 3:\tShould admit every key up to MaxKeys.", succeed)
+
+				if d := l.Allow("bill"); d.Allowed {
+					t.Fatalf("\t%s\tTest 3:\tShould deny a key beyond MaxKeys : %+v", failed, d)
+				}
+				t.Logf("\t%s\tTest 3:\tShould deny a key beyond MaxKeys.", succeed)
+			}
+		}
+	}
+
+	t.Log("Given the need to validate the configuration.")
+	{
+		t.Log("\tTest 0:\tWhen the limit is zero.")
+		{
+			for _, lt := range limiters {
+				if _, err := lt.new(limiter.Config{Period: time.Second}); !errors.Is(err, limiter.ErrInvalidConfig) {
+					t.Fatalf("\t%s\tTest 0:\tShould reject the config for %s : %v", failed, lt.name, err)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould reject the config.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the limit is more than one request per nanosecond.")
+		{
+			g, err := limiter.NewGCRA(limiter.Config{Limit: 2_000_000, Period: time.Millisecond})
+			if err != nil {
+				t.Fatalf("\t%s\tTest 1:\tShould construct a GCRA limiter : %v", failed, err)
+			}
+			if d := g.Allow("bill"); !d.Allowed {
+				t.Fatalf("\t%s\tTest 1:\tShould allow a request : %+v", failed, d)
+			}
+			t.Logf("\t%s\tTest 1:\tShould construct a GCRA limiter and allow a request.", succeed)
+		}
+	}
+}
+
+// TestSlidingWindow validates the window slides rather than resets.
+func TestSlidingWindow(t *testing.T) {
+	t.Log("Given the need to count requests over a sliding window.")
+	{
+		t.Log("\tTest 0:\tWhen requests are spread over the window.")
+		{
+			f := clock.NewFake(start)
+			l, _ := limiter.NewSlidingWindow(limiter.Config{Limit: 2, Period: time.Minute, Clock: f})
+
+			l.Allow("bill")
+			f.Advance(40 * time.Second)
+			l.Allow("bill")
+			f.Advance(30 * time.Second)
+
+			// The first request has left the window, the second has not.
+			if d := l.Allow("bill"); !d.Allowed {
+				t.Fatalf("\t%s\tTest 0:\tShould allow once the oldest request leaves : %+v", failed, d)
+			}
+			t.Logf("\t%s\tTest 0:\tShould allow once the oldest request leaves.", succeed)
+
+			d := l.Allow("bill")
+			if d.Allowed || d.RetryAfter != 30*time.Second {
+				t.Fatalf("\t%s\tTest 0:\tShould retry when the next request leaves : %+v", failed, d)
+			}
+			t.Logf("\t%s\tTest 0:\tShould retry when the next request leaves.", succeed)
+		}
+	}
+}
+
+// TestBurst validates the burst is separate from the rate.
+func TestBurst(t *testing.T) {
+	t.Log("Given the need to allow bursts above the steady rate.")
+	{
+		t.Log("\tTest 0:\tWhen a token bucket and GCRA allow a burst of 10 at 1/sec.")
+		{
+			f := clock.NewFake(start)
+			cfg := limiter.Config{Limit: 1, Period: time.Second, Burst: 10, Clock: f}
+			tb, _ := limiter.NewTokenBucket(cfg)
+			g, _ := limiter.NewGCRA(cfg)
+
+			for _, l := range []limiter.Limiter{tb, g} {
+				for i := range 10 {
+					if d := l.Allow("bill"); !d.Allowed {
+						t.Fatalf("\t%s\tTest 0:\tShould allow the burst : request %d : %+v", failed, i, d)
+					}
+				}
+				if d := l.Allow("bill"); d.Allowed || d.RetryAfter != time.Second || d.Reset != 10*time.Second {
+					t.Fatalf("\t%s\tTest 0:\tShould deny after the burst : %+v", failed, d)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould allow the burst and then deny.", succeed)
+
+			f.Advance(3 * time.Second)
+			for _, l := range []limiter.Limiter{tb, g} {
+				for i := range 3 {
+					if d := l.Allow("bill"); !d.Allowed {
+						t.Fatalf("\t%s\tTest 0:\tShould refill at the rate : request %d : %+v", failed, i, d)
+					}
+				}
+				if d := l.Allow("bill"); d.Allowed {
+					t.Fatalf("\t%s\tTest 0:\tShould only refill at the rate : %+v", failed, d)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould refill at the rate.", succeed)
+		}
+	}
+}
+
+// BenchmarkAllow shows the contention between goroutines when 10k distinct
+// keys are being limited at the same time.
+func BenchmarkAllow(b *testing.B) {
+	const keys = 10_000
+
+	names := make([]string, keys)
+	for i := range names {
+		names[i] = "client-" + strconv.Itoa(i)
+	}
+
+	for _, lt := range limiters {
+		b.Run(lt.name, func(b *testing.B) {
+			l, _ := lt.new(limiter.Config{Limit: 100, Period: time.Second, MaxKeys: keys})
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				var i int
+				for pb.Next() {
+					l.Allow(names[i%keys])
+					i += 7919
+				}
+			})
+		})
+	}
+}
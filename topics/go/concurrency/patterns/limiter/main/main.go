@@ -0,0 +1,44 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// This sample program demonstrates how to use the limiter package to rate
+// limit every client IP separately instead of sharing one global limiter.
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/limiter"
+)
+
+func main() {
+
+	// 100/sec per client IP, burst of 200.
+	l, err := limiter.NewTokenBucket(limiter.Config{
+		Limit:  100,
+		Period: time.Second,
+		Burst:  200,
+	})
+	if err != nil {
+		log.Fatalf("error: %s", err)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "OK")
+	})
+
+	http.Handle("/", limiter.Middleware(l, limiter.ByIP, ok))
+
+	addr := os.Getenv("RATE_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+	log.Printf("INFO: server starting on %s", addr)
+	if err := http.ListenAndServe(addr, nil); err != nil {
+		log.Fatalf("error: %s", err)
+	}
+}
@@ -0,0 +1,66 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package limiter
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// KeyFunc extracts the key to rate limit a request by.
+type KeyFunc func(r *http.Request) string
+
+// ByIP keys requests by the IP address of the client.
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ByHeader keys requests by the value of the specified header, such as an
+// API key. Requests without the header share the empty key.
+func ByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// ByRoute keys requests by the method and path of the request.
+func ByRoute(r *http.Request) string {
+	return r.Method + " " + r.URL.Path
+}
+
+// Middleware rate limits the requests passed to the next handler. Every
+// response has the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset
+// headers set and requests that are denied receive a 429 with a Retry-After
+// header.
+func Middleware(l Limiter, key KeyFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := l.Allow(key(r))
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		h.Set("RateLimit-Reset", seconds(d.Reset))
+
+		if !d.Allowed {
+			h.Set("Retry-After", seconds(d.RetryAfter))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// seconds formats a duration as whole seconds, rounding up so clients never
+// retry too early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
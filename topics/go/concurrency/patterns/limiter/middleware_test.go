@@ -0,0 +1,90 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package limiter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/limiter"
+)
+
+// TestMiddleware validates the headers set by the middleware.
+func TestMiddleware(t *testing.T) {
+	t.Log("Given the need to rate limit http requests by client IP.")
+	{
+		f := clock.NewFake(start)
+		l, _ := limiter.NewGCRA(limiter.Config{Limit: 2, Period: 10 * time.Second, Clock: f})
+
+		ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("OK"))
+		})
+		h := limiter.Middleware(l, limiter.ByIP, ok)
+
+		send := func(addr string) *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = addr
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			return w
+		}
+
+		t.Log("\tTest 0:\tWhen the client is within the limit.")
+		{
+			w := send("10.0.0.1:1234")
+			if w.Code != http.StatusOK {
+				t.Fatalf("\t%s\tTest 0:\tShould receive a 200 : %d", failed, w.Code)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive a 200.", succeed)
+
+			exp := map[string]string{"RateLimit-Limit": "2", "RateLimit-Remaining": "1", "RateLimit-Reset": "5"}
+			for k, v := range exp {
+				if got := w.Header().Get(k); got != v {
+					t.Fatalf("\t%s\tTest 0:\tShould set %s to %s : %q", failed, k, v, got)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould set the RateLimit headers.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the client is over the limit.")
+		{
+			send("10.0.0.1:1234")
+			w := send("10.0.0.1:5678")
+			if w.Code != http.StatusTooManyRequests {
+				t.Fatalf("\t%s\tTest 1:\tShould receive a 429 : %d", failed, w.Code)
+			}
+			t.Logf("\t%s\tTest 1:\tShould receive a 429.", succeed)
+
+			if got := w.Header().Get("Retry-After"); got != "5" {
+				t.Fatalf("\t%s\tTest 1:\tShould set Retry-After to 5 : %q", failed, got)
+			}
+			t.Logf("\t%s\tTest 1:\tShould set Retry-After to 5.", succeed)
+
+			if w := send("10.0.0.2:1234"); w.Code != http.StatusOK {
+				t.Fatalf("\t%s\tTest 1:\tShould not limit a different client : %d", failed, w.Code)
+			}
+			t.Logf("\t%s\tTest 1:\tShould not limit a different client.", succeed)
+		}
+	}
+
+	t.Log("Given the need to key requests by API key and route.")
+	{
+		t.Log("\tTest 0:\tWhen extracting the keys.")
+		{
+			r := httptest.NewRequest(http.MethodPost, "/search?q=go", nil)
+			r.Header.Set("X-API-Key", "secret")
+
+			if got := limiter.ByHeader("X-API-Key")(r); got != "secret" {
+				t.Fatalf("\t%s\tTest 0:\tShould key by the API key : %q", failed, got)
+			}
+			if got := limiter.ByRoute(r); got != "POST /search" {
+				t.Fatalf("\t%s\tTest 0:\tShould key by the route : %q", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould extract the keys.", succeed)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package limiter
+
+import (
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// SlidingWindow implements the sliding window log algorithm. The time of
+// every allowed request is kept for each key and a request is allowed if
+// fewer than Limit requests were allowed during the last Period. This is the
+// most accurate algorithm but it stores up to Limit times per key.
+type SlidingWindow struct {
+	limit  int
+	period time.Duration
+	clock  clock.Clock
+	store  *store[window]
+}
+
+// window is the state kept for each key. The times are kept in a ring
+// buffer from oldest to newest starting at start.
+type window struct {
+	times []time.Time
+	start int
+	count int
+}
+
+// NewSlidingWindow constructs a sliding window log limiter.
+func NewSlidingWindow(cfg Config) (*SlidingWindow, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	sw := SlidingWindow{
+		limit:  cfg.Limit,
+		period: cfg.Period,
+		clock:  cfg.Clock,
+	}
+	sw.store = newStore(cfg.MaxKeys, cfg.Limit, sw.idle)
+
+	return &sw, nil
+}
+
+// Allow implements the Limiter interface.
+func (sw *SlidingWindow) Allow(key string) Decision {
+	now := sw.clock.Now()
+
+	return sw.store.update(key, now, func(w *window) Decision {
+		if w.times == nil {
+			w.times = make([]time.Time, sw.limit)
+		}
+
+		// Drop the times that have slid out of the window.
+		cutoff := now.Add(-sw.period)
+		for w.count > 0 && !w.times[w.start].After(cutoff) {
+			w.start = (w.start + 1) % sw.limit
+			w.count--
+		}
+
+		d := Decision{
+			Limit: sw.limit,
+		}
+
+		if w.count < sw.limit {
+			w.times[(w.start+w.count)%sw.limit] = now
+			w.count++
+			d.Allowed = true
+		} else {
+			d.RetryAfter = w.times[w.start].Add(sw.period).Sub(now)
+		}
+
+		d.Remaining = sw.limit - w.count
+		newest := w.times[(w.start+w.count-1)%sw.limit]
+		d.Reset = newest.Add(sw.period).Sub(now)
+
+		return d
+	})
+}
+
+// idle returns the time until every request has slid out of the window.
+func (sw *SlidingWindow) idle(w *window, now time.Time) time.Duration {
+	if w.count == 0 {
+		return 0
+	}
+
+	newest := w.times[(w.start+w.count-1)%sw.limit]
+	return max(newest.Add(sw.period).Sub(now), 0)
+}
+
+// Len returns the number of keys being tracked.
+func (sw *SlidingWindow) Len() int {
+	return sw.store.len()
+}
@@ -0,0 +1,100 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package limiter
+
+import (
+	"math"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// TokenBucket implements the token bucket algorithm. Each key has a bucket
+// that holds up to Burst tokens and is refilled at a rate of Limit tokens
+// per Period. Every request takes a token from the bucket.
+type TokenBucket struct {
+	burst int
+	rate  float64 // Tokens added per nanosecond.
+	clock clock.Clock
+	store *store[bucket]
+}
+
+// bucket is the state kept for each key.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket constructs a token bucket limiter.
+func NewTokenBucket(cfg Config) (*TokenBucket, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	tb := TokenBucket{
+		burst: cfg.Burst,
+		rate:  float64(cfg.Limit) / float64(cfg.Period),
+		clock: cfg.Clock,
+	}
+	tb.store = newStore(cfg.MaxKeys, cfg.Burst, tb.idle)
+
+	return &tb, nil
+}
+
+// Allow implements the Limiter interface.
+func (tb *TokenBucket) Allow(key string) Decision {
+	now := tb.clock.Now()
+
+	return tb.store.update(key, now, func(b *bucket) Decision {
+		burst := float64(tb.burst)
+
+		// Refill the bucket for the time that has passed.
+		switch {
+		case b.last.IsZero():
+			b.tokens = burst
+		default:
+			b.tokens = min(burst, b.tokens+float64(now.Sub(b.last))*tb.rate)
+		}
+		b.last = now
+
+		d := Decision{
+			Limit: tb.burst,
+		}
+
+		if b.tokens >= 1 {
+			b.tokens--
+			d.Allowed = true
+		} else {
+			d.RetryAfter = tb.duration(1 - b.tokens)
+		}
+
+		d.Remaining = int(math.Floor(b.tokens))
+		d.Reset = tb.duration(burst - b.tokens)
+
+		return d
+	})
+}
+
+// idle returns the time until the bucket is full again.
+func (tb *TokenBucket) idle(b *bucket, now time.Time) time.Duration {
+	if b.last.IsZero() {
+		return 0
+	}
+
+	tokens := b.tokens + float64(now.Sub(b.last))*tb.rate
+	if tokens >= float64(tb.burst) {
+		return 0
+	}
+	return tb.duration(float64(tb.burst) - tokens)
+}
+
+// Len returns the number of keys being tracked.
+func (tb *TokenBucket) Len() int {
+	return tb.store.len()
+}
+
+// duration returns the time it takes to refill the specified tokens.
+func (tb *TokenBucket) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / tb.rate))
+}
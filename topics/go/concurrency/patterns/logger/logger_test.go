@@ -0,0 +1,84 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package logger_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/logger"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+// device simulates a disk that blocks on the clock while it has a problem.
+type device struct {
+	clock   clock.Clock
+	mu      sync.Mutex
+	problem bool
+	lines   []string
+}
+
+func (d *device) Write(p []byte) (int, error) {
+	for d.isProblem() {
+		d.clock.Sleep(time.Second)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lines = append(d.lines, strings.TrimSpace(string(p)))
+	return len(p), nil
+}
+
+func (d *device) isProblem() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.problem
+}
+
+func (d *device) setProblem(problem bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.problem = problem
+}
+
+// TestLogger validates writes are dropped while the device is blocked.
+func TestLogger(t *testing.T) {
+	t.Log("Given the need to keep logging when the device is slow.")
+	{
+		t.Log("\tTest 0:\tWhen the device blocks and the buffer fills up.")
+		{
+			f := clock.NewFake(time.Now())
+			d := device{clock: f, problem: true}
+
+			const capacity = 2
+			l := logger.New(&d, capacity)
+
+			// The first write is taken by the writer goroutine which then
+			// blocks on the device waiting for the clock.
+			l.Write("log 1")
+			f.BlockUntil(1)
+
+			// The next writes fill the buffer and the rest are dropped.
+			for i := 2; i <= 5; i++ {
+				l.Write(fmt.Sprintf("log %d", i))
+			}
+
+			d.setProblem(false)
+			f.Advance(time.Second)
+			l.Shutdown()
+
+			exp := []string{"log 1", "log 2", "log 3"}
+			if strings.Join(d.lines, ",") != strings.Join(exp, ",") {
+				t.Fatalf("\t%s\tTest 0:\tShould write the buffered logs and drop the rest : got %v, exp %v", failed, d.lines, exp)
+			}
+			t.Logf("\t%s\tTest 0:\tShould write the buffered logs and drop the rest.", succeed)
+		}
+	}
+}
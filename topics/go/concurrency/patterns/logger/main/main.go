@@ -10,11 +10,13 @@ import (
 	"os/signal"
 	"time"
 
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
 	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/logger"
 )
 
 // device allows us to mock a device we write logs to.
 type device struct {
+	clock   clock.Clock
 	problem bool
 }
 
@@ -23,13 +25,14 @@ func (d *device) Write(p []byte) (n int, err error) {
 	for d.problem {
 
 		// Simulate disk problems.
-		time.Sleep(time.Second)
+		d.clock.Sleep(time.Second)
 	}
 
 	fmt.Print(string(p))
 	return len(p), nil
 }
 
+//runexamples:skip waits for a signal to shut down
 func main() {
 
 	// Number of goroutines that will be writing logs.
@@ -37,7 +40,7 @@ func main() {
 
 	// Create a logger value with a buffer of capacity
 	// for each goroutine that will be logging.
-	var d device
+	d := device{clock: clock.Real()}
 	l := logger.New(&d, grs)
 
 	// Generate goroutines, each writing to disk.
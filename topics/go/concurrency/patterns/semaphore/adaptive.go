@@ -0,0 +1,289 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package semaphore
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// Sample is a measurement taken when a unit of work completes.
+type Sample struct {
+	RTT      time.Duration // How long the work took.
+	InFlight int           // Work in flight when this work was started.
+	Dropped  bool          // The work failed or timed out.
+}
+
+// Algorithm calculates a new concurrency limit from the current limit and
+// a sample. Calls to Update are serialized by the Adaptive limiter.
+type Algorithm interface {
+	Update(limit float64, s Sample) float64
+}
+
+// =============================================================================
+
+// AIMD implements additive increase, multiplicative decrease in the style of
+// TCP congestion control. Every sample is one request out of a window of
+// limit requests, so each sample applies a fraction of the change. Over a
+// full window of successful work the limit grows by one, and over a window of
+// failed or slow work the limit is multiplied by the Backoff ratio.
+type AIMD struct {
+	Timeout time.Duration // Latency treated as a failure, zero disables.
+	Backoff float64       // Ratio to multiply the limit by, defaults to 0.9.
+}
+
+// Update implements the Algorithm interface.
+func (a *AIMD) Update(limit float64, s Sample) float64 {
+	backoff := a.Backoff
+	if backoff <= 0 || backoff >= 1 {
+		backoff = 0.9
+	}
+
+	if s.Dropped || (a.Timeout > 0 && s.RTT > a.Timeout) {
+		return limit * math.Pow(backoff, 1/limit)
+	}
+
+	// Only grow the limit if we are close to using it, otherwise an idle
+	// service would grow the limit without bound.
+	if float64(s.InFlight)*2 >= limit {
+		return limit + 1/limit
+	}
+
+	return limit
+}
+
+// =============================================================================
+
+// Gradient implements a Vegas style algorithm. It tracks the lowest latency
+// seen, which represents the latency with no queueing, and compares it to
+// the latency of each sample. When latency rises above the minimum, queues
+// are building downstream and the limit is reduced in proportion. A small
+// allowance for queueing, the square root of the limit, lets the limit grow
+// while latency stays flat.
+//
+// The latency with no queueing can change, such as when a downstream service
+// moves, so it's measured again every so often. A probe drops the limit to
+// the queueing allowance until the work in flight before the probe has
+// completed, takes the lowest latency seen meanwhile, and restores the limit.
+type Gradient struct {
+	Smoothing     float64 // Weight of each window's new limit, defaults to 0.2.
+	ProbeInterval float64 // Windows of work between probes, defaults to 50.
+
+	minRTT   time.Duration
+	windows  float64       // Windows of work since the last probe.
+	probing  int           // Samples left in the current probe.
+	probeMin time.Duration // Lowest latency seen by the current probe.
+	saved    float64       // Limit to restore after the probe.
+}
+
+// Update implements the Algorithm interface.
+func (g *Gradient) Update(limit float64, s Sample) float64 {
+	smoothing := g.Smoothing
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = 0.2
+	}
+	interval := g.ProbeInterval
+	if interval <= 0 {
+		interval = 50
+	}
+
+	if g.probing > 0 {
+		return g.probe(limit, s)
+	}
+
+	if s.Dropped {
+		return limit * math.Pow(1-smoothing/2, 1/limit)
+	}
+
+	if g.minRTT == 0 || s.RTT < g.minRTT {
+		g.minRTT = s.RTT
+	}
+
+	// Each sample is one request out of a window of limit requests.
+	g.windows += 1 / math.Max(1, limit)
+	if g.windows >= interval {
+		g.windows = 0
+
+		// Wait for a window of work at the old limit to drain and a
+		// window at the probe limit to complete.
+		probe := math.Max(1, math.Sqrt(limit))
+		g.probing = int(math.Ceil(limit + probe))
+		g.saved = limit
+		return probe
+	}
+
+	gradient := math.Max(0.5, math.Min(1, float64(g.minRTT)/float64(s.RTT)))
+	queue := math.Sqrt(limit)
+	target := limit*gradient + queue
+
+	// Like AIMD, each sample is one request out of a window of limit
+	// requests, so a full window moves the limit by the smoothing weight.
+	w := smoothing / math.Max(1, limit)
+
+	return limit*(1-w) + target*w
+}
+
+// probe records a sample taken while probing for the latency with no
+// queueing, and ends the probe once enough samples have been seen.
+func (g *Gradient) probe(limit float64, s Sample) float64 {
+	if !s.Dropped && (g.probeMin == 0 || s.RTT < g.probeMin) {
+		g.probeMin = s.RTT
+	}
+
+	g.probing--
+	if g.probing > 0 {
+		return limit
+	}
+
+	if g.probeMin > 0 {
+		g.minRTT = g.probeMin
+	}
+	g.probeMin = 0
+
+	return g.saved
+}
+
+// =============================================================================
+
+// AdaptiveConfig provides the settings for an adaptive limiter.
+type AdaptiveConfig struct {
+	Initial   int         // Starting limit, defaults to 10.
+	Min       int         // Lowest limit, defaults to 1.
+	Max       int         // Highest limit, defaults to 1000.
+	Algorithm Algorithm   // Defaults to Gradient.
+	Clock     clock.Clock // Used by Do to measure latency, defaults to the real clock.
+}
+
+// Adaptive limits concurrency using a weighted semaphore whose size is
+// adjusted by an algorithm from the latency of completed work.
+type Adaptive struct {
+	sem   *Weighted
+	clock clock.Clock
+	alg   Algorithm
+	min   float64
+	max   float64
+
+	mu       sync.Mutex
+	limit    float64
+	inFlight int
+}
+
+// NewAdaptive constructs an adaptive limiter for use.
+func NewAdaptive(cfg AdaptiveConfig) *Adaptive {
+	if cfg.Min <= 0 {
+		cfg.Min = 1
+	}
+	if cfg.Max <= 0 {
+		cfg.Max = 1000
+	}
+	if cfg.Initial <= 0 {
+		cfg.Initial = 10
+	}
+	cfg.Initial = min(max(cfg.Initial, cfg.Min), cfg.Max)
+	if cfg.Algorithm == nil {
+		cfg.Algorithm = &Gradient{}
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real()
+	}
+
+	a := Adaptive{
+		sem:   NewWeighted(int64(cfg.Initial)),
+		clock: cfg.Clock,
+		alg:   cfg.Algorithm,
+		min:   float64(cfg.Min),
+		max:   float64(cfg.Max),
+		limit: float64(cfg.Initial),
+	}
+
+	return &a
+}
+
+// Token represents permission to perform one unit of work.
+type Token struct {
+	a        *Adaptive
+	inFlight int
+	released bool // Guarded by a.mu.
+}
+
+// Acquire blocks until the work is permitted by the current limit or the
+// context is cancelled. The token must be released once the work is done.
+func (a *Adaptive) Acquire(ctx context.Context) (*Token, error) {
+	if err := a.sem.Acquire(ctx, 1); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	a.inFlight++
+	t := Token{a: a, inFlight: a.inFlight}
+	a.mu.Unlock()
+
+	return &t, nil
+}
+
+// TryAcquire is like Acquire but returns false instead of blocking.
+func (a *Adaptive) TryAcquire() (*Token, bool) {
+	if !a.sem.TryAcquire(1) {
+		return nil, false
+	}
+
+	a.mu.Lock()
+	a.inFlight++
+	t := Token{a: a, inFlight: a.inFlight}
+	a.mu.Unlock()
+
+	return &t, true
+}
+
+// Release reports how long the work took and if it failed, which is used
+// to adjust the limit. Like releasing a semaphore more than it holds,
+// releasing a token twice panics.
+func (t *Token) Release(rtt time.Duration, dropped bool) {
+	a := t.a
+
+	a.mu.Lock()
+	if t.released {
+		a.mu.Unlock()
+		panic("semaphore: token released twice")
+	}
+	t.released = true
+
+	a.inFlight--
+	s := Sample{RTT: rtt, InFlight: t.inFlight, Dropped: dropped}
+	a.limit = min(max(a.alg.Update(a.limit, s), a.min), a.max)
+
+	// Resize while holding the lock so concurrent releases can't apply
+	// their sizes out of order.
+	a.sem.Resize(int64(a.limit))
+	a.mu.Unlock()
+
+	a.sem.Release(1)
+}
+
+// Do performs the work within the limit, measuring how long it takes. Work
+// that returns an error is reported as dropped.
+func (a *Adaptive) Do(ctx context.Context, work func(context.Context) error) error {
+	t, err := a.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	start := a.clock.Now()
+	err = work(ctx)
+	t.Release(a.clock.Now().Sub(start), err != nil)
+
+	return err
+}
+
+// Limit returns the current concurrency limit.
+func (a *Adaptive) Limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return int(a.limit)
+}
@@ -0,0 +1,240 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package semaphore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/semaphore"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+// curve returns the latency of a request given the number of requests in
+// flight downstream during a round of the simulation.
+type curve func(round int, inFlight int) time.Duration
+
+// capacity simulates a downstream service that handles n requests at the
+// base latency and queues the rest, so latency grows linearly past n.
+func capacity(base time.Duration, n int) func(inFlight int) time.Duration {
+	return func(inFlight int) time.Duration {
+		if inFlight <= n {
+			return base
+		}
+		return base * time.Duration(inFlight) / time.Duration(n)
+	}
+}
+
+// simulate drives the limiter with more demand than it permits. Each round
+// acquires every permit available, then releases them all with the latency
+// given by the curve. The limit at the end of each round is returned.
+func simulate(a *semaphore.Adaptive, rounds int, c curve) []int {
+	limits := make([]int, rounds)
+
+	for r := range rounds {
+		var tokens []*semaphore.Token
+		for {
+			t, ok := a.TryAcquire()
+			if !ok {
+				break
+			}
+			tokens = append(tokens, t)
+		}
+
+		rtt := c(r, len(tokens))
+		for _, t := range tokens {
+			t.Release(rtt, false)
+		}
+
+		limits[r] = a.Limit()
+	}
+
+	return limits
+}
+
+// logLimits shows how the limit converged during the simulation.
+func logLimits(t *testing.T, limits []int) {
+	t.Helper()
+	for r := 0; r < len(limits); r += len(limits) / 10 {
+		t.Logf("\t\t\tround %4d : limit %d", r, limits[r])
+	}
+}
+
+// within checks the limit stayed in the range for at least 90% of the rounds
+// specified. The rest allows for the Gradient algorithm's probes.
+func within(limits []int, from, to int, lo, hi int) bool {
+	var in int
+	for _, l := range limits[from:to] {
+		if l >= lo && l <= hi {
+			in++
+		}
+	}
+	return in*10 >= (to-from)*9
+}
+
+// TestConvergence validates the algorithms converge on the capacity of a
+// downstream service and adapt when it changes.
+func TestConvergence(t *testing.T) {
+	const rounds = 600
+	base := 10 * time.Millisecond
+
+	// The downstream service can handle 50 concurrent requests until
+	// round 300, when it slows down and can only handle 20.
+	slower := func(round int, inFlight int) time.Duration {
+		if round < 300 {
+			return capacity(base, 50)(inFlight)
+		}
+		return capacity(base, 20)(inFlight)
+	}
+
+	// The downstream service can handle 50 concurrent requests throughout,
+	// but at round 300 it moves further away and every request takes 20ms
+	// longer.
+	further := func(round int, inFlight int) time.Duration {
+		if round < 300 {
+			return capacity(base, 50)(inFlight)
+		}
+		return capacity(3*base, 50)(inFlight)
+	}
+
+	algorithms := []struct {
+		name string
+		alg  func() semaphore.Algorithm
+	}{
+		{"AIMD", func() semaphore.Algorithm { return &semaphore.AIMD{Timeout: 15 * time.Millisecond} }},
+		{"Gradient", func() semaphore.Algorithm { return &semaphore.Gradient{} }},
+	}
+
+	for _, at := range algorithms {
+		t.Logf("Given the need to adapt the %s limit to the downstream capacity.", at.name)
+		{
+			t.Log("\tTest 0:\tWhen capacity drops from 50 to 20 at round 300.")
+			{
+				a := semaphore.NewAdaptive(semaphore.AdaptiveConfig{Initial: 5, Algorithm: at.alg()})
+				limits := simulate(a, rounds, slower)
+				logLimits(t, limits)
+
+				if !within(limits, 200, 300, 40, 80) {
+					t.Fatalf("\t%s\tTest 0:\tShould converge near 50 : %v", failed, limits[200:300])
+				}
+				t.Logf("\t%s\tTest 0:\tShould converge near 50.", succeed)
+
+				if !within(limits, 500, 600, 15, 35) {
+					t.Fatalf("\t%s\tTest 0:\tShould back off to near 20 : %v", failed, limits[500:600])
+				}
+				t.Logf("\t%s\tTest 0:\tShould back off to near 20.", succeed)
+			}
+		}
+	}
+
+	t.Log("Given the need to follow the latency with no queueing.")
+	{
+		t.Log("\tTest 0:\tWhen the base latency triples at round 300.")
+		{
+			a := semaphore.NewAdaptive(semaphore.AdaptiveConfig{Initial: 5, Algorithm: &semaphore.Gradient{}})
+			limits := simulate(a, rounds, further)
+			logLimits(t, limits)
+
+			if !within(limits, 200, 300, 40, 80) {
+				t.Fatalf("\t%s\tTest 0:\tShould converge near 50 : %v", failed, limits[200:300])
+			}
+			t.Logf("\t%s\tTest 0:\tShould converge near 50.", succeed)
+
+			if !within(limits, 500, 600, 40, 80) {
+				t.Fatalf("\t%s\tTest 0:\tShould recover to near 50 : %v", failed, limits[500:600])
+			}
+			t.Logf("\t%s\tTest 0:\tShould recover to near 50.", succeed)
+		}
+	}
+}
+
+// TestAdaptive validates the limiter bounds and failure handling.
+func TestAdaptive(t *testing.T) {
+	t.Log("Given the need to limit work with an adaptive limiter.")
+	{
+		t.Log("\tTest 0:\tWhen the work keeps failing.")
+		{
+			f := clock.NewFake(time.Now())
+			a := semaphore.NewAdaptive(semaphore.AdaptiveConfig{
+				Initial:   20,
+				Min:       2,
+				Algorithm: &semaphore.AIMD{},
+				Clock:     f,
+			})
+
+			boom := errors.New("boom")
+			for range 500 {
+				err := a.Do(context.Background(), func(ctx context.Context) error {
+					f.Advance(time.Millisecond)
+					return boom
+				})
+				if !errors.Is(err, boom) {
+					t.Fatalf("\t%s\tTest 0:\tShould return the work error : %v", failed, err)
+				}
+			}
+
+			if l := a.Limit(); l != 2 {
+				t.Fatalf("\t%s\tTest 0:\tShould back off to the minimum : %d", failed, l)
+			}
+			t.Logf("\t%s\tTest 0:\tShould back off to the minimum.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the limit is reached.")
+		{
+			a := semaphore.NewAdaptive(semaphore.AdaptiveConfig{Initial: 2})
+
+			t1, _ := a.TryAcquire()
+			a.TryAcquire()
+			if _, ok := a.TryAcquire(); ok {
+				t.Fatalf("\t%s\tTest 1:\tShould not exceed the limit.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould not exceed the limit.", succeed)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if _, err := a.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest 1:\tShould wait for a token until the deadline : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould wait for a token until the deadline.", succeed)
+
+			t1.Release(time.Millisecond, false)
+			if _, ok := a.TryAcquire(); !ok {
+				t.Fatalf("\t%s\tTest 1:\tShould acquire once a token is released.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould acquire once a token is released.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen a token is released twice.")
+		{
+			a := semaphore.NewAdaptive(semaphore.AdaptiveConfig{Initial: 2})
+
+			t1, _ := a.TryAcquire()
+			a.TryAcquire()
+			t1.Release(time.Millisecond, false)
+
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Fatalf("\t%s\tTest 2:\tShould panic on the second release.", failed)
+					}
+				}()
+				t1.Release(time.Millisecond, false)
+			}()
+			t.Logf("\t%s\tTest 2:\tShould panic on the second release.", succeed)
+
+			if _, ok := a.TryAcquire(); !ok {
+				t.Fatalf("\t%s\tTest 2:\tShould acquire the permit released once.", failed)
+			}
+			if _, ok := a.TryAcquire(); ok {
+				t.Fatalf("\t%s\tTest 2:\tShould not free the permit twice.", failed)
+			}
+			t.Logf("\t%s\tTest 2:\tShould not free the permit twice.", succeed)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Package semaphore provides a weighted semaphore with FIFO fairness and an
+// adaptive limiter built on top of it. The buffered channel semaphore used in
+// fanOutSem and freqConcurrentSem can only hand out one permit at a time and
+// has a fixed size. A weighted semaphore lets expensive work take more than
+// one permit, and the adaptive limiter changes the number of permits based
+// on the latency it observes.
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Weighted provides a way to bound concurrent access to a resource. Callers
+// acquire a number of permits and are served in the order they asked, so a
+// large request is never starved by a stream of small ones.
+type Weighted struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+}
+
+// waiter represents a goroutine waiting to acquire permits.
+type waiter struct {
+	n     int64
+	ready chan struct{} // Closed when the permits are granted.
+}
+
+// NewWeighted constructs a semaphore with the specified number of permits.
+func NewWeighted(n int64) *Weighted {
+	return &Weighted{
+		size: n,
+	}
+}
+
+// Acquire acquires n permits, blocking until they are available or the
+// context is cancelled. On cancellation no permits are held and the context
+// error is returned.
+func (s *Weighted) Acquire(ctx context.Context, n int64) error {
+	s.mu.Lock()
+
+	// Take the permits now only if nobody is waiting ahead of us.
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	w := waiter{
+		n:     n,
+		ready: make(chan struct{}),
+	}
+	elem := s.waiters.PushBack(&w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		select {
+		case <-w.ready:
+
+			// The permits were granted after the context was cancelled,
+			// give them back.
+			s.cur -= n
+			s.notify()
+
+		default:
+			isFront := s.waiters.Front() == elem
+			s.waiters.Remove(elem)
+
+			// If we were blocking the waiters behind us, they may now
+			// be able to proceed.
+			if isFront {
+				s.notify()
+			}
+		}
+
+		return ctx.Err()
+	}
+}
+
+// TryAcquire acquires n permits without blocking and reports if it was
+// successful.
+func (s *Weighted) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+
+	return false
+}
+
+// Release returns n permits to the semaphore.
+func (s *Weighted) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		panic("semaphore: released more than held")
+	}
+
+	s.notify()
+}
+
+// Resize changes the number of permits. Shrinking the semaphore does not
+// affect permits already held, new callers wait until enough are released.
+func (s *Weighted) Resize(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.size = n
+	s.notify()
+}
+
+// Size returns the current number of permits.
+func (s *Weighted) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.size
+}
+
+// notify grants permits to the waiters in FIFO order until the waiter at the
+// front can't be satisfied. The lock must be held.
+func (s *Weighted) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+
+		w := front.Value.(*waiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+
+		s.cur += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,136 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package semaphore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+// waitForWaiters blocks until n goroutines are waiting on the semaphore.
+func waitForWaiters(s *Weighted, n int) {
+	for {
+		s.mu.Lock()
+		l := s.waiters.Len()
+		s.mu.Unlock()
+
+		if l >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestWeighted validates the weighted semaphore.
+func TestWeighted(t *testing.T) {
+	t.Log("Given the need to bound access with a weighted semaphore.")
+	{
+		t.Log("\tTest 0:\tWhen acquiring without blocking.")
+		{
+			s := NewWeighted(10)
+
+			if !s.TryAcquire(7) {
+				t.Fatalf("\t%s\tTest 0:\tShould acquire 7 of 10 permits.", failed)
+			}
+			if s.TryAcquire(4) {
+				t.Fatalf("\t%s\tTest 0:\tShould not acquire 4 with 3 permits left.", failed)
+			}
+			if !s.TryAcquire(3) {
+				t.Fatalf("\t%s\tTest 0:\tShould acquire the last 3 permits.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould only acquire the permits available.", succeed)
+
+			s.Release(10)
+			if !s.TryAcquire(10) {
+				t.Fatalf("\t%s\tTest 0:\tShould acquire every permit once released.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould acquire every permit once released.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen a large request is waiting ahead of small ones.")
+		{
+			s := NewWeighted(10)
+			s.TryAcquire(8)
+
+			order := make(chan int64, 2)
+			go func() {
+				s.Acquire(context.Background(), 5)
+				order <- 5
+			}()
+			waitForWaiters(s, 1)
+
+			if s.TryAcquire(1) {
+				t.Fatalf("\t%s\tTest 1:\tShould not let a small request barge ahead.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould not let a small request barge ahead.", succeed)
+
+			go func() {
+				s.Acquire(context.Background(), 1)
+				order <- 1
+			}()
+			waitForWaiters(s, 2)
+
+			// Releasing 3 permits leaves 5 available which is only
+			// enough for the large request at the front.
+			s.Release(3)
+			if first := <-order; first != 5 {
+				t.Fatalf("\t%s\tTest 1:\tShould serve the large request first : %d", failed, first)
+			}
+
+			s.Release(1)
+			if second := <-order; second != 1 {
+				t.Fatalf("\t%s\tTest 1:\tShould serve the small request second : %d", failed, second)
+			}
+			t.Logf("\t%s\tTest 1:\tShould serve waiters in FIFO order.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen a waiter's context is cancelled.")
+		{
+			s := NewWeighted(2)
+			s.TryAcquire(1)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			if err := s.Acquire(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest 2:\tShould return the context error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 2:\tShould return the context error.", succeed)
+
+			if !s.TryAcquire(1) {
+				t.Fatalf("\t%s\tTest 2:\tShould not hold permits for a cancelled waiter.", failed)
+			}
+			t.Logf("\t%s\tTest 2:\tShould not hold permits for a cancelled waiter.", succeed)
+		}
+
+		t.Log("\tTest 3:\tWhen the semaphore is resized.")
+		{
+			s := NewWeighted(1)
+			s.TryAcquire(1)
+
+			done := make(chan error)
+			go func() {
+				done <- s.Acquire(context.Background(), 3)
+			}()
+
+			s.Resize(4)
+			if err := <-done; err != nil {
+				t.Fatalf("\t%s\tTest 3:\tShould wake the waiter when grown : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 3:\tShould wake the waiter when grown.", succeed)
+
+			s.Resize(2)
+			s.Release(3)
+			if s.TryAcquire(2) || !s.TryAcquire(1) {
+				t.Fatalf("\t%s\tTest 3:\tShould respect the smaller size.", failed)
+			}
+			t.Logf("\t%s\tTest 3:\tShould respect the smaller size.", succeed)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// This sample program demonstrates how to use the supervisor package to
+// restart goroutines that fail and shut them down in an orderly way.
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/supervisor"
+)
+
+//runexamples:skip waits for a signal to shut down
+func main() {
+
+	// Shutdown the supervisor when the user hits <ctrl> c.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	// Allow 5 restarts every 10 seconds before giving up.
+	s := supervisor.New(supervisor.Config{
+		MaxRestarts: 5,
+		Window:      10 * time.Second,
+		OnRestart: func(name string, err error) {
+			log.Printf("restarting %s : %v", name, err)
+		},
+	})
+
+	s.Add(supervisor.Child{Name: "database", Strategy: supervisor.OneForAll, Run: worker("database")})
+	s.Add(supervisor.Child{Name: "cache", Strategy: supervisor.OneForOne, Run: worker("cache")})
+	s.Add(supervisor.Child{Name: "http", Strategy: supervisor.OneForOne, Run: worker("http")})
+
+	if err := s.Run(ctx); err != nil {
+		log.Println("supervisor failed :", err)
+		return
+	}
+
+	log.Println("supervisor shutdown")
+}
+
+// worker returns a child that randomly fails.
+func worker(name string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		log.Println("starting", name)
+		defer log.Println("stopped", name)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case <-time.After(time.Duration(rand.Intn(5000)) * time.Millisecond):
+				if rand.Intn(4) == 0 {
+					return errors.New("random failure")
+				}
+				log.Println(name, ": working")
+			}
+		}
+	}
+}
@@ -0,0 +1,339 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Package supervisor provides an errgroup style supervisor for long running
+// goroutines. Children are registered with a restart strategy and the
+// supervisor restarts them when they fail, up to a maximum number of restarts
+// within a time window. When that limit is exceeded the supervisor shuts down
+// every child in the reverse order they were started and reports the failure.
+// Since a supervisor's Run method has the same signature as a child, trees of
+// supervisors can be built by adding one supervisor to another.
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Strategy defines what the supervisor does when a child fails.
+type Strategy int
+
+// Set of restart strategies a child can be registered with.
+const (
+	// OneForOne restarts only the child that failed.
+	OneForOne Strategy = iota
+
+	// OneForAll stops every running child and restarts all of them.
+	OneForAll
+
+	// Never leaves the child down when it fails, like a temporary child in
+	// Erlang. The other children keep running.
+	Never
+
+	// Escalate treats a failure of the child as a failure of the
+	// supervisor, which stops every child and returns the failure.
+	Escalate
+)
+
+// String implements the fmt.Stringer interface.
+func (s Strategy) String() string {
+	switch s {
+	case OneForOne:
+		return "one-for-one"
+	case OneForAll:
+		return "one-for-all"
+	case Never:
+		return "never"
+	case Escalate:
+		return "escalate"
+	}
+	return fmt.Sprintf("Strategy(%d)", int(s))
+}
+
+// ErrTooManyRestarts is returned when the children fail more often than
+// the configured restart intensity allows.
+var ErrTooManyRestarts = errors.New("too many restarts")
+
+// ErrRunning is returned when a child is added to a running supervisor.
+var ErrRunning = errors.New("supervisor is running")
+
+// Config provides the settings for a supervisor.
+type Config struct {
+
+	// MaxRestarts is the number of restarts allowed within the Window
+	// before the supervisor gives up. Zero means no restarts are allowed.
+	MaxRestarts int
+	Window      time.Duration
+
+	// Now reports the current time and defaults to time.Now. Tests can
+	// replace it to control how restarts fall within the window.
+	Now func() time.Time
+
+	// OnRestart is called, if set, every time a child is restarted.
+	OnRestart func(name string, err error)
+
+	// OnDown is called, if set, when a child with the Never strategy
+	// fails and is left down.
+	OnDown func(name string, err error)
+}
+
+// Child represents a goroutine managed by the supervisor. The Run function
+// must return when the context it is given is cancelled. A child that returns
+// a nil error is considered finished and is not restarted.
+type Child struct {
+	Name     string
+	Strategy Strategy
+	Run      func(ctx context.Context) error
+}
+
+// ChildError reports the failure of a specific child.
+type ChildError struct {
+	Name string
+	Err  error
+}
+
+// Error implements the error interface.
+func (ce *ChildError) Error() string {
+	return fmt.Sprintf("child %q: %v", ce.Name, ce.Err)
+}
+
+// Unwrap provides support for errors.Is and errors.As.
+func (ce *ChildError) Unwrap() error {
+	return ce.Err
+}
+
+// Supervisor manages a set of children.
+type Supervisor struct {
+	cfg    Config
+	onFail context.CancelCauseFunc
+
+	mu       sync.Mutex
+	children []Child
+	running  bool
+}
+
+// New constructs a supervisor for use.
+func New(cfg Config) *Supervisor {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &Supervisor{
+		cfg: cfg,
+	}
+}
+
+// WithContext constructs a supervisor and a context derived from ctx. The
+// context is cancelled, with the failure as the cause, if the supervisor
+// fails. This allows a failure to propagate to code that is not a child of
+// the supervisor.
+func WithContext(ctx context.Context, cfg Config) (*Supervisor, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	s := New(cfg)
+	s.onFail = cancel
+
+	return s, ctx
+}
+
+// Add registers a child with the supervisor. Children are started in the
+// order they are added and stopped in the reverse order.
+func (s *Supervisor) Add(c Child) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return ErrRunning
+	}
+
+	s.children = append(s.children, c)
+	return nil
+}
+
+// proc represents a running instance of a child.
+type proc struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	gen    int
+	alive  bool
+}
+
+// exit is sent by a child goroutine when it returns.
+type exit struct {
+	idx int
+	gen int
+	err error
+}
+
+// Run starts every child and supervises them until ctx is cancelled or the
+// supervisor fails. Before Run returns every child has been stopped in the
+// reverse order it was started. Run returns nil on an orderly shutdown.
+func (s *Supervisor) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return ErrRunning
+	}
+	s.running = true
+	children := s.children
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.running = false
+		s.mu.Unlock()
+	}()
+
+	// The children get a context that is not cancelled by the parent, so
+	// the supervisor has control over the order they are stopped in.
+	base := context.WithoutCancel(ctx)
+
+	// quit allows the goroutines of stopped children to walk away from
+	// sending their exit once Run has returned.
+	quit := make(chan struct{})
+	defer close(quit)
+
+	exits := make(chan exit)
+	procs := make([]proc, len(children))
+	var restarts []time.Time
+
+	start := func(idx int) {
+		cctx, cancel := context.WithCancel(base)
+		p := &procs[idx]
+		p.gen++
+		p.cancel = cancel
+		p.done = make(chan struct{})
+		p.alive = true
+
+		gen, done, run := p.gen, p.done, children[idx].Run
+		go func() {
+			err := safeRun(cctx, run)
+			close(done)
+
+			select {
+			case exits <- exit{idx: idx, gen: gen, err: err}:
+			case <-quit:
+			}
+		}()
+	}
+
+	stop := func(idx int) {
+		p := &procs[idx]
+		if p.alive {
+			p.cancel()
+			<-p.done
+			p.alive = false
+		}
+	}
+
+	stopAll := func() {
+		for i := len(procs) - 1; i >= 0; i-- {
+			stop(i)
+		}
+	}
+
+	// allowRestart records a restart and reports if it is within the
+	// configured intensity.
+	allowRestart := func() bool {
+		now := s.cfg.Now()
+		cutoff := now.Add(-s.cfg.Window)
+
+		keep := restarts[:0]
+		for _, t := range restarts {
+			if t.After(cutoff) {
+				keep = append(keep, t)
+			}
+		}
+		restarts = append(keep, now)
+
+		return len(restarts) <= s.cfg.MaxRestarts
+	}
+
+	fail := func(err error) error {
+		stopAll()
+		if s.onFail != nil {
+			s.onFail(err)
+		}
+		return err
+	}
+
+	for i := range children {
+		start(i)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			stopAll()
+			return nil
+
+		case e := <-exits:
+			p := &procs[e.idx]
+
+			// Ignore instances that were stopped by the supervisor.
+			if e.gen != p.gen || !p.alive {
+				continue
+			}
+			p.alive = false
+			p.cancel()
+
+			if e.err == nil {
+				continue
+			}
+
+			child := children[e.idx]
+			cerr := &ChildError{Name: child.Name, Err: e.err}
+
+			switch child.Strategy {
+			case Never:
+				if s.cfg.OnDown != nil {
+					s.cfg.OnDown(child.Name, e.err)
+				}
+				continue
+
+			case Escalate:
+				return fail(cerr)
+			}
+
+			if !allowRestart() {
+				return fail(fmt.Errorf("%w: %w", ErrTooManyRestarts, cerr))
+			}
+
+			if s.cfg.OnRestart != nil {
+				s.cfg.OnRestart(child.Name, e.err)
+			}
+
+			if child.Strategy == OneForOne {
+				start(e.idx)
+				continue
+			}
+
+			// For OneForAll, restart every child that was running at
+			// the time of the failure, including the failed child.
+			var restart []int
+			for i := range procs {
+				if procs[i].alive || i == e.idx {
+					restart = append(restart, i)
+				}
+			}
+			stopAll()
+			for _, i := range restart {
+				start(i)
+			}
+		}
+	}
+}
+
+// safeRun executes the child and converts a panic into an error.
+func safeRun(ctx context.Context, run func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return run(ctx)
+}
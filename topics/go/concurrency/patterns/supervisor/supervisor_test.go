@@ -0,0 +1,393 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package supervisor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/supervisor"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+// fakeClock provides a clock whose time only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.now = fc.now.Add(d)
+}
+
+// recorder keeps track of the events produced by the children.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+// blocker returns a child that records when it starts and stops and runs
+// until it is cancelled.
+func blocker(r *recorder, name string, started chan<- string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		r.add("start " + name)
+		if started != nil {
+			started <- name
+		}
+		<-ctx.Done()
+		r.add("stop " + name)
+		return nil
+	}
+}
+
+// TestCrashLoop validates the supervisor gives up on a crash loop.
+func TestCrashLoop(t *testing.T) {
+	t.Log("Given the need to stop restarting a child that keeps failing.")
+	{
+		t.Log("\tTest 0:\tWhen the child fails faster than the window allows.")
+		{
+			clock := fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+			boom := errors.New("boom")
+
+			var r recorder
+			var runs int
+
+			s := supervisor.New(supervisor.Config{
+				MaxRestarts: 3,
+				Window:      time.Minute,
+				Now:         clock.Now,
+			})
+			s.Add(supervisor.Child{Name: "steady", Strategy: supervisor.OneForOne, Run: blocker(&r, "steady", nil)})
+			s.Add(supervisor.Child{
+				Name:     "crasher",
+				Strategy: supervisor.OneForOne,
+				Run: func(ctx context.Context) error {
+					runs++
+					clock.Advance(time.Second)
+					return boom
+				},
+			})
+
+			err := s.Run(context.Background())
+			if !errors.Is(err, supervisor.ErrTooManyRestarts) || !errors.Is(err, boom) {
+				t.Fatalf("\t%s\tTest 0:\tShould receive ErrTooManyRestarts wrapping the child error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive ErrTooManyRestarts wrapping the child error.", succeed)
+
+			var ce *supervisor.ChildError
+			if !errors.As(err, &ce) || ce.Name != "crasher" {
+				t.Fatalf("\t%s\tTest 0:\tShould identify the failing child : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould identify the failing child.", succeed)
+
+			if runs != 4 {
+				t.Fatalf("\t%s\tTest 0:\tShould run the child 4 times : %d", failed, runs)
+			}
+			t.Logf("\t%s\tTest 0:\tShould run the child 4 times.", succeed)
+
+			if exp := []string{"start steady", "stop steady"}; !slices.Equal(r.get(), exp) {
+				t.Fatalf("\t%s\tTest 0:\tShould stop the other children : got %v", failed, r.get())
+			}
+			t.Logf("\t%s\tTest 0:\tShould stop the other children.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the child fails slower than the window allows.")
+		{
+			clock := fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var restarts int
+			s := supervisor.New(supervisor.Config{
+				MaxRestarts: 1,
+				Window:      time.Minute,
+				Now:         clock.Now,
+				OnRestart:   func(name string, err error) { restarts++ },
+			})
+
+			const crashes = 10
+			var runs int
+			s.Add(supervisor.Child{
+				Name:     "slow-crasher",
+				Strategy: supervisor.OneForOne,
+				Run: func(ctx context.Context) error {
+					runs++
+					if runs > crashes {
+						cancel()
+						<-ctx.Done()
+						return nil
+					}
+					clock.Advance(2 * time.Minute)
+					return errors.New("boom")
+				},
+			})
+
+			if err := s.Run(ctx); err != nil {
+				t.Fatalf("\t%s\tTest 1:\tShould keep restarting the child : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould keep restarting the child.", succeed)
+
+			if restarts != crashes {
+				t.Fatalf("\t%s\tTest 1:\tShould restart the child %d times : %d", failed, crashes, restarts)
+			}
+			t.Logf("\t%s\tTest 1:\tShould restart the child %d times.", succeed, crashes)
+		}
+	}
+}
+
+// TestShutdown validates children are stopped in reverse order.
+func TestShutdown(t *testing.T) {
+	t.Log("Given the need to shutdown children in an orderly way.")
+	{
+		t.Log("\tTest 0:\tWhen the parent context is cancelled.")
+		{
+			var r recorder
+			started := make(chan string, 3)
+
+			s := supervisor.New(supervisor.Config{Now: (&fakeClock{}).Now})
+			for _, name := range []string{"db", "cache", "http"} {
+				s.Add(supervisor.Child{Name: name, Run: blocker(&r, name, started)})
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errs := make(chan error, 1)
+			go func() { errs <- s.Run(ctx) }()
+
+			for range 3 {
+				<-started
+			}
+			cancel()
+
+			if err := <-errs; err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould shutdown without error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould shutdown without error.", succeed)
+
+			exp := []string{"stop http", "stop cache", "stop db"}
+			if got := r.get()[3:]; !slices.Equal(got, exp) {
+				t.Fatalf("\t%s\tTest 0:\tShould stop children in reverse order : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould stop children in reverse order.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen a child registered with Escalate fails.")
+		{
+			var r recorder
+			started := make(chan string, 2)
+			boom := errors.New("boom")
+
+			s, ctx := supervisor.WithContext(context.Background(), supervisor.Config{MaxRestarts: 10, Window: time.Hour})
+			s.Add(supervisor.Child{Name: "a", Run: blocker(&r, "a", started)})
+			s.Add(supervisor.Child{Name: "b", Run: blocker(&r, "b", started)})
+			s.Add(supervisor.Child{
+				Name:     "critical",
+				Strategy: supervisor.Escalate,
+				Run: func(ctx context.Context) error {
+					<-started
+					<-started
+					return boom
+				},
+			})
+
+			err := s.Run(ctx)
+			if !errors.Is(err, boom) || errors.Is(err, supervisor.ErrTooManyRestarts) {
+				t.Fatalf("\t%s\tTest 1:\tShould fail without restarting : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould fail without restarting.", succeed)
+
+			if !errors.Is(context.Cause(ctx), boom) {
+				t.Fatalf("\t%s\tTest 1:\tShould cancel the parent context with the failure : %v", failed, context.Cause(ctx))
+			}
+			t.Logf("\t%s\tTest 1:\tShould cancel the parent context with the failure.", succeed)
+
+			exp := []string{"stop b", "stop a"}
+			if got := r.get()[2:]; !slices.Equal(got, exp) {
+				t.Fatalf("\t%s\tTest 1:\tShould stop children in reverse order : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 1:\tShould stop children in reverse order.", succeed)
+		}
+	}
+}
+
+// TestNever validates a child that isn't restarted stays down.
+func TestNever(t *testing.T) {
+	t.Log("Given the need to run a child only once.")
+	{
+		t.Log("\tTest 0:\tWhen a child registered with Never fails.")
+		{
+			var r recorder
+			started := make(chan string, 2)
+			down := make(chan error, 1)
+			boom := errors.New("boom")
+
+			s := supervisor.New(supervisor.Config{
+				Now:    (&fakeClock{}).Now,
+				OnDown: func(name string, err error) { down <- err },
+			})
+
+			var runs int
+			s.Add(supervisor.Child{Name: "a", Run: blocker(&r, "a", started)})
+			s.Add(supervisor.Child{Name: "b", Run: blocker(&r, "b", started)})
+			s.Add(supervisor.Child{
+				Name:     "once",
+				Strategy: supervisor.Never,
+				Run: func(ctx context.Context) error {
+					runs++
+					return boom
+				},
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errs := make(chan error, 1)
+			go func() { errs <- s.Run(ctx) }()
+
+			for range 2 {
+				<-started
+			}
+
+			if err := <-down; !errors.Is(err, boom) {
+				t.Fatalf("\t%s\tTest 0:\tShould report the child is down : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould report the child is down.", succeed)
+
+			if got := r.get(); len(got) != 2 {
+				t.Fatalf("\t%s\tTest 0:\tShould keep the other children running : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould keep the other children running.", succeed)
+
+			cancel()
+			if err := <-errs; err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould shutdown without error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould shutdown without error.", succeed)
+
+			if runs != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould not restart the child : ran %d times", failed, runs)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not restart the child.", succeed)
+		}
+	}
+}
+
+// TestRunning validates a supervisor can't be changed or run twice while
+// it is running.
+func TestRunning(t *testing.T) {
+	t.Log("Given the need to run a supervisor once at a time.")
+	{
+		t.Log("\tTest 0:\tWhen Add and Run are called during Run.")
+		{
+			started := make(chan string, 1)
+
+			s := supervisor.New(supervisor.Config{Now: (&fakeClock{}).Now})
+			s.Add(supervisor.Child{Name: "a", Run: blocker(&recorder{}, "a", started)})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errs := make(chan error, 1)
+			go func() { errs <- s.Run(ctx) }()
+			<-started
+
+			if err := s.Add(supervisor.Child{Name: "b", Run: blocker(&recorder{}, "b", nil)}); !errors.Is(err, supervisor.ErrRunning) {
+				t.Fatalf("\t%s\tTest 0:\tShould not add a child : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not add a child.", succeed)
+
+			if err := s.Run(ctx); !errors.Is(err, supervisor.ErrRunning) {
+				t.Fatalf("\t%s\tTest 0:\tShould not run twice : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not run twice.", succeed)
+
+			cancel()
+			if err := <-errs; err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould shutdown without error : %v", failed, err)
+			}
+
+			if err := s.Add(supervisor.Child{Name: "b", Run: blocker(&recorder{}, "b", nil)}); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould add a child once stopped : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould add a child once stopped.", succeed)
+		}
+	}
+}
+
+// TestOneForAll validates a failure restarts every child.
+func TestOneForAll(t *testing.T) {
+	t.Log("Given the need to restart dependent children together.")
+	{
+		t.Log("\tTest 0:\tWhen a child registered with OneForAll panics.")
+		{
+			var r recorder
+			started := make(chan string, 10)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var panicErr error
+			s := supervisor.New(supervisor.Config{
+				MaxRestarts: 1,
+				Window:      time.Minute,
+				Now:         (&fakeClock{}).Now,
+				OnRestart:   func(name string, err error) { panicErr = err },
+			})
+
+			var runs int
+			s.Add(supervisor.Child{Name: "a", Run: blocker(&r, "a", started)})
+			s.Add(supervisor.Child{
+				Name:     "b",
+				Strategy: supervisor.OneForAll,
+				Run: func(ctx context.Context) error {
+					runs++
+					<-started
+					if runs == 1 {
+						panic("bad state")
+					}
+					r.add("b running")
+					cancel()
+					<-ctx.Done()
+					return nil
+				},
+			})
+
+			if err := s.Run(ctx); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould recover from the failure : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould recover from the failure.", succeed)
+
+			if panicErr == nil {
+				t.Fatalf("\t%s\tTest 0:\tShould convert the panic into an error.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould convert the panic into an error : %v", succeed, panicErr)
+
+			exp := []string{"start a", "stop a", "start a", "b running", "stop a"}
+			if got := r.get(); !slices.Equal(got, exp) {
+				t.Fatalf("\t%s\tTest 0:\tShould restart every child : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould restart every child.", succeed)
+		}
+	}
+}
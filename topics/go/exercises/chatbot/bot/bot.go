@@ -0,0 +1,270 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Package bot implements a chat bot that recognizes what the user is asking
+// about and hands the question to a skill to answer.
+//
+// Intents are read from a YAML corpus. Input is matched against each
+// intent's patterns, then its keywords and finally a TF-IDF classifier
+// trained from its examples. Each conversation has a Session so follow up
+// questions are understood in the context of the previous answer.
+package bot
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// How an intent was matched.
+const (
+	ByPattern    = "pattern"
+	ByKeyword    = "keyword"
+	ByClassifier = "classifier"
+)
+
+// Match is an intent recognized in the user's input.
+type Match struct {
+	Intent *Intent
+	Input  string
+	By     string            // One of ByPattern, ByKeyword or ByClassifier.
+	Score  float64           // Classifier score, 1 for patterns and keywords.
+	Slots  map[string]string // Named groups captured by a pattern.
+}
+
+// Skill answers the questions for an intent.
+type Skill interface {
+	Respond(s *Session, m Match) (string, error)
+}
+
+// SkillFunc is an adapter to allow the use of ordinary functions as skills.
+type SkillFunc func(s *Session, m Match) (string, error)
+
+// Respond calls f(s, m).
+func (f SkillFunc) Respond(s *Session, m Match) (string, error) {
+	return f(s, m)
+}
+
+// Option changes the behavior of a Bot.
+type Option func(*Bot)
+
+// WithRand sets the source used to pick responses so a conversation can
+// be repeated in tests.
+func WithRand(src rand.Source) Option {
+	return func(b *Bot) {
+		b.rand = rand.New(src)
+	}
+}
+
+// Bot answers questions using the intents in its corpus and the skills
+// registered for them. A Bot can hold many conversations at once.
+type Bot struct {
+	corpus     Corpus
+	intents    map[string]*Intent
+	classifier *classifier
+
+	mu     sync.Mutex
+	skills map[string]Skill
+	rand   *rand.Rand
+}
+
+// New constructs a bot that understands the intents in the corpus.
+func New(c Corpus, opts ...Option) (*Bot, error) {
+	if err := c.compile(); err != nil {
+		return nil, err
+	}
+
+	b := Bot{
+		corpus:     c,
+		intents:    make(map[string]*Intent, len(c.Intents)),
+		classifier: newClassifier(c.Intents),
+		skills:     make(map[string]Skill),
+		rand:       rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+	for _, in := range c.Intents {
+		b.intents[in.Name] = in
+	}
+
+	for _, opt := range opts {
+		opt(&b)
+	}
+
+	return &b, nil
+}
+
+// Register makes the skill answer the questions for the named intent
+// instead of the intent's responses.
+func (b *Bot) Register(intent string, s Skill) error {
+	if _, exists := b.intents[intent]; !exists {
+		return fmt.Errorf("unknown intent %q", intent)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.skills[intent] = s
+	return nil
+}
+
+// Greeting returns what the bot says when a conversation starts.
+func (b *Bot) Greeting() string {
+	return b.corpus.Greeting
+}
+
+// Match finds the intent of the input in the context of the session.
+// Intents that follow the session's topic are tried first.
+func (b *Bot) Match(s *Session, input string) (Match, bool) {
+	var followers, others []*Intent
+	for _, in := range b.corpus.Intents {
+		switch {
+		case len(in.Follows) == 0:
+			others = append(others, in)
+		case in.follows(s.Topic):
+			followers = append(followers, in)
+		}
+	}
+	intents := append(followers, others...)
+
+	for _, in := range intents {
+		for _, re := range in.patterns {
+			sub := re.FindStringSubmatch(input)
+			if sub == nil {
+				continue
+			}
+
+			slots := make(map[string]string)
+			for i, name := range re.SubexpNames() {
+				if name != "" && sub[i] != "" {
+					slots[name] = sub[i]
+				}
+			}
+			return Match{Intent: in, Input: input, By: ByPattern, Score: 1, Slots: slots}, true
+		}
+	}
+
+	words := tokenize(input)
+	for _, in := range intents {
+		for _, kw := range in.keywords {
+			if containsPhrase(words, kw) {
+				return Match{Intent: in, Input: input, By: ByKeyword, Score: 1}, true
+			}
+		}
+	}
+
+	allowed := func(in *Intent) bool { return in.follows(s.Topic) }
+	if in, score := b.classifier.classify(input, allowed); in != nil && score >= b.corpus.Threshold {
+		return Match{Intent: in, Input: input, By: ByClassifier, Score: score}, true
+	}
+
+	return Match{}, false
+}
+
+// Reply answers the input and records the turn in the session.
+func (b *Bot) Reply(s *Session, input string) (string, error) {
+	input = strings.TrimSpace(input)
+
+	m, ok := b.Match(s, input)
+	if !ok {
+		reply := b.respond(b.corpus.Fallback, s.Vars)
+		s.record(Turn{Input: input, Reply: reply})
+		return reply, nil
+	}
+
+	for k, v := range m.Slots {
+		s.Vars[k] = v
+	}
+
+	b.mu.Lock()
+	skill, exists := b.skills[m.Intent.Name]
+	b.mu.Unlock()
+
+	var reply string
+	if exists {
+		var err error
+		if reply, err = skill.Respond(s, m); err != nil {
+			return "", fmt.Errorf("skill %q: %w", m.Intent.Name, err)
+		}
+	} else {
+		reply = b.respond(m.Intent.Responses, s.Vars)
+	}
+
+	s.Topic = m.Intent.Topic
+	s.Ended = m.Intent.End
+	s.record(Turn{Input: input, Intent: m.Intent.Name, Reply: reply})
+
+	return reply, nil
+}
+
+// respond picks one of the responses at random and expands it. Responses
+// that use the most remembered values are preferred, and those that use a
+// name without a value are only picked when there are no others.
+func (b *Bot) respond(responses []string, vars map[string]string) string {
+	var best []string
+	most := -1
+	for _, r := range responses {
+		if strings.ContainsRune(Expand(r, vars), '{') {
+			continue
+		}
+
+		switch used := strings.Count(r, "{"); {
+		case used > most:
+			best, most = []string{r}, used
+		case used == most:
+			best = append(best, r)
+		}
+	}
+	if len(best) > 0 {
+		responses = best
+	}
+
+	if len(responses) == 0 {
+		return ""
+	}
+
+	b.mu.Lock()
+	r := responses[b.rand.IntN(len(responses))]
+	b.mu.Unlock()
+
+	return Expand(r, vars)
+}
+
+// Expand replaces {name} in the text with the value of name in vars.
+// Names without a value are left as they are.
+func Expand(text string, vars map[string]string) string {
+	var b strings.Builder
+
+	for {
+		open := strings.IndexByte(text, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(text[open:], '}')
+		if end < 0 {
+			break
+		}
+		end += open
+
+		b.WriteString(text[:open])
+		if v, exists := vars[text[open+1:end]]; exists {
+			b.WriteString(v)
+		} else {
+			b.WriteString(text[open : end+1])
+		}
+		text = text[end+1:]
+	}
+
+	b.WriteString(text)
+	return b.String()
+}
+
+// containsPhrase reports if the phrase appears in words.
+func containsPhrase(words, phrase []string) bool {
+	for i := range len(words) - len(phrase) + 1 {
+		if slices.Equal(words[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,288 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package bot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// update rewrites the transcripts in testdata with the bot's replies.
+//
+//	go test -update
+var update = flag.Bool("update", false, "update the transcripts in testdata")
+
+// newBot constructs a bot with the default corpus that always picks the
+// same responses.
+func newBot(t *testing.T) *Bot {
+	t.Helper()
+
+	b, err := New(DefaultCorpus(), WithRand(rand.NewPCG(1, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := func(s *Session, m Match) (string, error) {
+		return "It's 3:04PM.", nil
+	}
+	if err := b.Register("time", SkillFunc(clock)); err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestTranscripts(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		fn := func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want, err := ParseTranscript(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b := newBot(t)
+			s := NewSession(file)
+			script := Script{Inputs: slices.Clone(want.Inputs)}
+			if err := b.Run(context.Background(), &script, s); err != nil {
+				t.Fatalf("Run should not error but did: %v", err)
+			}
+
+			if *update {
+				if err := os.WriteFile(file, record(data, b, s, script.Inputs), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !slices.Equal(script.Replies, want.Replies) {
+				t.Errorf("Replies did not match %s", file)
+				t.Logf("Got:\n%s", strings.Join(script.Replies, "\n"))
+				t.Logf("Want:\n%s", strings.Join(want.Replies, "\n"))
+			}
+		}
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".txt"), fn)
+	}
+}
+
+// record writes the conversation in the session as a transcript, keeping
+// the comments at the top of the original and the inputs never read.
+func record(original []byte, b *Bot, s *Session, unread []string) []byte {
+	var buf bytes.Buffer
+
+	sc := bufio.NewScanner(bytes.NewReader(original))
+	for sc.Scan() && strings.HasPrefix(sc.Text(), "#") {
+		fmt.Fprintln(&buf, sc.Text())
+	}
+
+	fmt.Fprintln(&buf, "<", b.Greeting())
+	for _, turn := range s.History {
+		fmt.Fprintln(&buf, ">", turn.Input)
+		fmt.Fprintln(&buf, "<", turn.Reply)
+	}
+	for _, in := range unread {
+		fmt.Fprintln(&buf, ">", in)
+	}
+
+	return buf.Bytes()
+}
+
+func TestMatch(t *testing.T) {
+	b := newBot(t)
+
+	tests := []struct {
+		topic  string
+		input  string
+		intent string
+		by     string
+	}{
+		{"", "What's the weather like?", "weather", ByKeyword},
+		{"", "is it going to be cold today", "weather", ByClassifier},
+		{"", "Call me Ishmael", "hello", ByPattern},
+		{"", "what about tomorrow", "", ""},
+		{"weather", "what about tomorrow", "weather.tomorrow", ByKeyword},
+		{"weather", "will it get better later", "weather.tomorrow", ByClassifier},
+		{"mood", "what about tomorrow", "", ""},
+		{"game", "who won?", "game.score", ByKeyword},
+		{"", "who won?", "game", ByClassifier},
+		{"", "zzz", "", ""},
+	}
+
+	for _, test := range tests {
+		name := fmt.Sprintf("%s/%s", test.topic, test.input)
+		fn := func(t *testing.T) {
+			s := NewSession("test")
+			s.Topic = test.topic
+
+			m, ok := b.Match(s, test.input)
+			if test.intent == "" {
+				if ok {
+					t.Errorf("Should not match an intent. Got %s by %s", m.Intent.Name, m.By)
+				}
+				return
+			}
+
+			if !ok {
+				t.Fatalf("Should match %s but did not", test.intent)
+			}
+			if m.Intent.Name != test.intent || m.By != test.by {
+				t.Errorf("Should match %s by %s. Got %s by %s with %.2f", test.intent, test.by, m.Intent.Name, m.By, m.Score)
+			}
+		}
+		t.Run(name, fn)
+	}
+}
+
+func TestSkills(t *testing.T) {
+	b := newBot(t)
+
+	if err := b.Register("nope", SkillFunc(nil)); err == nil {
+		t.Error("Register should error for an unknown intent but did not")
+	}
+
+	broken := func(s *Session, m Match) (string, error) {
+		return "", errors.New("out of order")
+	}
+	if err := b.Register("time", SkillFunc(broken)); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSession("test")
+	if _, err := b.Reply(s, "what time is it?"); err == nil || !strings.Contains(err.Error(), "out of order") {
+		t.Errorf("Reply should return the skill's error. Got %v", err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"name": "Anna", "team": "Arsenal"}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello {name}!", "Hello Anna!"},
+		{"{name} supports {team}", "Anna supports Arsenal"},
+		{"Hello {nobody}", "Hello {nobody}"},
+		{"unclosed {name", "unclosed {name"},
+		{"nothing to expand", "nothing to expand"},
+	}
+
+	for _, test := range tests {
+		if got := Expand(test.text, vars); got != test.want {
+			t.Errorf("Expand(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestLoadCorpus(t *testing.T) {
+	tests := []struct {
+		name   string
+		corpus string
+	}{
+		{"not yaml", "intents: [["},
+		{"no fallback", "intents: [{name: a, keywords: [a]}]"},
+		{"no name", "fallback: [?]\nintents: [{keywords: [a]}]"},
+		{"duplicate", "fallback: [?]\nintents: [{name: a, keywords: [a]}, {name: a, keywords: [b]}]"},
+		{"nothing to match", "fallback: [?]\nintents: [{name: a, responses: [b]}]"},
+		{"bad pattern", "fallback: [?]\nintents: [{name: a, patterns: ['(']}]"},
+	}
+
+	for _, test := range tests {
+		fn := func(t *testing.T) {
+			_, err := LoadCorpus(strings.NewReader(test.corpus))
+			if !errors.Is(err, ErrInvalidCorpus) {
+				t.Errorf("LoadCorpus should return ErrInvalidCorpus. Got %v", err)
+			}
+		}
+		t.Run(test.name, fn)
+	}
+}
+
+func TestServe(t *testing.T) {
+	b := newBot(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- b.Serve(ctx, l)
+	}()
+
+	// Each connection has its own session so one user's name isn't known
+	// to the other.
+	talk := func(lines ...string) []string {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		fmt.Fprint(conn, strings.Join(lines, "\n")+"\n")
+
+		var replies []string
+		sc := bufio.NewScanner(conn)
+		for sc.Scan() {
+			for _, part := range strings.Split(sc.Text(), "> ") {
+				if reply, ok := strings.CutPrefix(part, "< "); ok {
+					replies = append(replies, reply)
+				}
+			}
+		}
+		return replies
+	}
+
+	first := talk("my name is Anna", "bye")
+	second := talk("bye")
+
+	if len(first) != 3 || first[2] != "Goodbye Anna! Thanks for chatting!" {
+		t.Errorf("First conversation should say goodbye to Anna. Got %q", first)
+	}
+	if len(second) != 2 || strings.Contains(second[1], "Anna") {
+		t.Errorf("Second conversation should not know Anna. Got %q", second)
+	}
+
+	// Leave a conversation open to check it's ended when the server stops.
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	bufio.NewReader(conn).ReadString('\n')
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve should not error when canceled. Got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve should return when canceled")
+	}
+}
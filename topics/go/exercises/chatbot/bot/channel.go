@@ -0,0 +1,216 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package bot
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Channel carries a conversation between a user and the bot. Receive
+// returns io.EOF when the user leaves.
+type Channel interface {
+	Receive() (string, error)
+	Send(reply string) error
+}
+
+// Lines is a Channel that reads a line at a time from r and writes replies
+// to w. It prints a < before each reply and prompts for input with a >,
+// which suits a terminal or a TCP connection.
+type Lines struct {
+	s *bufio.Scanner
+	w io.Writer
+}
+
+// NewLines constructs a Channel that talks over r and w.
+func NewLines(r io.Reader, w io.Writer) *Lines {
+	return &Lines{
+		s: bufio.NewScanner(r),
+		w: w,
+	}
+}
+
+// Receive prompts for and reads the next line.
+func (l *Lines) Receive() (string, error) {
+	if _, err := io.WriteString(l.w, "> "); err != nil {
+		return "", err
+	}
+
+	if !l.s.Scan() {
+		if err := l.s.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return l.s.Text(), nil
+}
+
+// Send writes the reply on its own line.
+func (l *Lines) Send(reply string) error {
+	_, err := fmt.Fprintf(l.w, "< %s\n", reply)
+	return err
+}
+
+// Script is a Channel that plays back scripted input and records the
+// replies, for tests.
+type Script struct {
+	Inputs  []string
+	Replies []string
+}
+
+// Receive returns the next scripted input.
+func (s *Script) Receive() (string, error) {
+	if len(s.Inputs) == 0 {
+		return "", io.EOF
+	}
+
+	in := s.Inputs[0]
+	s.Inputs = s.Inputs[1:]
+	return in, nil
+}
+
+// Send records the reply.
+func (s *Script) Send(reply string) error {
+	s.Replies = append(s.Replies, reply)
+	return nil
+}
+
+// Transcript is a recorded conversation. Lines starting with > are what
+// the user said and lines starting with < what the bot replied. Blank lines
+// and lines starting with # are ignored.
+//
+//	< Welcome to my chatbot!
+//	> how's the weather?
+//	< Not bad, not bad!
+type Transcript struct {
+	Inputs  []string
+	Replies []string
+}
+
+// ParseTranscript reads a transcript.
+func ParseTranscript(r io.Reader) (Transcript, error) {
+	var t Transcript
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, ">"):
+			t.Inputs = append(t.Inputs, strings.TrimSpace(line[1:]))
+		case strings.HasPrefix(line, "<"):
+			t.Replies = append(t.Replies, strings.TrimSpace(line[1:]))
+		default:
+			return Transcript{}, fmt.Errorf("line %d: should start with > or <: %q", n, line)
+		}
+	}
+
+	return t, s.Err()
+}
+
+// Run holds a conversation over the channel until the user leaves, says
+// goodbye or the context is canceled.
+func (b *Bot) Run(ctx context.Context, ch Channel, s *Session) error {
+	if g := b.Greeting(); g != "" {
+		if err := ch.Send(Expand(g, s.Vars)); err != nil {
+			return err
+		}
+	}
+
+	for !s.Ended {
+		input, err := ch.Receive()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+
+		reply, err := b.Reply(s, input)
+		if err != nil {
+			return err
+		}
+
+		if err := ch.Send(reply); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Serve holds a conversation with each connection accepted on the
+// listener until the context is canceled. Every connection gets its own
+// session.
+func (b *Bot) Serve(ctx context.Context, l net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// Closing the listener and connections unblocks Accept and the
+	// conversations waiting to Receive.
+	conns := make(map[net.Conn]struct{})
+	var mu sync.Mutex
+
+	stop := context.AfterFunc(ctx, func() {
+		l.Close()
+
+		mu.Lock()
+		defer mu.Unlock()
+		for c := range conns {
+			c.Close()
+		}
+	})
+	defer stop()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		// The context may have been canceled after the connection was
+		// accepted but before it could be tracked.
+		mu.Lock()
+		conns[conn] = struct{}{}
+		if ctx.Err() != nil {
+			conn.Close()
+		}
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				delete(conns, conn)
+				mu.Unlock()
+				conn.Close()
+			}()
+
+			s := NewSession(conn.RemoteAddr().String())
+			if err := b.Run(ctx, NewLines(conn, conn), s); err != nil && ctx.Err() == nil {
+				slog.Error("conversation", "session", s.ID, "error", err)
+			}
+		}()
+	}
+}
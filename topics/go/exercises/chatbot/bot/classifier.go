@@ -0,0 +1,162 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package bot
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// stopwords are too common to tell intents apart.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "be": true, "do": true,
+	"does": true, "i": true, "in": true, "is": true, "it": true, "me": true,
+	"my": true, "of": true, "on": true, "s": true, "the": true, "to": true,
+	"was": true, "what": true, "you": true, "your": true,
+}
+
+// tokenize splits text into lower case words.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// terms are the words in text worth classifying on.
+func terms(text string) []string {
+	var ts []string
+	for _, w := range tokenize(text) {
+		if !stopwords[w] {
+			ts = append(ts, w)
+		}
+	}
+	return ts
+}
+
+// vector is a sparse TF-IDF vector of unit length.
+type vector map[string]float64
+
+// normalize scales v to unit length.
+func (v vector) normalize() vector {
+	var sum float64
+	for _, w := range v {
+		sum += w * w
+	}
+	if sum == 0 {
+		return v
+	}
+
+	norm := math.Sqrt(sum)
+	for t := range v {
+		v[t] /= norm
+	}
+	return v
+}
+
+// cosine returns the cosine similarity of two unit vectors.
+func (v vector) cosine(o vector) float64 {
+	if len(o) < len(v) {
+		v, o = o, v
+	}
+
+	var dot float64
+	for t, w := range v {
+		dot += w * o[t]
+	}
+	return dot
+}
+
+// classifier scores input against the examples of each intent. Every
+// intent is represented by the centroid of its examples' TF-IDF vectors.
+type classifier struct {
+	idf       map[string]float64
+	centroids map[*Intent]vector
+}
+
+// newClassifier trains a classifier from the examples of the intents.
+func newClassifier(intents []*Intent) *classifier {
+	var docs [][]string
+	df := make(map[string]int)
+
+	for _, in := range intents {
+		for _, ex := range in.Examples {
+			ts := terms(ex)
+			docs = append(docs, ts)
+
+			seen := make(map[string]bool)
+			for _, t := range ts {
+				if !seen[t] {
+					seen[t] = true
+					df[t]++
+				}
+			}
+		}
+	}
+
+	// Smoothed so terms in every example still count for something.
+	idf := make(map[string]float64, len(df))
+	for t, n := range df {
+		idf[t] = math.Log(float64(1+len(docs))/float64(1+n)) + 1
+	}
+
+	c := classifier{
+		idf:       idf,
+		centroids: make(map[*Intent]vector),
+	}
+
+	for _, in := range intents {
+		if len(in.Examples) == 0 {
+			continue
+		}
+
+		centroid := make(vector)
+		for _, ex := range in.Examples {
+			for t, w := range c.vectorize(terms(ex)) {
+				centroid[t] += w
+			}
+		}
+		c.centroids[in] = centroid.normalize()
+	}
+
+	return &c
+}
+
+// vectorize returns the TF-IDF vector of the terms. Terms the classifier
+// wasn't trained with are ignored.
+func (c *classifier) vectorize(ts []string) vector {
+	v := make(vector)
+	for _, t := range ts {
+		if idf, known := c.idf[t]; known {
+			v[t] += idf
+		}
+	}
+	return v.normalize()
+}
+
+// classify returns the intent most like the input of those allowed and its
+// score between 0 and 1.
+func (c *classifier) classify(input string, allowed func(*Intent) bool) (*Intent, float64) {
+	v := c.vectorize(terms(input))
+	if len(v) == 0 {
+		return nil, 0
+	}
+
+	var best *Intent
+	var score float64
+	for in, centroid := range c.centroids {
+		if !allowed(in) {
+			continue
+		}
+
+		// Ties go to the intent named first so results don't depend on the
+		// order of the map.
+		s := centroid.cosine(v)
+		if s > score || (s == score && best != nil && in.Name < best.Name) {
+			best, score = in, s
+		}
+	}
+
+	return best, score
+}
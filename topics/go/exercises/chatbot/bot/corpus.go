@@ -0,0 +1,143 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package bot
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Intent is something a user might ask the bot and how to recognize it.
+type Intent struct {
+	Name string `yaml:"name"` // Used to register skills, ex. "weather".
+
+	// Keywords are words or phrases that match the intent when they appear
+	// in the input, ex. "weather" or "how are you".
+	Keywords []string `yaml:"keywords"`
+
+	// Patterns are regular expressions that match the intent. Their named
+	// groups are remembered in the session, ex. `my name is (?P<name>\w+)`.
+	Patterns []string `yaml:"patterns"`
+
+	// Examples are sentences the classifier is trained with when neither
+	// keywords nor patterns match.
+	Examples []string `yaml:"examples"`
+
+	// Responses are picked from at random when no skill is registered for
+	// the intent. Values remembered in the session can be used like {name}.
+	Responses []string `yaml:"responses"`
+
+	// Follows limits the intent to follow up questions about one of these
+	// topics, ex. "what about tomorrow?" after asking about the weather.
+	Follows []string `yaml:"follows"`
+
+	// Topic is what the conversation is about after the intent matches. It
+	// defaults to the intent's name.
+	Topic string `yaml:"topic"`
+
+	// End finishes the conversation after responding.
+	End bool `yaml:"end"`
+
+	patterns []*regexp.Regexp
+	keywords [][]string
+}
+
+// follows reports if the intent can match in a conversation about topic.
+func (in *Intent) follows(topic string) bool {
+	return len(in.Follows) == 0 || slices.Contains(in.Follows, topic)
+}
+
+// Corpus is the set of intents a bot understands.
+type Corpus struct {
+	Greeting  string    `yaml:"greeting"`  // Sent when a conversation starts.
+	Fallback  []string  `yaml:"fallback"`  // Sent when no intent matches.
+	Threshold float64   `yaml:"threshold"` // Lowest classifier score to accept.
+	Intents   []*Intent `yaml:"intents"`
+}
+
+// DefaultThreshold is used when the corpus doesn't set a threshold.
+const DefaultThreshold = 0.3
+
+// ErrInvalidCorpus is returned when a corpus can't be used.
+var ErrInvalidCorpus = errors.New("invalid corpus")
+
+//go:embed corpus.yaml
+var defaultCorpus string
+
+// DefaultCorpus returns the corpus the bot ships with. It talks about the
+// weather, how it's feeling and the game last night.
+func DefaultCorpus() Corpus {
+	c, err := LoadCorpus(strings.NewReader(defaultCorpus))
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// LoadCorpus reads a YAML corpus and validates it.
+func LoadCorpus(r io.Reader) (Corpus, error) {
+	var c Corpus
+	if err := yaml.NewDecoder(r).Decode(&c); err != nil {
+		return Corpus{}, fmt.Errorf("%w: %w", ErrInvalidCorpus, err)
+	}
+
+	if err := c.compile(); err != nil {
+		return Corpus{}, err
+	}
+
+	return c, nil
+}
+
+// compile validates the corpus and prepares its intents for matching.
+func (c *Corpus) compile() error {
+	if c.Threshold == 0 {
+		c.Threshold = DefaultThreshold
+	}
+	if len(c.Fallback) == 0 {
+		return fmt.Errorf("%w: no fallback responses", ErrInvalidCorpus)
+	}
+
+	names := make(map[string]bool)
+	for _, in := range c.Intents {
+		if in.Name == "" {
+			return fmt.Errorf("%w: intent has no name", ErrInvalidCorpus)
+		}
+		if names[in.Name] {
+			return fmt.Errorf("%w: intent %q is defined twice", ErrInvalidCorpus, in.Name)
+		}
+		names[in.Name] = true
+
+		if len(in.Keywords)+len(in.Patterns)+len(in.Examples) == 0 {
+			return fmt.Errorf("%w: intent %q has no keywords, patterns or examples", ErrInvalidCorpus, in.Name)
+		}
+		if in.Topic == "" {
+			in.Topic = in.Name
+		}
+
+		in.keywords = nil
+		for _, k := range in.Keywords {
+			if words := tokenize(k); len(words) > 0 {
+				in.keywords = append(in.keywords, words)
+			}
+		}
+
+		in.patterns = nil
+		for _, p := range in.Patterns {
+			re, err := regexp.Compile("(?i)" + p)
+			if err != nil {
+				return fmt.Errorf("%w: intent %q: %w", ErrInvalidCorpus, in.Name, err)
+			}
+			in.patterns = append(in.patterns, re)
+		}
+	}
+
+	return nil
+}
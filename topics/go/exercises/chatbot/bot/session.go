@@ -0,0 +1,48 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package bot
+
+// Turn is one exchange in a conversation.
+type Turn struct {
+	Input  string // What the user said.
+	Intent string // The intent matched, empty when none did.
+	Reply  string // What the bot said.
+}
+
+// Session is the state of one conversation. It lets follow up questions
+// be understood and values the user gave be remembered.
+type Session struct {
+	ID      string
+	Topic   string            // What the conversation is currently about.
+	Vars    map[string]string // Remembered values, ex. the user's name.
+	History []Turn
+	Ended   bool // The user said goodbye.
+}
+
+// NewSession starts a conversation.
+func NewSession(id string) *Session {
+	return &Session{
+		ID:   id,
+		Vars: make(map[string]string),
+	}
+}
+
+// Last returns the most recent turn of the conversation.
+func (s *Session) Last() (Turn, bool) {
+	if len(s.History) == 0 {
+		return Turn{}, false
+	}
+	return s.History[len(s.History)-1], true
+}
+
+// maxHistory is how many turns a session remembers.
+const maxHistory = 50
+
+// record adds a turn to the history, forgetting the oldest turns.
+func (s *Session) record(t Turn) {
+	s.History = append(s.History, t)
+	if n := len(s.History) - maxHistory; n > 0 {
+		s.History = append(s.History[:0], s.History[n:]...)
+	}
+}
@@ -0,0 +1,101 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// This program runs the chat bot on the terminal or, with -listen, for
+// every client that connects over TCP.
+//
+// ./chatbot
+// ./chatbot -listen localhost:4000 -corpus corpus.yaml
+// nc localhost 4000
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/exercises/chatbot/bot"
+)
+
+func main() {
+	listen := flag.String("listen", "", "address to serve conversations on over TCP instead of the terminal")
+	corpus := flag.String("corpus", "", "YAML corpus to use instead of the built in one")
+	typing := flag.Bool("typing", true, "pause between characters on the terminal as if someone is typing")
+	flag.Parse()
+
+	c := bot.DefaultCorpus()
+	if *corpus != "" {
+		f, err := os.Open(*corpus)
+		if err != nil {
+			log.Fatal(err)
+		}
+		c, err = bot.LoadCorpus(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	b, err := bot.New(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The error is ignored since a custom corpus might not have a time
+	// intent to answer.
+	b.Register("time", bot.SkillFunc(tellTime))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *listen != "" {
+		l, err := net.Listen("tcp", *listen)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Println("chatbot listening on", l.Addr())
+
+		if err := b.Serve(ctx, l); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if *typing {
+		w = typist{w: os.Stdout}
+	}
+
+	if err := b.Run(ctx, bot.NewLines(os.Stdin, w), bot.NewSession("terminal")); err != nil && ctx.Err() == nil {
+		log.Fatal("could not chat: ", err)
+	}
+}
+
+// tellTime is a skill that answers with the time of day.
+func tellTime(s *bot.Session, m bot.Match) (string, error) {
+	return fmt.Sprintf("It's %s.", time.Now().Format(time.Kitchen)), nil
+}
+
+// typist writes one character at a time with a short random delay between
+// them so it looks like someone is typing.
+type typist struct {
+	w io.Writer
+}
+
+// Write implements the io.Writer interface.
+func (t typist) Write(p []byte) (int, error) {
+	for i, r := range string(p) {
+		time.Sleep(time.Duration(rand.IntN(50)+1) * time.Millisecond)
+		if _, err := io.WriteString(t.w, string(r)); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
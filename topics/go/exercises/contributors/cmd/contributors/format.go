@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	svg "github.com/ajstarks/svgo"
+)
+
+// formatter writes a report of the contributors.
+type formatter func(w io.Writer, cons []Contributor) error
+
+// formats are the report formats by name.
+var formats = map[string]formatter{
+	"table":    writeTable,
+	"csv":      writeCSV,
+	"json":     writeJSON,
+	"markdown": writeMarkdown,
+	"svg":      writeSVG,
+}
+
+// formatNames returns the names of the report formats in order.
+func formatNames() []string {
+	return slices.Sorted(maps.Keys(formats))
+}
+
+// writeTable writes the contributors as aligned columns.
+func writeTable(w io.Writer, cons []Contributor) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "RANK\tLOGIN\tCONTRIBUTIONS\tREPOS")
+	for i, c := range cons {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\n", i+1, c.Login, c.Contributions, strings.Join(c.Repos, ", "))
+	}
+
+	return tw.Flush()
+}
+
+// writeCSV writes the contributors as CSV with a header row. A contributor's
+// repos are separated by spaces.
+func writeCSV(w io.Writer, cons []Contributor) error {
+	cw := csv.NewWriter(w)
+
+	cw.Write([]string{"rank", "login", "contributions", "repos"})
+	for i, c := range cons {
+		cw.Write([]string{strconv.Itoa(i + 1), c.Login, strconv.Itoa(c.Contributions), strings.Join(c.Repos, " ")})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes the contributors as an indented JSON array.
+func writeJSON(w io.Writer, cons []Contributor) error {
+	if cons == nil {
+		cons = []Contributor{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cons)
+}
+
+// writeMarkdown writes the contributors as a GitHub flavored Markdown table.
+func writeMarkdown(w io.Writer, cons []Contributor) error {
+	var b bytes.Buffer
+
+	b.WriteString("| Rank | Login | Contributions | Repos |\n")
+	b.WriteString("| ---: | :--- | ---: | :--- |\n")
+	for i, c := range cons {
+		fmt.Fprintf(&b, "| %d | [%s](https://github.com/%s) | %d | %s |\n", i+1, c.Login, c.Login, c.Contributions, strings.Join(c.Repos, ", "))
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Dimensions of the SVG bar chart in pixels.
+const (
+	chartLabels = 160 // Width of the login column.
+	chartBars   = 480 // Width of the longest bar.
+	chartMargin = 60  // Room for the count after the longest bar.
+	chartRow    = 24  // Height of each bar including the gap below it.
+	chartTop    = 40  // Height of the title.
+)
+
+// writeSVG writes the contributors as a horizontal bar chart, one bar per
+// contributor scaled to the one with the most contributions.
+func writeSVG(w io.Writer, cons []Contributor) error {
+	most := 1
+	for _, c := range cons {
+		most = max(most, c.Contributions)
+	}
+
+	width := chartLabels + chartBars + chartMargin
+	height := chartTop + len(cons)*chartRow + chartRow/2
+
+	// The canvas doesn't report write errors so draw into a buffer first.
+	var b bytes.Buffer
+	canvas := svg.New(&b)
+	canvas.Start(width, height)
+	canvas.Title("Contributors")
+	canvas.Rect(0, 0, width, height, "fill:white")
+	canvas.Text(width/2, chartTop/2+6, "Contributors", "text-anchor:middle;font-size:18px;font-family:sans-serif")
+
+	canvas.Gstyle("font-size:12px;font-family:sans-serif")
+	for i, c := range cons {
+		y := chartTop + i*chartRow
+		bar := max(c.Contributions*chartBars/most, 1)
+
+		canvas.Text(chartLabels-8, y+chartRow/2+2, c.Login, "text-anchor:end")
+		canvas.Rect(chartLabels, y+2, bar, chartRow-6, "fill:steelblue")
+		canvas.Text(chartLabels+bar+6, y+chartRow/2+2, strconv.Itoa(c.Contributions))
+	}
+	canvas.Gend()
+	canvas.End()
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
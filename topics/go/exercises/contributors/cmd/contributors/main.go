@@ -0,0 +1,157 @@
+// This program reports who contributed to one or more GitHub repos. The
+// contributors to each repo are merged by login.
+//
+// export GITHUB_TOKEN=000a0aaaa0000a00000000aaa00000000a000000
+// ./contributors -format markdown -top 10 golang/go golang/tools
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ardanlabs/gotraining/topics/go/exercises/contributors/part3/github"
+)
+
+// config is how a report was asked for on the command line.
+type config struct {
+	api     string
+	format  string
+	sort    string
+	top     int
+	workers int
+	output  string
+	repos   []string
+}
+
+// parseFlags reads the config from the command line arguments.
+func parseFlags(args []string) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("contributors", flag.ContinueOnError)
+	fs.StringVar(&cfg.api, "api", github.API, "root url of the GitHub API")
+	fs.StringVar(&cfg.format, "format", "table", "report format: "+strings.Join(formatNames(), ", "))
+	fs.StringVar(&cfg.sort, "sort", SortContributions, "sort by "+strings.Join([]string{SortContributions, SortLogin, SortRepos}, ", "))
+	fs.IntVar(&cfg.top, "top", 0, "only report this many contributors, 0 reports all of them")
+	fs.IntVar(&cfg.workers, "workers", 4, "repos to query at the same time")
+	fs.StringVar(&cfg.output, "o", "", "file to write the report to instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: contributors [flags] owner/repo...")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	// GitHub repo names aren't case sensitive, so a repo named twice would
+	// have its contributions counted twice.
+	seen := make(map[string]bool)
+	for _, repo := range fs.Args() {
+		key := strings.ToLower(repo)
+		if !seen[key] {
+			seen[key] = true
+			cfg.repos = append(cfg.repos, repo)
+		}
+	}
+
+	switch {
+	case len(cfg.repos) == 0:
+		return config{}, errors.New("at least one repo is required")
+	case formats[cfg.format] == nil:
+		return config{}, fmt.Errorf("unknown format %q", cfg.format)
+	case cfg.sort != SortContributions && cfg.sort != SortLogin && cfg.sort != SortRepos:
+		return config{}, fmt.Errorf("unknown sort order %q", cfg.sort)
+	case cfg.top < 0:
+		return config{}, errors.New("top can't be negative")
+	case cfg.workers < 1:
+		return config{}, errors.New("at least one worker is required")
+	}
+
+	return cfg, nil
+}
+
+func main() {
+	cfg, err := parseFlags(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		log.Print(err)
+		os.Exit(2)
+	}
+
+	tkn := os.Getenv("GITHUB_TOKEN")
+	if tkn == "" {
+		log.Print("Token not found. You must set it in your environment like")
+		log.Print("export GITHUB_TOKEN=000a0aaaa0000a00000000aaa00000000a000000")
+		log.Print("You can generate a token at https://github.com/settings/tokens")
+		os.Exit(1)
+	}
+
+	c, err := github.NewClient(cfg.api, tkn, github.WaitForRateLimit())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.output == "" {
+		if err := report(context.Background(), os.Stdout, cfg, c); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := writeReport(context.Background(), cfg.output, cfg, c); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeReport writes the report to a temporary file next to name and renames
+// it into place once it's complete, so a report that fails partway through
+// doesn't leave a truncated file behind or replace a good one.
+func writeReport(ctx context.Context, name string, cfg config, c contributorLister) error {
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := report(ctx, f, cfg, c); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), name)
+}
+
+// report collects the contributors to the configured repos and writes them
+// in the configured format.
+func report(ctx context.Context, w io.Writer, cfg config, c contributorLister) error {
+	cons, err := collect(ctx, c, cfg.repos, cfg.workers)
+	if err != nil {
+		return err
+	}
+
+	if err := sortContributors(cons, cfg.sort); err != nil {
+		return err
+	}
+
+	if cfg.top > 0 && len(cons) > cfg.top {
+		cons = cons[:cfg.top]
+	}
+
+	return formats[cfg.format](w, cons)
+}
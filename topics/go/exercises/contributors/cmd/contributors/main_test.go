@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/exercises/contributors/githubmock"
+	"github.com/ardanlabs/gotraining/topics/go/exercises/contributors/part3/github"
+)
+
+// update rewrites the golden files with the output of the tests.
+//
+//	go test -update
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// token is accepted by the mock server.
+const token = "781b0bdab2315c62134544eff45811333c663797"
+
+// fixtures are three repos that share some of their contributors.
+const fixtures = `{
+	"repos": [
+		{"full_name": "gophers/alpha", "contributors": [
+			{"login": "anna", "contributions": 40},
+			{"login": "jacob", "contributions": 12},
+			{"login": "kell", "contributions": 3}
+		]},
+		{"full_name": "gophers/beta", "contributors": [
+			{"login": "jacob", "contributions": 25},
+			{"login": "carter", "contributions": 18},
+			{"login": "anna", "contributions": 2}
+		]},
+		{"full_name": "gophers/gamma", "contributors": [
+			{"login": "rory", "contributions": 30},
+			{"login": "kell", "contributions": 9},
+			{"login": "jacob", "contributions": 1}
+		]}
+	]
+}`
+
+// newClient starts a mock server loaded with the fixtures and returns a
+// client for it.
+func newClient(t *testing.T) *github.Client {
+	t.Helper()
+
+	srv := githubmock.NewServer(t)
+	if err := srv.Load(strings.NewReader(fixtures)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two contributors per page makes every repo take more than one request.
+	srv.SetMaxPerPage(2)
+
+	c, err := github.NewClient(srv.URL, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestReport(t *testing.T) {
+	c := newClient(t)
+
+	tests := []struct {
+		golden string
+		args   []string
+	}{
+		{"table.golden", []string{"-format", "table"}},
+		{"csv.golden", []string{"-format", "csv"}},
+		{"json.golden", []string{"-format", "json"}},
+		{"markdown.golden", []string{"-format", "markdown"}},
+		{"svg.golden", []string{"-format", "svg"}},
+		{"sort-login.golden", []string{"-sort", "login"}},
+		{"sort-repos-top.golden", []string{"-sort", "repos", "-top", "3"}},
+		{"one-worker.golden", []string{"-workers", "1", "-top", "2", "-format", "csv"}},
+	}
+
+	for _, test := range tests {
+		fn := func(t *testing.T) {
+			args := slices.Concat(test.args, []string{"gophers/alpha", "gophers/beta", "gophers/gamma"})
+
+			cfg, err := parseFlags(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if err := report(context.Background(), &buf, cfg, c); err != nil {
+				t.Fatalf("report should not error but did: %v", err)
+			}
+
+			golden := filepath.Join("testdata", test.golden)
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != string(want) {
+				t.Errorf("Report did not match %s", golden)
+				t.Logf("Got:\n%s", got)
+				t.Logf("Want:\n%s", want)
+			}
+		}
+		t.Run(strings.TrimSuffix(test.golden, ".golden"), fn)
+	}
+}
+
+func TestReportFailure(t *testing.T) {
+	c := newClient(t)
+
+	cfg, err := parseFlags([]string{"gophers/alpha", "gophers/missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = report(context.Background(), &buf, cfg, c)
+	if err == nil || !strings.Contains(err.Error(), "gophers/missing") {
+		t.Errorf("report should name the repo that failed. Got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Nothing should be written when a repo fails. Got %q", buf.String())
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	c := newClient(t)
+	name := filepath.Join(t.TempDir(), "report.svg")
+
+	cfg, err := parseFlags([]string{"-format", "svg", "gophers/alpha"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeReport(context.Background(), name, cfg, c); err != nil {
+		t.Fatalf("writeReport should not error. Got %v", err)
+	}
+	good, err := os.ReadFile(name)
+	if err != nil || len(good) == 0 {
+		t.Fatalf("The report should be written. Got %q, %v", good, err)
+	}
+
+	cfg.repos = append(cfg.repos, "gophers/missing")
+	if err := writeReport(context.Background(), name, cfg, c); err == nil {
+		t.Error("writeReport should error when a repo fails")
+	}
+
+	if got, err := os.ReadFile(name); err != nil || !bytes.Equal(got, good) {
+		t.Errorf("A failed report should leave the old file alone. Got %q, %v", got, err)
+	}
+	if files, _ := os.ReadDir(filepath.Dir(name)); len(files) != 1 {
+		t.Errorf("Temporary files should be removed. Got %d files", len(files))
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no repos", []string{"-format", "csv"}},
+		{"unknown format", []string{"-format", "xml", "golang/go"}},
+		{"unknown sort", []string{"-sort", "age", "golang/go"}},
+		{"negative top", []string{"-top", "-1", "golang/go"}},
+		{"no workers", []string{"-workers", "0", "golang/go"}},
+	}
+
+	for _, test := range tests {
+		fn := func(t *testing.T) {
+			if _, err := parseFlags(test.args); err == nil {
+				t.Errorf("parseFlags(%q) should error but did not", test.args)
+			}
+		}
+		t.Run(test.name, fn)
+	}
+}
+
+func TestParseFlagsRepos(t *testing.T) {
+	cfg, err := parseFlags([]string{"golang/go", "golang/tools", "Golang/Go", "golang/go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"golang/go", "golang/tools"}
+	if !slices.Equal(cfg.repos, want) {
+		t.Errorf("Repos named more than once should be kept once. Got %q, want %q", cfg.repos, want)
+	}
+}
+
+// slowLister records how many requests are running at the same time.
+type slowLister struct {
+	mu      sync.Mutex
+	running int
+	most    int
+}
+
+// ContributorList satisfies the contributorLister interface.
+func (s *slowLister) ContributorList(ctx context.Context, repo string) ([]github.Contributor, error) {
+	s.mu.Lock()
+	s.running++
+	s.most = max(s.most, s.running)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.running--
+		s.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(10 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if repo == "fail/fail" {
+		return nil, errors.New("could not reach API")
+	}
+	return []github.Contributor{{Login: repo, Contributions: 1}}, nil
+}
+
+func TestCollectWorkers(t *testing.T) {
+	repos := []string{"a/1", "a/2", "a/3", "a/4", "a/5", "a/6", "a/7", "a/8"}
+
+	var s slowLister
+	cons, err := collect(context.Background(), &s, repos, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cons) != len(repos) {
+		t.Errorf("Should get a contributor per repo. Got %d", len(cons))
+	}
+	for i, c := range cons {
+		if c.Login != repos[i] {
+			t.Errorf("Contributors should be merged in repo order. Got %s at %d", c.Login, i)
+		}
+	}
+	if s.most > 3 || s.most < 2 {
+		t.Errorf("Should run up to 3 requests at a time. Got %d", s.most)
+	}
+
+	if _, err := collect(context.Background(), &s, append(repos, "fail/fail"), 3); err == nil {
+		t.Error("collect should error when a repo fails but did not")
+	}
+}
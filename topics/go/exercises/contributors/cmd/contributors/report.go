@@ -0,0 +1,149 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ardanlabs/gotraining/topics/go/exercises/contributors/part3/github"
+)
+
+// contributorLister is the interface that this package looks for when
+// collecting contributors.
+type contributorLister interface {
+	ContributorList(context.Context, string) ([]github.Contributor, error)
+}
+
+// Contributor is one person's contributions across every repo in a report.
+type Contributor struct {
+	Login         string   `json:"login"`
+	Contributions int      `json:"contributions"`
+	Repos         []string `json:"repos"`
+}
+
+// Orders contributors can be sorted in. Ties are broken by login.
+const (
+	SortContributions = "contributions" // Most contributions first.
+	SortLogin         = "login"         // Alphabetical by login.
+	SortRepos         = "repos"         // Contributed to the most repos first.
+)
+
+// result is the contributors to one repo.
+type result struct {
+	repo string
+	cons []github.Contributor
+	err  error
+}
+
+// collect asks for the contributors to each repo using no more than workers
+// requests at a time and merges them. The first failure cancels the
+// requests still running.
+func collect(ctx context.Context, c contributorLister, repos []string, workers int) ([]Contributor, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan string)
+	results := make(chan result, len(repos))
+
+	var wg sync.WaitGroup
+	for range min(max(workers, 1), len(repos)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range jobs {
+				cons, err := c.ContributorList(ctx, repo)
+				results <- result{repo: repo, cons: cons, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, repo := range repos {
+			select {
+			case jobs <- repo:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Responses arrive in any order so keep them by repo and merge them in
+	// the order the repos were given.
+	byRepo := make(map[string][]github.Contributor, len(repos))
+	var err error
+	for r := range results {
+		if r.err != nil && err == nil {
+			err = fmt.Errorf("getting contributors to %s: %w", r.repo, r.err)
+			cancel()
+		}
+		byRepo[r.repo] = r.cons
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return merge(repos, byRepo), nil
+}
+
+// merge combines the contributors to each repo by login.
+func merge(repos []string, byRepo map[string][]github.Contributor) []Contributor {
+	var cons []Contributor
+	index := make(map[string]int)
+
+	for _, repo := range repos {
+		for _, gc := range byRepo[repo] {
+			key := strings.ToLower(gc.Login)
+			i, exists := index[key]
+			if !exists {
+				i = len(cons)
+				index[key] = i
+				cons = append(cons, Contributor{Login: gc.Login})
+			}
+			cons[i].Contributions += gc.Contributions
+			if !slices.Contains(cons[i].Repos, repo) {
+				cons[i].Repos = append(cons[i].Repos, repo)
+			}
+		}
+	}
+
+	return cons
+}
+
+// sortContributors orders the contributors by one of the Sort constants.
+func sortContributors(cons []Contributor, by string) error {
+	var less func(a, b Contributor) int
+
+	switch by {
+	case SortContributions:
+		less = func(a, b Contributor) int { return cmp.Compare(b.Contributions, a.Contributions) }
+	case SortLogin:
+		less = func(a, b Contributor) int { return 0 }
+	case SortRepos:
+		less = func(a, b Contributor) int {
+			if n := cmp.Compare(len(b.Repos), len(a.Repos)); n != 0 {
+				return n
+			}
+			return cmp.Compare(b.Contributions, a.Contributions)
+		}
+	default:
+		return fmt.Errorf("unknown sort order %q", by)
+	}
+
+	slices.SortFunc(cons, func(a, b Contributor) int {
+		if n := less(a, b); n != 0 {
+			return n
+		}
+		return cmp.Compare(strings.ToLower(a.Login), strings.ToLower(b.Login))
+	})
+
+	return nil
+}
@@ -0,0 +1,57 @@
+// This program runs the mock GitHub API so the exercise can be done without
+// reaching github.com.
+//
+// ./githubmock -fixtures repos.json -rate-limit 60
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/exercises/contributors/githubmock"
+)
+
+//runexamples:skip listens for connections
+func main() {
+	addr := flag.String("addr", "127.0.0.1:0", "address to listen on, port 0 picks a free port")
+	fixtures := flag.String("fixtures", "", "JSON file of users and repos to add to the defaults")
+	limit := flag.Int("rate-limit", 0, "requests allowed per hour, 0 is unlimited")
+	flag.Parse()
+
+	mock := githubmock.New()
+	mock.SetRateLimit(*limit, time.Hour)
+
+	if *fixtures != "" {
+		f, err := os.Open(*fixtures)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = mock.Load(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Start listening for local traffic. With port 0 the OS picks a random
+	// open port. We start the listener separately from the server so we
+	// can report the listener's address.
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		log.Println("serving", r.Method, r.URL.Path)
+		mock.ServeHTTP(w, r)
+	}
+
+	fmt.Println("Mock GitHub server running at this url")
+	fmt.Println("http://" + listener.Addr().String() + "/repos/golang/go/contributors")
+	log.Fatal(http.Serve(listener, http.HandlerFunc(handler)))
+}
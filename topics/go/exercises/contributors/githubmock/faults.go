@@ -0,0 +1,85 @@
+package githubmock
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Fault describes how to misbehave when responding to a request.
+type Fault struct {
+	Latency   time.Duration // Wait before responding.
+	Status    int           // Respond with this status instead, ex. 502.
+	Malformed bool          // Cut the JSON response short.
+	Times     int           // Requests the fault applies to, zero is every request.
+}
+
+// Inject adds a fault for the endpoint, which is one of the Endpoint
+// constants. Faults for an endpoint are used in the order they're added, so
+// a sequence of responses can be scripted:
+//
+//	m.Inject(githubmock.EndpointContributors, githubmock.Fault{Status: 502, Times: 2})
+//	m.Inject(githubmock.EndpointContributors, githubmock.Fault{Malformed: true, Times: 1})
+func (m *Mock) Inject(endpoint string, f Fault) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.faults[endpoint] = append(m.faults[endpoint], &f)
+}
+
+// ClearFaults removes every fault.
+func (m *Mock) ClearFaults() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.faults)
+}
+
+// fault returns the fault to apply to the next request for the endpoint, or
+// nil if there isn't one.
+func (m *Mock) fault(endpoint string) *Fault {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	faults := m.faults[endpoint]
+	if len(faults) == 0 {
+		return nil
+	}
+
+	f := *faults[0]
+	if faults[0].Times > 0 {
+		if faults[0].Times--; faults[0].Times == 0 {
+			m.faults[endpoint] = faults[1:]
+		}
+	}
+
+	return &f
+}
+
+// malformedKey marks a request whose response should be cut short.
+type malformedKey struct{}
+
+// applyFault misbehaves as the fault says. It reports false if the response
+// has been written.
+func applyFault(w http.ResponseWriter, r *http.Request, f *Fault) (*http.Request, bool) {
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			timer.Stop()
+			return r, false
+		}
+	}
+
+	if f.Status != 0 {
+		apiError(w, f.Status, http.StatusText(f.Status))
+		return r, false
+	}
+
+	if f.Malformed {
+		r = r.WithContext(context.WithValue(r.Context(), malformedKey{}, true))
+	}
+
+	return r, true
+}
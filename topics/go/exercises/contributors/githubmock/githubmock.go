@@ -0,0 +1,404 @@
+// Package githubmock provides a fake of the GitHub API for running the
+// exercise and its tests without reaching github.com. It keeps an in-memory
+// store of repos, users and contributors seeded from JSON fixtures. Like the
+// real API it paginates with Link headers, enforces a rate limit and can
+// answer 202 while the statistics are being computed, and faults can be
+// injected per endpoint.
+package githubmock
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Set of endpoints the mock serves, which are used to inject faults.
+const (
+	EndpointRepo         = "GET /repos/{owner}/{repo}"
+	EndpointContributors = "GET /repos/{owner}/{repo}/contributors"
+	EndpointUser         = "GET /users/{login}"
+	EndpointRateLimit    = "GET /rate_limit"
+)
+
+// Page sizes used when the client doesn't ask for one, and the most the
+// client can ask for.
+const (
+	defaultPerPage = 30
+	maxPerPage     = 100
+)
+
+// seed holds the users and repos the mock starts with.
+//
+//go:embed fixtures/seed.json
+var seed []byte
+
+var repoRE = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+var authRE = regexp.MustCompile("^(token|Bearer) .+$")
+
+// Mock serves the GitHub API from its store.
+type Mock struct {
+	mux *http.ServeMux
+
+	mu        sync.Mutex
+	users     map[string]User  // By lower case login.
+	repos     map[string]*repo // By lower case full name.
+	nextID    int64
+	now       func() time.Time
+	maxPage   int           // Largest page the client can ask for.
+	limit     int           // Requests allowed per window, zero is unlimited.
+	window    time.Duration // How long until the limit resets.
+	remaining int
+	reset     time.Time
+	computing map[string]int      // Requests to answer with a 202, by repo.
+	faults    map[string][]*Fault // By endpoint.
+	requests  int
+}
+
+// New constructs a mock seeded with the contributors of ardanlabs/gotraining
+// and golang/go.
+func New() *Mock {
+	m := Mock{
+		mux:       http.NewServeMux(),
+		users:     make(map[string]User),
+		repos:     make(map[string]*repo),
+		now:       time.Now,
+		maxPage:   maxPerPage,
+		computing: make(map[string]int),
+		faults:    make(map[string][]*Fault),
+	}
+
+	m.mux.HandleFunc(EndpointRepo, m.handleRepo)
+	m.mux.HandleFunc(EndpointContributors, m.handleContributors)
+	m.mux.HandleFunc(EndpointUser, m.handleUser)
+	m.mux.HandleFunc(EndpointRateLimit, m.handleRateLimit)
+	m.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		apiError(w, http.StatusNotFound, "Not Found")
+	})
+
+	if err := m.Load(bytes.NewReader(seed)); err != nil {
+		panic("githubmock: " + err.Error())
+	}
+
+	return &m
+}
+
+// SetRateLimit allows limit requests per window. A limit of zero removes
+// the rate limit.
+func (m *Mock) SetRateLimit(limit int, window time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limit = limit
+	m.window = window
+	m.remaining = limit
+	m.reset = time.Time{}
+}
+
+// SetMaxPerPage caps the number of items on a page, which makes it easy to
+// test following the pages.
+func (m *Mock) SetMaxPerPage(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.maxPage = n
+}
+
+// Computing makes the next n requests for the repo's contributors respond
+// with 202 Accepted, which GitHub does while it computes the statistics.
+func (m *Mock) Computing(repo string, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.computing[strings.ToLower(repo)] = n
+}
+
+// Requests returns the number of requests received.
+func (m *Mock) Requests() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.requests
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (m *Mock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.requests++
+	m.mu.Unlock()
+
+	// They must provide an auth token.
+	if !authRE.MatchString(r.Header.Get("Authorization")) {
+		apiError(w, http.StatusUnauthorized, `Authorization header must be in the form "Bearer {githubToken}"`)
+		return
+	}
+
+	_, endpoint := m.mux.Handler(r)
+
+	if f := m.fault(endpoint); f != nil {
+		var ok bool
+		if r, ok = applyFault(w, r, f); !ok {
+			return
+		}
+	}
+
+	// Like GitHub, checking the rate limit doesn't count against it.
+	if endpoint != EndpointRateLimit && !m.allow(w) {
+		apiError(w, http.StatusForbidden, "API rate limit exceeded")
+		return
+	}
+
+	m.mux.ServeHTTP(w, r)
+}
+
+// handleRepo handles GET /repos/{owner}/{repo}.
+func (m *Mock) handleRepo(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	rp, exists := m.repos[strings.ToLower(r.PathValue("owner")+"/"+r.PathValue("repo"))]
+	m.mu.Unlock()
+
+	if !exists {
+		apiError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	owner, name, _ := strings.Cut(rp.fullName, "/")
+	base := baseURL(r)
+	writeJSON(w, r, map[string]any{
+		"id":               rp.id,
+		"name":             name,
+		"full_name":        rp.fullName,
+		"owner":            map[string]string{"login": owner},
+		"description":      rp.description,
+		"html_url":         "https://github.com/" + rp.fullName,
+		"url":              base + "/repos/" + rp.fullName,
+		"contributors_url": base + "/repos/" + rp.fullName + "/contributors",
+	})
+}
+
+// contributor is a contributor in a response.
+type contributor struct {
+	userJSON
+	Contributions int `json:"contributions"`
+}
+
+// handleContributors handles GET /repos/{owner}/{repo}/contributors.
+func (m *Mock) handleContributors(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(r.PathValue("owner") + "/" + r.PathValue("repo"))
+
+	m.mu.Lock()
+	rp, exists := m.repos[name]
+	var cons []contributor
+	computing := false
+	if exists {
+		if m.computing[name] > 0 {
+			m.computing[name]--
+			computing = true
+		}
+		for _, c := range rp.contributors() {
+			cons = append(cons, contributor{userJSON: newUserJSON(r, m.users[strings.ToLower(c.Login)]), Contributions: c.Contributions})
+		}
+	}
+	m.mu.Unlock()
+
+	switch {
+	case !exists:
+		apiError(w, http.StatusNotFound, "Not Found")
+		return
+	case computing:
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	from, to, ok := m.paginate(w, r, len(cons))
+	if !ok {
+		return
+	}
+
+	writeJSON(w, r, cons[from:to])
+}
+
+// handleUser handles GET /users/{login}.
+func (m *Mock) handleUser(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	u, exists := m.users[strings.ToLower(r.PathValue("login"))]
+	m.mu.Unlock()
+
+	if !exists {
+		apiError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(w, r, newUserJSON(r, u))
+}
+
+// handleRateLimit handles GET /rate_limit.
+func (m *Mock) handleRateLimit(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	limit, remaining, reset := m.limit, m.remaining, m.reset
+	m.mu.Unlock()
+
+	if limit == 0 {
+		limit, remaining = 5000, 5000
+	}
+
+	core := map[string]int64{
+		"limit":     int64(limit),
+		"remaining": int64(remaining),
+		"used":      int64(limit - remaining),
+		"reset":     reset.Unix(),
+	}
+	writeJSON(w, r, map[string]any{"resources": map[string]any{"core": core}, "rate": core})
+}
+
+// userJSON is a user in a response.
+type userJSON struct {
+	Login     string `json:"login"`
+	ID        int64  `json:"id"`
+	AvatarURL string `json:"avatar_url"`
+	URL       string `json:"url"`
+	HTMLURL   string `json:"html_url"`
+	Type      string `json:"type"`
+	SiteAdmin bool   `json:"site_admin"`
+}
+
+// newUserJSON fills in the URLs for the user.
+func newUserJSON(r *http.Request, u User) userJSON {
+	return userJSON{
+		Login:     u.Login,
+		ID:        u.ID,
+		AvatarURL: u.AvatarURL,
+		URL:       baseURL(r) + "/users/" + u.Login,
+		HTMLURL:   "https://github.com/" + u.Login,
+		Type:      u.Type,
+		SiteAdmin: u.SiteAdmin,
+	}
+}
+
+// paginate works out the page of n items the client asked for and writes
+// the Link header pointing at the other pages. It reports false if the
+// request was invalid and an error has been written.
+func (m *Mock) paginate(w http.ResponseWriter, r *http.Request, n int) (int, int, bool) {
+	page, err := intParam(r, "page", 1)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err.Error())
+		return 0, 0, false
+	}
+	perPage, err := intParam(r, "per_page", defaultPerPage)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err.Error())
+		return 0, 0, false
+	}
+
+	m.mu.Lock()
+	perPage = min(perPage, m.maxPage)
+	m.mu.Unlock()
+
+	// Point the client at the other pages.
+	last := max((n+perPage-1)/perPage, 1)
+	link := func(p int, rel string) string {
+		return fmt.Sprintf(`<%s%s?page=%d&per_page=%d>; rel="%s"`, baseURL(r), r.URL.Path, p, perPage, rel)
+	}
+	var links []string
+	if page > 1 {
+		links = append(links, link(page-1, "prev"), link(1, "first"))
+	}
+	if page < last {
+		links = append(links, link(page+1, "next"), link(last, "last"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	from := min((page-1)*perPage, n)
+	to := min(from+perPage, n)
+	return from, to, true
+}
+
+// allow counts the request against the rate limit and writes the rate limit
+// headers. It reports false if the limit has been used up.
+func (m *Mock) allow(w http.ResponseWriter) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.limit == 0 {
+		return true
+	}
+
+	// Start a new window once the old one has reset. The reset is reported
+	// in whole seconds, so it's rounded up to make sure a client waiting
+	// for it doesn't come back early.
+	now := m.now()
+	if !now.Before(m.reset) {
+		m.remaining = m.limit
+		m.reset = now.Add(m.window).Truncate(time.Second).Add(time.Second)
+	}
+
+	allowed := m.remaining > 0
+	if allowed {
+		m.remaining--
+	}
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(m.limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(m.remaining))
+	w.Header().Set("X-RateLimit-Used", strconv.Itoa(m.limit-m.remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(m.reset.Unix(), 10))
+
+	return allowed
+}
+
+// writeJSON writes the value as the response, cutting it short when a fault
+// asks for malformed JSON.
+func writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if malformed, _ := r.Context().Value(malformedKey{}).(bool); malformed {
+		data = data[:len(data)/2]
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(data)
+}
+
+// apiError writes an error document like GitHub's.
+func apiError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":           msg,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
+
+// baseURL returns the URL the request was made to without the path.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// intParam reads a positive integer query parameter.
+func intParam(r *http.Request, name string, def int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("%s must be a positive number", name)
+	}
+	return v, nil
+}
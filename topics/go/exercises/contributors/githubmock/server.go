@@ -0,0 +1,28 @@
+package githubmock
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// Server is a mock running on a local port. The mock's methods can be used
+// to change its state and the test server's fields to reach it.
+type Server struct {
+	*Mock
+	*httptest.Server
+}
+
+// NewServer starts a mock seeded with the default fixtures, which is shut
+// down when the test finishes.
+//
+//	srv := githubmock.NewServer(t)
+//	c, err := github.NewClient(srv.URL, token)
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	m := New()
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	return &Server{Mock: m, Server: srv}
+}
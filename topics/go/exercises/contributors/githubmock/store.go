@@ -0,0 +1,164 @@
+package githubmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// User is a GitHub account.
+type User struct {
+	Login     string `json:"login"`
+	ID        int64  `json:"id"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	Type      string `json:"type,omitempty"` // Defaults to "User".
+	SiteAdmin bool   `json:"site_admin"`
+}
+
+// Contribution is the number of commits a user made to a repo.
+type Contribution struct {
+	Login         string `json:"login"`
+	Contributions int    `json:"contributions"`
+}
+
+// Repo is a repository and the users who contributed to it.
+type Repo struct {
+	FullName     string         `json:"full_name"` // As in "golang/go".
+	Description  string         `json:"description,omitempty"`
+	Contributors []Contribution `json:"contributors,omitempty"`
+}
+
+// Fixtures is the document the store is seeded from.
+//
+//	{
+//	  "users": [{"login": "gopher", "id": 1}],
+//	  "repos": [{"full_name": "golang/go", "contributors": [{"login": "gopher", "contributions": 10}]}]
+//	}
+type Fixtures struct {
+	Users []User `json:"users"`
+	Repos []Repo `json:"repos"`
+}
+
+// repo is a stored repository.
+type repo struct {
+	id            int64
+	fullName      string
+	description   string
+	contributions map[string]int // By login.
+}
+
+// contributors returns the repo's contributions, most first as GitHub
+// orders them.
+func (r *repo) contributors() []Contribution {
+	cons := make([]Contribution, 0, len(r.contributions))
+	for login, n := range r.contributions {
+		cons = append(cons, Contribution{Login: login, Contributions: n})
+	}
+
+	slices.SortFunc(cons, func(a, b Contribution) int {
+		if a.Contributions != b.Contributions {
+			return b.Contributions - a.Contributions
+		}
+		return strings.Compare(a.Login, b.Login)
+	})
+
+	return cons
+}
+
+// Load adds the users and repos in the JSON fixtures to the store. Users
+// and repos that already exist are replaced.
+func (m *Mock) Load(r io.Reader) error {
+	var fx Fixtures
+	if err := json.NewDecoder(r).Decode(&fx); err != nil {
+		return fmt.Errorf("decoding fixtures: %w", err)
+	}
+
+	for _, u := range fx.Users {
+		m.AddUser(u)
+	}
+	for _, r := range fx.Repos {
+		if err := m.AddRepo(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddUser adds or replaces a user. A user without an ID is given one.
+func (m *Mock) AddUser(u User) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.addUser(u)
+}
+
+// addUser adds or replaces a user. The caller must hold the lock.
+func (m *Mock) addUser(u User) User {
+	if u.ID == 0 {
+		m.nextID++
+		u.ID = m.nextID
+	}
+	m.nextID = max(m.nextID, u.ID)
+
+	if u.Type == "" {
+		u.Type = "User"
+	}
+
+	m.users[strings.ToLower(u.Login)] = u
+	return u
+}
+
+// AddRepo adds or replaces a repo. Contributors who aren't known users are
+// added as users.
+func (m *Mock) AddRepo(r Repo) error {
+	if !repoRE.MatchString(r.FullName) {
+		return fmt.Errorf("invalid repo name %q", r.FullName)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	stored := repo{
+		id:            m.nextID,
+		fullName:      r.FullName,
+		description:   r.Description,
+		contributions: make(map[string]int),
+	}
+
+	for _, c := range r.Contributors {
+		login := m.ensureUser(c.Login).Login
+		stored.contributions[login] += c.Contributions
+	}
+
+	m.repos[strings.ToLower(r.FullName)] = &stored
+	return nil
+}
+
+// AddContributions records n more contributions by the user to the repo.
+func (m *Mock) AddContributions(repo string, login string, n int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, exists := m.repos[strings.ToLower(repo)]
+	if !exists {
+		return fmt.Errorf("unknown repo %q", repo)
+	}
+
+	login = m.ensureUser(login).Login
+	r.contributions[login] += n
+
+	return nil
+}
+
+// ensureUser returns the user with the login, adding them if they don't exist.
+// The caller must hold the lock.
+func (m *Mock) ensureUser(login string) User {
+	if u, exists := m.users[strings.ToLower(login)]; exists {
+		return u
+	}
+	return m.addUser(User{Login: login})
+}
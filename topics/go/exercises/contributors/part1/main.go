@@ -20,6 +20,7 @@ type Contributor struct {
 	Contributions int    `json:"contributions"`
 }
 
+//runexamples:skip needs a network connection
 func main() {
 
 	// Get an access token from the environment.
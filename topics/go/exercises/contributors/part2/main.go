@@ -8,6 +8,7 @@ import (
 	"github.com/ardanlabs/gotraining/topics/go/exercises/contributors/part2/github"
 )
 
+//runexamples:skip needs a network connection
 func main() {
 	tkn := os.Getenv("GITHUB_TOKEN")
 	if tkn == "" {
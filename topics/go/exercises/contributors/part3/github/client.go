@@ -1,11 +1,16 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"net/http"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,6 +22,42 @@ type Client struct {
 	token   string
 	client  http.Client
 	baseURL string
+
+	retries   int           // Times a 202 or 5xx response is retried.
+	backoff   time.Duration // Wait before the first retry, doubled each time.
+	waitLimit bool          // Wait for the rate limit to reset.
+}
+
+// Option changes the behavior of a Client.
+type Option func(*Client)
+
+// WithRetries sets how many times a request that gets a 202 or 5xx response
+// is retried, and how long to wait before the first retry. The wait doubles
+// with each retry. The default is 5 retries starting at half a second.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// WaitForRateLimit makes the client block until the rate limit resets
+// instead of returning a RateLimitError.
+func WaitForRateLimit() Option {
+	return func(c *Client) {
+		c.waitLimit = true
+	}
+}
+
+// RateLimitError is returned when the API's rate limit has been used up.
+type RateLimitError struct {
+	Limit int       // Requests allowed in the window.
+	Reset time.Time // When requests are allowed again.
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit of %d requests exceeded, resets at %s", e.Limit, e.Reset.Format(time.RFC3339))
 }
 
 // tokenRE defines the form of a valid token. We compile it once at package
@@ -28,8 +69,9 @@ var tokenRE = regexp.MustCompile(`^[0-9a-f]{40}$`)
 // const API for github.com or pass your own url for tests or an enterprise
 // installation. NewClient will error if the token field is invalid.
 // Call it like:
+//
 //	github.NewClient(github.API, os.Getenv("GITHUB_TOKEN"))
-func NewClient(root, token string) (*Client, error) {
+func NewClient(root, token string, opts ...Option) (*Client, error) {
 
 	if token == "" {
 		return nil, errors.New("token is required")
@@ -38,53 +80,207 @@ func NewClient(root, token string) (*Client, error) {
 		return nil, errors.New("token is invalid")
 	}
 
-	return &Client{
+	c := Client{
 		token:   token,
 		client:  http.Client{Timeout: 5 * time.Second},
 		baseURL: root,
-	}, nil
+		retries: 5,
+		backoff: 500 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c, nil
 }
 
 // repoRE is the regexp value for checking repo strings. We compile this once
 // with "MustCompile" when the package loads because it will never change and
 // we know it will always work.
-var repoRE = regexp.MustCompile(`[a-zA-Z0-9]+/[a-zA-Z0-9]+`)
-
-// ContributorList gives a list of the top 30 contributors. It returns an error
-// for network problems reaching the API or for application problems such as a
-// 404 or 403 response from GitHub.
-func (c *Client) ContributorList(repo string) ([]Contributor, error) {
-	if repo == "" {
-		return nil, errors.New("repo is required")
+var repoRE = regexp.MustCompile(`^[a-zA-Z0-9_.-]+/[a-zA-Z0-9_.-]+$`)
+
+// Contributors iterates over every contributor to the repo, following the
+// pages of results. Iteration stops after the first error.
+//
+//	for con, err := range c.Contributors(ctx, "golang/go") {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(con.Login)
+//	}
+func (c *Client) Contributors(ctx context.Context, repo string) iter.Seq2[Contributor, error] {
+	return func(yield func(Contributor, error) bool) {
+		if repo == "" {
+			yield(Contributor{}, errors.New("repo is required"))
+			return
+		}
+		if !repoRE.MatchString(repo) {
+			yield(Contributor{}, errors.New("repo is invalid"))
+			return
+		}
+
+		next := fmt.Sprintf("%s/repos/%s/contributors?per_page=100", c.baseURL, repo)
+		for next != "" {
+			var cons []Contributor
+			link, err := c.get(ctx, next, &cons)
+			if err != nil {
+				yield(Contributor{}, err)
+				return
+			}
+
+			for _, con := range cons {
+				if !yield(con, nil) {
+					return
+				}
+			}
+
+			next = nextPage(link)
+		}
 	}
-	if !repoRE.MatchString(repo) {
-		return nil, errors.New("repo is invalid")
+}
+
+// ContributorList gives a list of every contributor to the repo. It returns an
+// error for network problems reaching the API or for application problems such
+// as a 404 or 403 response from GitHub.
+func (c *Client) ContributorList(ctx context.Context, repo string) ([]Contributor, error) {
+	var cons []Contributor
+	for con, err := range c.Contributors(ctx, repo) {
+		if err != nil {
+			return nil, err
+		}
+		cons = append(cons, con)
 	}
 
-	// Make a request and set the auth token in the header.
-	u := fmt.Sprintf("%s/repos/%s/contributors", c.baseURL, repo)
-	req, err := http.NewRequest(http.MethodGet, u, nil)
-	if err != nil {
-		return nil, err
+	return cons, nil
+}
+
+// get requests the url and decodes the response into v, returning the Link
+// header. Responses that say to come back later are retried.
+func (c *Client) get(ctx context.Context, u string, v any) (string, error) {
+	for attempt := 0; ; attempt++ {
+
+		// Make a request and set the auth token in the header.
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+
+		// Execute the request.
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+
+		var wait time.Duration
+		switch {
+		case resp.StatusCode == http.StatusOK:
+
+			// Decode the result.
+			defer resp.Body.Close()
+			if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+				return "", err
+			}
+			return strings.Join(resp.Header.Values("Link"), ","), nil
+
+		case resp.StatusCode == http.StatusAccepted || resp.StatusCode >= 500:
+
+			// The statistics are being computed or the API is having
+			// problems, so try again later.
+			if attempt >= c.retries {
+				drain(resp)
+				return "", fmt.Errorf("API responded with a %d %s after %d attempts", resp.StatusCode, resp.Status, attempt+1)
+			}
+			wait = c.backoff << attempt
+
+		default:
+			rle := rateLimited(resp)
+			if rle == nil {
+				drain(resp)
+				return "", fmt.Errorf("API responded with a %d %s", resp.StatusCode, resp.Status)
+			}
+			if !c.waitLimit {
+				drain(resp)
+				return "", rle
+			}
+
+			// Waiting for the rate limit to reset doesn't count as an
+			// attempt. A reset that has already passed does, with the
+			// usual backoff, so a server that keeps saying the limit is
+			// used up can't keep the client spinning.
+			wait = time.Until(rle.Reset)
+			switch {
+			case wait > 0:
+				attempt--
+			case attempt >= c.retries:
+				drain(resp)
+				return "", rle
+			default:
+				wait = c.backoff << attempt
+			}
+		}
+		drain(resp)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		}
 	}
-	req.Header.Set("Authorization", "Bearer "+c.token)
+}
 
-	// Execute the request.
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+// rateLimited returns the rate limit error for a 403 or 429 response that
+// was caused by the rate limit, or nil if it wasn't.
+func rateLimited(resp *http.Response) *RateLimitError {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API responded with a %d %s", resp.StatusCode, resp.Status)
+	// Secondary rate limits say how many seconds to wait.
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			return &RateLimitError{Reset: time.Now().Add(time.Duration(secs) * time.Second)}
+		}
 	}
 
-	// Decode the result.
-	var cons []Contributor
-	if err := json.NewDecoder(resp.Body).Decode(&cons); err != nil {
-		return nil, err
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
 	}
 
-	return cons, nil
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return &RateLimitError{Limit: limit, Reset: time.Now().Add(time.Minute)}
+	}
+
+	return &RateLimitError{Limit: limit, Reset: time.Unix(reset, 0)}
+}
+
+// nextPage returns the url of the next page from a Link header, or an empty
+// string on the last page.
+//
+//	Link: <https://api.github.com/...?page=2>; rel="next", <...?page=5>; rel="last"
+func nextPage(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		u, params, found := strings.Cut(strings.TrimSpace(part), ";")
+		if !found {
+			continue
+		}
+		for _, p := range strings.Split(params, ";") {
+			if strings.TrimSpace(p) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(u), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// drain reads the rest of the body so the connection can be reused, and
+// closes it.
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	resp.Body.Close()
 }
@@ -1,10 +1,14 @@
 package github
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/ardanlabs/gotraining/topics/go/exercises/contributors/githubmock"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -79,7 +83,7 @@ func TestContributorsSuccess(t *testing.T) {
 	}
 
 	// Call the method under test.
-	got, err := c.ContributorList("golang/go")
+	got, err := c.ContributorList(context.Background(), "golang/go")
 
 	if err != nil {
 		t.Fatalf("Client should not error. Got %v", err)
@@ -115,7 +119,222 @@ func TestContributorsAPIFailure(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if _, err := c.ContributorList("golang/go"); err == nil {
+	if _, err := c.ContributorList(context.Background(), "golang/go"); err == nil {
 		t.Fatal("Client should error but did not")
 	}
 }
+
+// TestRepoNames asserts on which repo names are accepted. Real names can
+// contain dashes, underscores and dots.
+func TestRepoNames(t *testing.T) {
+	tests := []struct {
+		repo  string
+		valid bool
+	}{
+		{"golang/go", true},
+		{"ardanlabs/go-training", true},
+		{"some_org/repo.name", true},
+		{"golang", false},
+		{"golang/go/issues", false},
+		{"golang/go?page=2", false},
+	}
+
+	for _, test := range tests {
+		if got := repoRE.MatchString(test.repo); got != test.valid {
+			t.Errorf("repoRE.MatchString(%q) = %v, want %v", test.repo, got, test.valid)
+		}
+	}
+}
+
+func TestContributorsPagination(t *testing.T) {
+	srv := githubmock.NewServer(t)
+
+	c, err := NewClient(srv.URL, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Get every contributor in one page to compare against.
+	want, err := c.ContributorList(context.Background(), "golang/go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want) != 30 {
+		t.Fatalf("Should get 30 contributors in one page. Got %d", len(want))
+	}
+
+	t.Run("every page", func(t *testing.T) {
+		srv.SetMaxPerPage(7)
+		start := srv.Requests()
+
+		got, err := c.ContributorList(context.Background(), "golang/go")
+		if err != nil {
+			t.Fatalf("Client should not error. Got %v", err)
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("contributors from every page did not match:\n%s", diff)
+		}
+		if got := srv.Requests() - start; got != 5 {
+			t.Errorf("Should request 5 pages. Got %d", got)
+		}
+	})
+
+	t.Run("stop early", func(t *testing.T) {
+		srv.SetMaxPerPage(7)
+		start := srv.Requests()
+
+		var n int
+		for _, err := range c.Contributors(context.Background(), "golang/go") {
+			if err != nil {
+				t.Fatalf("Client should not error. Got %v", err)
+			}
+			if n++; n == 3 {
+				break
+			}
+		}
+		if got := srv.Requests() - start; got != 1 {
+			t.Errorf("Should only request the first page. Got %d", got)
+		}
+	})
+}
+
+func TestContributorsRetry(t *testing.T) {
+	srv := githubmock.NewServer(t)
+
+	tests := []struct {
+		name      string
+		computing int
+		failures  int
+		shouldErr bool
+	}{
+		{"statistics computed in time", 2, 0, false},
+		{"statistics never computed", 10, 0, true},
+		{"server errors", 0, 2, false},
+		{"server down", 0, 10, true},
+	}
+
+	for _, test := range tests {
+		fn := func(t *testing.T) {
+			srv.ClearFaults()
+			srv.Computing("golang/go", test.computing)
+			if test.failures > 0 {
+				srv.Inject(githubmock.EndpointContributors, githubmock.Fault{Status: http.StatusBadGateway, Times: test.failures})
+			}
+
+			c, err := NewClient(srv.URL, token, WithRetries(3, time.Millisecond))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = c.ContributorList(context.Background(), "golang/go")
+			if test.shouldErr && err == nil {
+				t.Error("Client should error but did not")
+			} else if !test.shouldErr && err != nil {
+				t.Errorf("Client should not error but gave %v", err)
+			}
+		}
+		t.Run(test.name, fn)
+	}
+}
+
+func TestContributorsRateLimit(t *testing.T) {
+
+	t.Run("return error", func(t *testing.T) {
+		srv := githubmock.NewServer(t)
+		srv.SetRateLimit(1, time.Hour)
+
+		c, err := NewClient(srv.URL, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.ContributorList(context.Background(), "golang/go"); err != nil {
+			t.Fatalf("First request should not error. Got %v", err)
+		}
+
+		_, err = c.ContributorList(context.Background(), "golang/go")
+		var rle *RateLimitError
+		if !errors.As(err, &rle) {
+			t.Fatalf("Should get a RateLimitError. Got %v", err)
+		}
+		if rle.Limit != 1 || !rle.Reset.After(time.Now().Add(59*time.Minute)) {
+			t.Errorf("RateLimitError should carry the limit and reset time. Got %+v", rle)
+		}
+	})
+
+	t.Run("wait for reset", func(t *testing.T) {
+		srv := githubmock.NewServer(t)
+		srv.SetRateLimit(1, 0)
+
+		c, err := NewClient(srv.URL, token, WaitForRateLimit())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for range 2 {
+			if _, err := c.ContributorList(context.Background(), "golang/go"); err != nil {
+				t.Fatalf("Client should wait for the reset. Got %v", err)
+			}
+		}
+		if got := srv.Requests(); got < 3 {
+			t.Errorf("Should retry after the reset. Got %d requests", got)
+		}
+	})
+
+	t.Run("reset already passed", func(t *testing.T) {
+		var requests int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1")
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL, token, WaitForRateLimit(), WithRetries(3, time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = c.ContributorList(context.Background(), "golang/go")
+		var rle *RateLimitError
+		if !errors.As(err, &rle) {
+			t.Fatalf("Should give up with a RateLimitError. Got %v", err)
+		}
+		if requests != 4 {
+			t.Errorf("Should count the retries. Got %d requests", requests)
+		}
+	})
+
+	t.Run("give up waiting", func(t *testing.T) {
+		srv := githubmock.NewServer(t)
+		srv.SetRateLimit(1, time.Hour)
+
+		c, err := NewClient(srv.URL, token, WaitForRateLimit())
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.ContributorList(context.Background(), "golang/go")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if _, err := c.ContributorList(ctx, "golang/go"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Client should stop waiting when the context is done. Got %v", err)
+		}
+	})
+}
+
+func TestContributorsMalformed(t *testing.T) {
+	srv := githubmock.NewServer(t)
+	srv.Inject(githubmock.EndpointContributors, githubmock.Fault{Malformed: true, Times: 1})
+
+	c, err := NewClient(srv.URL, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.ContributorList(context.Background(), "golang/go"); err == nil {
+		t.Error("Client should error on malformed JSON but did not")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"github.com/ardanlabs/gotraining/topics/go/exercises/contributors/part3/github"
 )
 
+//runexamples:skip needs a network connection
 func main() {
 	tkn := os.Getenv("GITHUB_TOKEN")
 	if tkn == "" {
@@ -18,12 +20,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	c, err := github.NewClient(github.API, tkn)
+	c, err := github.NewClient(github.API, tkn, github.WaitForRateLimit())
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if err := process(os.Stdout, "ardanlabs/gotraining", c); err != nil {
+	if err := process(context.Background(), os.Stdout, "ardanlabs/gotraining", c); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -31,11 +33,11 @@ func main() {
 // contributorLister is the interface that this package looks for when
 // calling process.
 type contributorLister interface {
-	ContributorList(string) ([]github.Contributor, error)
+	ContributorList(context.Context, string) ([]github.Contributor, error)
 }
 
-func process(w io.Writer, repo string, c contributorLister) error {
-	cons, err := c.ContributorList(repo)
+func process(ctx context.Context, w io.Writer, repo string, c contributorLister) error {
+	cons, err := c.ContributorList(ctx, repo)
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -15,7 +16,7 @@ type mock struct{}
 
 // ContributorList satisfies the main package's "contributorLister" interface.
 // It returns predefined result sets for different repo values.
-func (mock) ContributorList(repo string) ([]github.Contributor, error) {
+func (mock) ContributorList(ctx context.Context, repo string) ([]github.Contributor, error) {
 	switch repo {
 	case "golang/go":
 		return []github.Contributor{
@@ -61,7 +62,7 @@ func TestProcess(t *testing.T) {
 			var buf bytes.Buffer
 
 			// Call the function under test.
-			err := process(&buf, test.repo, c)
+			err := process(context.Background(), &buf, test.repo, c)
 
 			// Assert on our results.
 			got := strings.TrimSpace(buf.String())
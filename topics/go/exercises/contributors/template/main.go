@@ -15,6 +15,7 @@ import (
 // Create a type where we can decode contributor json values.
 // It needs the fields "login" and "contributions".
 
+//runexamples:skip needs a network connection
 func main() {
 
 	// Get an access token from the environment.
@@ -0,0 +1,219 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// Package cas implements a content-addressed file store. Blobs are stored
+// once under the sha256 sum of their contents, so putting the same content
+// twice costs nothing, and are checked against the sum when read.
+//
+// A store is a directory laid out like:
+//
+//	sha256/ab/cdef...  blobs named by their sum, split after two characters
+//	refs/release       named references to blobs, the roots kept by GC
+//	tmp/               blobs being written or removed
+package cas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Set of errors returned by the store.
+var (
+	ErrInvalidDigest = errors.New("invalid digest")
+	ErrNotFound      = errors.New("blob not found")
+	ErrCorrupt       = errors.New("blob is corrupt")
+)
+
+// Digest identifies a blob by the sha256 sum of its contents, written like
+// "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824".
+type Digest string
+
+// algorithm prefixes every digest and names the directory of blobs.
+const algorithm = "sha256"
+
+// ParseDigest validates a digest.
+func ParseDigest(s string) (Digest, error) {
+	hexSum, found := strings.CutPrefix(s, algorithm+":")
+	if !found || len(hexSum) != 2*sha256.Size || strings.ToLower(hexSum) != hexSum {
+		return "", fmt.Errorf("%w: %q", ErrInvalidDigest, s)
+	}
+	if _, err := hex.DecodeString(hexSum); err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidDigest, s)
+	}
+	return Digest(s), nil
+}
+
+// Hex returns the sum without the algorithm.
+func (d Digest) Hex() string {
+	return strings.TrimPrefix(string(d), algorithm+":")
+}
+
+// String implements the fmt.Stringer interface.
+func (d Digest) String() string {
+	return string(d)
+}
+
+// digestOf returns the digest for the sum computed by h.
+func digestOf(h hash.Hash) Digest {
+	return Digest(algorithm + ":" + hex.EncodeToString(h.Sum(nil)))
+}
+
+// Store is a content-addressed store in a directory. It's safe to use a
+// Store from many goroutines and to share the directory between processes.
+type Store struct {
+	root string
+}
+
+// Open opens the store in the directory, creating it if needed.
+func Open(root string) (*Store, error) {
+	for _, dir := range []string{algorithm, "refs", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			return nil, fmt.Errorf("creating store: %w", err)
+		}
+	}
+
+	return &Store{root: root}, nil
+}
+
+// Path returns where the blob with the digest is stored.
+func (s *Store) Path(d Digest) string {
+	h := d.Hex()
+	return filepath.Join(s.root, algorithm, h[:2], h[2:])
+}
+
+// Put stores the contents of r and returns its digest. The blob is written
+// to a temporary file and renamed into place once complete, so readers
+// never see part of a blob. Content already in the store is not written
+// again, but counts as just put for GC.
+func (s *Store) Put(r io.Reader) (Digest, error) {
+	f, err := os.CreateTemp(filepath.Join(s.root, "tmp"), "put-")
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+
+	// The temp file is removed unless it was renamed into place.
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), r); err != nil {
+		f.Close()
+		return "", fmt.Errorf("writing blob: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return "", fmt.Errorf("writing blob: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("writing blob: %w", err)
+	}
+
+	d := digestOf(h)
+	path := s.Path(d)
+
+	// Content already stored has its modification time refreshed, so GC's
+	// grace period protects it until it's given a ref the same as a new
+	// blob. If it was removed meanwhile or is corrupt it's written again,
+	// replacing the corrupt blob.
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err == nil {
+		if err := s.Verify(d); err == nil {
+			return d, nil
+		}
+	}
+
+	if err := os.Chmod(tmp, 0444); err != nil {
+		return "", fmt.Errorf("writing blob: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("writing blob: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("writing blob: %w", err)
+	}
+
+	return d, nil
+}
+
+// Has reports if the blob is in the store.
+func (s *Store) Has(d Digest) (bool, error) {
+	if _, err := ParseDigest(string(d)); err != nil {
+		return false, err
+	}
+
+	_, err := os.Stat(s.Path(d))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, fs.ErrNotExist):
+		return false, nil
+	}
+	return false, err
+}
+
+// Get opens the blob for reading. The contents are checked against the
+// digest as they're read and the final Read returns ErrCorrupt instead of
+// io.EOF when they don't match, so a caller reading to the end never uses
+// a corrupt blob without knowing.
+func (s *Store) Get(d Digest) (io.ReadCloser, error) {
+	if _, err := ParseDigest(string(d)); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(s.Path(d))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, d)
+		}
+		return nil, err
+	}
+
+	return &verifier{f: f, h: sha256.New(), want: d}, nil
+}
+
+// Verify reads the blob and checks it against its digest.
+func (s *Store) Verify(d Digest) error {
+	rc, err := s.Get(d)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	_, err = io.Copy(io.Discard, rc)
+	return err
+}
+
+// verifier hashes a blob as it's read.
+type verifier struct {
+	f    *os.File
+	h    hash.Hash
+	want Digest
+}
+
+// Read implements the io.Reader interface.
+func (v *verifier) Read(p []byte) (int, error) {
+	n, err := v.f.Read(p)
+	v.h.Write(p[:n])
+
+	if err == io.EOF {
+		if got := digestOf(v.h); got != v.want {
+			return n, fmt.Errorf("%w: %s has digest %s", ErrCorrupt, v.want, got)
+		}
+	}
+
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (v *verifier) Close() error {
+	return v.f.Close()
+}
@@ -0,0 +1,428 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package cas
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// helloDigest is the digest of "hello".
+const helloDigest = Digest("sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+
+// open returns an empty store for a test.
+func open(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return s
+}
+
+// put stores the string and returns its digest.
+func put(t *testing.T, s *Store, content string) Digest {
+	t.Helper()
+
+	d, err := s.Put(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Put(%q): %v", content, err)
+	}
+	return d
+}
+
+// corrupt overwrites a blob in place.
+func corrupt(t *testing.T, s *Store, d Digest) {
+	t.Helper()
+
+	path := s.Path(d)
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("jello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// age makes a blob look older so GC's grace period doesn't keep it.
+func age(t *testing.T, s *Store, d Digest) {
+	t.Helper()
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(s.Path(d), old, old); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		ok   bool
+	}{
+		{"valid", string(helloDigest), true},
+		{"no algorithm", helloDigest.Hex(), false},
+		{"other algorithm", "md5:5d41402abc4b2a76b9719d911017c592", false},
+		{"short", "sha256:2cf24dba", false},
+		{"upper case", "sha256:" + strings.ToUpper(helloDigest.Hex()), false},
+		{"not hex", "sha256:" + strings.Repeat("zz", 32), false},
+		{"path", "sha256:../../" + helloDigest.Hex()[6:], false},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			_, err := ParseDigest(tt.in)
+			if tt.ok && err != nil {
+				t.Errorf("ParseDigest(%q): %v", tt.in, err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidDigest) {
+				t.Errorf("ParseDigest(%q) = %v, want ErrInvalidDigest", tt.in, err)
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestPutGet(t *testing.T) {
+	s := open(t)
+
+	d := put(t, s, "hello")
+	if d != helloDigest {
+		t.Errorf("Put = %s, want %s", d, helloDigest)
+	}
+
+	want := filepath.Join(s.root, "sha256", "2c", helloDigest.Hex()[2:])
+	if got := s.Path(d); got != want {
+		t.Errorf("Path = %s, want %s", got, want)
+	}
+
+	if d := put(t, s, "hello"); d != helloDigest {
+		t.Errorf("second Put = %s, want %s", d, helloDigest)
+	}
+
+	has, err := s.Has(d)
+	if err != nil || !has {
+		t.Errorf("Has = %v, %v, want true", has, err)
+	}
+
+	rc, err := s.Get(d)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil || string(data) != "hello" {
+		t.Errorf("Get read %q, %v, want %q", data, err, "hello")
+	}
+
+	tmp, err := os.ReadDir(filepath.Join(s.root, "tmp"))
+	if err != nil || len(tmp) != 0 {
+		t.Errorf("tmp has %d files, %v, want 0", len(tmp), err)
+	}
+}
+
+func TestMissing(t *testing.T) {
+	s := open(t)
+
+	has, err := s.Has(helloDigest)
+	if err != nil || has {
+		t.Errorf("Has = %v, %v, want false", has, err)
+	}
+
+	if _, err := s.Get(helloDigest); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get = %v, want ErrNotFound", err)
+	}
+
+	if _, err := s.Get("sha256:nope"); !errors.Is(err, ErrInvalidDigest) {
+		t.Errorf("Get = %v, want ErrInvalidDigest", err)
+	}
+}
+
+func TestCorrupt(t *testing.T) {
+	s := open(t)
+
+	d := put(t, s, "hello")
+	corrupt(t, s, d)
+
+	rc, err := s.Get(d)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer rc.Close()
+
+	if _, err := io.ReadAll(rc); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("reading = %v, want ErrCorrupt", err)
+	}
+
+	if err := s.Verify(d); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Verify = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestPutRepairsCorrupt(t *testing.T) {
+	s := open(t)
+
+	d := put(t, s, "hello")
+	corrupt(t, s, d)
+
+	if got := put(t, s, "hello"); got != d {
+		t.Fatalf("Put again = %s, want %s", got, d)
+	}
+	if err := s.Verify(d); err != nil {
+		t.Errorf("Verify after putting again = %v, want the blob repaired", err)
+	}
+}
+
+func TestRefs(t *testing.T) {
+	s := open(t)
+
+	d := put(t, s, "hello")
+	if err := s.SetRef("v1.0", d); err != nil {
+		t.Fatalf("SetRef: %v", err)
+	}
+
+	if got, err := s.Ref("v1.0"); err != nil || got != d {
+		t.Errorf("Ref = %s, %v, want %s", got, err, d)
+	}
+
+	for _, name := range []string{"", ".hidden", "a/b", "../up"} {
+		if err := s.SetRef(name, d); !errors.Is(err, ErrInvalidRef) {
+			t.Errorf("SetRef(%q) = %v, want ErrInvalidRef", name, err)
+		}
+	}
+
+	if err := s.DeleteRef("v1.0"); err != nil {
+		t.Fatalf("DeleteRef: %v", err)
+	}
+
+	refs, err := s.Refs()
+	if err != nil || len(refs) != 0 {
+		t.Errorf("Refs = %v, %v, want none", refs, err)
+	}
+}
+
+func TestManifest(t *testing.T) {
+	s := open(t)
+
+	ds := []Digest{put(t, s, "a"), put(t, s, "b")}
+
+	m, err := s.PutManifest(ds)
+	if err != nil {
+		t.Fatalf("PutManifest: %v", err)
+	}
+
+	got, ok, err := s.Manifest(m)
+	if err != nil || !ok {
+		t.Fatalf("Manifest = %v, %v", ok, err)
+	}
+	if len(got) != 2 || got[0] != ds[0] || got[1] != ds[1] {
+		t.Errorf("Manifest = %v, want %v", got, ds)
+	}
+
+	if _, ok, err := s.Manifest(ds[0]); err != nil || ok {
+		t.Errorf("Manifest of a plain blob = %v, %v, want false", ok, err)
+	}
+}
+
+func TestGC(t *testing.T) {
+	s := open(t)
+
+	a := put(t, s, "a")
+	b := put(t, s, "b")
+	orphan := put(t, s, "orphan")
+	fresh := put(t, s, "fresh")
+	extra := put(t, s, "extra")
+
+	m, err := s.PutManifest([]Digest{a, b})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetRef("release", m); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range []Digest{a, b, m, orphan, extra} {
+		age(t, s, d)
+	}
+
+	opts := GCOptions{Roots: []Digest{extra}, Grace: time.Hour, DryRun: true}
+
+	stats, err := s.GC(opts)
+	if err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if stats.Marked != 4 || stats.Swept != 1 || stats.Freed != int64(len("orphan")) {
+		t.Errorf("dry run stats = %+v, want 4 marked and 1 swept of 6 bytes", stats)
+	}
+	if has, _ := s.Has(orphan); !has {
+		t.Error("dry run removed a blob")
+	}
+
+	opts.DryRun = false
+	if _, err := s.GC(opts); err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		d    Digest
+		want bool
+	}{
+		{"manifest", m, true},
+		{"listed", a, true},
+		{"root", extra, true},
+		{"in grace period", fresh, true},
+		{"unreachable", orphan, false},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			if has, _ := s.Has(tt.d); has != tt.want {
+				t.Errorf("Has = %v, want %v", has, tt.want)
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+
+	if _, err := os.Stat(filepath.Dir(s.Path(orphan))); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("empty fan out directory was kept: %v", err)
+	}
+}
+
+func TestGCAfterPutAgain(t *testing.T) {
+	s := open(t)
+
+	d := put(t, s, "old")
+	age(t, s, d)
+
+	// Putting content that's already stored must protect it the same as
+	// new content until it's given a ref.
+	if got := put(t, s, "old"); got != d {
+		t.Fatalf("Put again = %s, want %s", got, d)
+	}
+
+	if _, err := s.GC(GCOptions{Grace: time.Hour}); err != nil {
+		t.Fatalf("GC: %v", err)
+	}
+	if err := s.SetRef("again", d); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := s.Get(d)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer rc.Close()
+
+	b, err := io.ReadAll(rc)
+	if err != nil || string(b) != "old" {
+		t.Errorf("ReadAll = %q, %v, want %q", b, err, "old")
+	}
+}
+
+func TestGCPutDuringSweep(t *testing.T) {
+	s := open(t)
+	cutoff := time.Now().Add(-time.Hour)
+
+	again := put(t, s, "again")
+	orphan := put(t, s, "orphan")
+	age(t, s, again)
+	age(t, s, orphan)
+
+	// Putting the content again after the walk found it old refreshes it
+	// before it's removed.
+	put(t, s, "again")
+
+	tests := []struct {
+		name string
+		d    Digest
+		kept bool
+	}{
+		{"put again", again, true},
+		{"unreachable", orphan, false},
+	}
+
+	for _, tt := range tests {
+		fn := func(t *testing.T) {
+			removed, err := s.remove(s.Path(tt.d), tt.d, cutoff)
+			if err != nil {
+				t.Fatalf("remove: %v", err)
+			}
+			if has, _ := s.Has(tt.d); has != tt.kept || removed == tt.kept {
+				t.Errorf("remove = %v and Has = %v, want the blob kept %v", removed, has, tt.kept)
+			}
+		}
+		t.Run(tt.name, fn)
+	}
+}
+
+func TestGCMalformedManifest(t *testing.T) {
+	s := open(t)
+
+	d := put(t, s, manifestHeader+"not a digest\n")
+	age(t, s, d)
+	if err := s.SetRef("bad", d); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := s.GC(GCOptions{Grace: time.Hour})
+	if err != nil {
+		t.Fatalf("GC = %v, want the manifest kept as a plain blob", err)
+	}
+	if has, _ := s.Has(d); !has || stats.Marked != 1 {
+		t.Errorf("GC kept the blob %v with %d marked, want it kept and marked", has, stats.Marked)
+	}
+}
+
+func TestFsck(t *testing.T) {
+	s := open(t)
+
+	problems, err := s.Fsck()
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("Fsck of an empty store = %v, %v", problems, err)
+	}
+
+	a := put(t, s, "a")
+	bad := put(t, s, "hello")
+	m, err := s.PutManifest([]Digest{a, helloDigest[:len(helloDigest)-1] + "5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupt(t, s, bad)
+
+	if err := s.SetRef("gone", Digest("sha256:"+strings.Repeat("0", 64))); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.root, "sha256", "stray"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err = s.Fsck()
+	if err != nil {
+		t.Fatalf("Fsck: %v", err)
+	}
+
+	want := map[string]error{
+		s.Path(bad):                              ErrCorrupt,
+		s.Path(m):                                ErrNotFound,
+		filepath.Join(s.root, "refs", "gone"):    ErrNotFound,
+		filepath.Join(s.root, "sha256", "stray"): ErrStrayFile,
+	}
+
+	if len(problems) != len(want) {
+		t.Errorf("Fsck found %d problems, want %d: %v", len(problems), len(want), problems)
+	}
+	for _, p := range problems {
+		if !errors.Is(p, want[p.Path]) {
+			t.Errorf("problem %v, want %v", p, want[p.Path])
+		}
+	}
+}
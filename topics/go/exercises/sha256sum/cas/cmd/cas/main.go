@@ -0,0 +1,227 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// This program stores files in a content-addressed store so identical
+// files are only kept once.
+//
+// ./cas put -manifest -ref release-1.2 build/*.tar.gz
+// ./cas get release-1.2
+// ./cas gc -grace 1h
+// ./cas fsck
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/exercises/sha256sum/cas"
+)
+
+// usage describes the commands.
+const usage = `Usage: cas [-store dir] command [flags] [args]
+
+Commands:
+  put [-ref name] [-manifest] [file...]  store files, or stdin, and print their digests
+  get [-o file] digest|ref               write a blob to stdout or a file
+  gc [-grace duration] [-dry-run]        remove blobs not reachable from a ref
+  fsck                                   check every blob and ref
+
+The store defaults to $CAS_STORE or .cas in the current directory.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the program with the arguments and returns its exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	root := os.Getenv("CAS_STORE")
+	if root == "" {
+		root = ".cas"
+	}
+
+	fs := flag.NewFlagSet("cas", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&root, "store", root, "directory of the store")
+	fs.Usage = func() { fmt.Fprint(stderr, usage) }
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	s, err := cas.Open(root)
+	if err != nil {
+		fmt.Fprintln(stderr, "cas:", err)
+		return 1
+	}
+
+	cmds := map[string]func(*cas.Store, []string, io.Reader, io.Writer) error{
+		"put":  put,
+		"get":  get,
+		"gc":   gc,
+		"fsck": fsck,
+	}
+
+	cmd, exists := cmds[fs.Arg(0)]
+	if !exists {
+		fmt.Fprintf(stderr, "cas: unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return 2
+	}
+
+	if err := cmd(s, fs.Args()[1:], stdin, stdout); err != nil {
+		fmt.Fprintf(stderr, "cas %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+
+	return 0
+}
+
+// put stores files and prints their digests like sha256sum does.
+func put(s *cas.Store, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("put", flag.ContinueOnError)
+	ref := fs.String("ref", "", "name the blob, or the manifest, so gc keeps it")
+	manifest := fs.Bool("manifest", false, "also store a manifest listing the files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	if *ref != "" && !*manifest && len(files) > 1 {
+		return errors.New("-ref needs -manifest to name more than one file")
+	}
+
+	var ds []cas.Digest
+	for _, name := range files {
+		d, err := putFile(s, name, stdin)
+		if err != nil {
+			return err
+		}
+		ds = append(ds, d)
+		fmt.Fprintf(stdout, "%s  %s\n", d, name)
+	}
+
+	named := ds[0]
+	if *manifest {
+		d, err := s.PutManifest(ds)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%s  (manifest)\n", d)
+		named = d
+	}
+
+	if *ref != "" {
+		return s.SetRef(*ref, named)
+	}
+	return nil
+}
+
+// putFile stores one file, where "-" is stdin.
+func putFile(s *cas.Store, name string, stdin io.Reader) (cas.Digest, error) {
+	if name == "-" {
+		return s.Put(stdin)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return s.Put(f)
+}
+
+// get writes a blob named by its digest or a ref.
+func get(s *cas.Store, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	out := fs.String("o", "", "file to write to instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("a digest or ref is required")
+	}
+
+	d, err := cas.ParseDigest(fs.Arg(0))
+	if err != nil {
+		if d, err = s.Ref(fs.Arg(0)); err != nil {
+			return err
+		}
+	}
+
+	rc, err := s.Get(d)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if *out == "" {
+		_, err := io.Copy(stdout, rc)
+		return err
+	}
+
+	// Don't leave part of a corrupt blob behind.
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		os.Remove(*out)
+		return err
+	}
+	return f.Close()
+}
+
+// gc removes the blobs that no ref reaches.
+func gc(s *cas.Store, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gc", flag.ContinueOnError)
+	grace := fs.Duration("grace", time.Hour, "keep blobs written more recently than this")
+	dryRun := fs.Bool("dry-run", false, "report what would be removed without removing it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	stats, err := s.GC(cas.GCOptions{Grace: *grace, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+
+	verb := "removed"
+	if *dryRun {
+		verb = "would remove"
+	}
+	fmt.Fprintf(stdout, "%d blobs reachable, %s %d blobs (%d bytes)\n", stats.Marked, verb, stats.Swept, stats.Freed)
+
+	return nil
+}
+
+// fsck prints the problems found in the store.
+func fsck(s *cas.Store, args []string, stdin io.Reader, stdout io.Writer) error {
+	problems, err := s.Fsck()
+	for _, p := range problems {
+		fmt.Fprintln(stdout, p)
+	}
+
+	switch {
+	case err != nil:
+		return err
+	case len(problems) > 0:
+		return fmt.Errorf("%d problems found", len(problems))
+	}
+
+	fmt.Fprintln(stdout, "ok")
+	return nil
+}
@@ -0,0 +1,89 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package cas
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ErrStrayFile is reported for a file in the blob directory whose path
+// isn't a digest.
+var ErrStrayFile = errors.New("stray file")
+
+// Problem is something wrong found by Fsck.
+type Problem struct {
+	Path string // The blob or ref with the problem.
+	Err  error
+}
+
+// Error implements the error interface.
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %v", p.Path, p.Err)
+}
+
+// Unwrap returns the problem's cause.
+func (p Problem) Unwrap() error {
+	return p.Err
+}
+
+// Fsck checks the integrity of the store. Every blob is read and checked
+// against its digest, and every ref and manifest is checked to name blobs
+// that exist. It returns the problems found in a stable order.
+func (s *Store) Fsck() ([]Problem, error) {
+	var problems []Problem
+
+	check := func(path string, d Digest, info fs.FileInfo) error {
+		if d == "" {
+			problems = append(problems, Problem{Path: path, Err: ErrStrayFile})
+			return nil
+		}
+
+		// Reading a manifest checks it, other blobs have to be read.
+		ds, isManifest, err := s.Manifest(d)
+		if err == nil && !isManifest {
+			err = s.Verify(d)
+		}
+		if err != nil {
+			problems = append(problems, Problem{Path: path, Err: err})
+			return nil
+		}
+
+		if isManifest {
+			for _, c := range ds {
+				if ok, err := s.Has(c); err != nil || !ok {
+					problems = append(problems, Problem{Path: path, Err: fmt.Errorf("%w: lists %s", ErrNotFound, c)})
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := s.walk(check); err != nil {
+		return problems, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(s.root, "refs"))
+	if err != nil {
+		return problems, err
+	}
+
+	for _, e := range entries {
+		path := filepath.Join(s.root, "refs", e.Name())
+
+		d, err := s.Ref(e.Name())
+		if err != nil {
+			problems = append(problems, Problem{Path: path, Err: err})
+			continue
+		}
+		if ok, err := s.Has(d); err != nil || !ok {
+			problems = append(problems, Problem{Path: path, Err: fmt.Errorf("%w: %s", ErrNotFound, d)})
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,191 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package cas
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GCOptions changes what a garbage collection removes.
+type GCOptions struct {
+	// Roots are kept along with the blobs named by refs.
+	Roots []Digest
+
+	// Grace keeps blobs and temp files written more recently, so a blob
+	// that was just put isn't removed before it's given a ref.
+	Grace time.Duration
+
+	// DryRun reports what would be removed without removing it.
+	DryRun bool
+}
+
+// GCStats describes what a garbage collection did.
+type GCStats struct {
+	Marked int   // Blobs reachable from the roots.
+	Swept  int   // Blobs removed.
+	Freed  int64 // Bytes removed.
+}
+
+// GC removes the blobs that can't be reached from the roots. The roots are
+// the blobs named by refs and in the options, and a manifest reaches the
+// blobs it lists.
+func (s *Store) GC(opts GCOptions) (GCStats, error) {
+	var stats GCStats
+
+	refs, err := s.Refs()
+	if err != nil {
+		return stats, err
+	}
+
+	roots := append([]Digest(nil), opts.Roots...)
+	for _, d := range refs {
+		roots = append(roots, d)
+	}
+
+	marked, err := s.mark(roots)
+	if err != nil {
+		return stats, fmt.Errorf("marking: %w", err)
+	}
+	stats.Marked = len(marked)
+
+	cutoff := time.Now().Add(-opts.Grace)
+
+	sweep := func(path string, d Digest, info fs.FileInfo) error {
+		if d == "" || marked[d] || info.ModTime().After(cutoff) {
+			return nil
+		}
+
+		if !opts.DryRun {
+			removed, err := s.remove(path, d, cutoff)
+			if err != nil || !removed {
+				return err
+			}
+		}
+
+		stats.Swept++
+		stats.Freed += info.Size()
+		return nil
+	}
+
+	if err := s.walk(sweep); err != nil {
+		return stats, fmt.Errorf("sweeping: %w", err)
+	}
+
+	// Temp files left by writers that crashed.
+	if !opts.DryRun {
+		entries, err := os.ReadDir(filepath.Join(s.root, "tmp"))
+		if err != nil {
+			return stats, fmt.Errorf("sweeping: %w", err)
+		}
+		for _, e := range entries {
+			if info, err := e.Info(); err == nil && info.ModTime().Before(cutoff) {
+				os.Remove(filepath.Join(s.root, "tmp", e.Name()))
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// remove removes a blob unless it was put again since it was found, and
+// reports if it did.
+//
+// A Put of the same content refreshes the blob's modification time, which
+// can happen between the walk and the removal, even from another process.
+// Moving the blob aside first means such a Put either lands before the
+// move, where its time is seen here and the blob is moved back, or finds
+// no blob and writes it again.
+func (s *Store) remove(path string, d Digest, cutoff time.Time) (bool, error) {
+	aside := filepath.Join(s.root, "tmp", "gc-"+d.Hex())
+	if err := os.Rename(path, aside); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil // Removed by another GC.
+		}
+		return false, err
+	}
+
+	if info, err := os.Stat(aside); err == nil && info.ModTime().After(cutoff) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return false, err
+		}
+		return false, os.Rename(aside, path)
+	}
+
+	if err := os.Remove(aside); err != nil {
+		return false, err
+	}
+	os.Remove(filepath.Dir(path)) // Fails unless the fan out directory is empty.
+
+	return true, nil
+}
+
+// mark returns every blob reachable from the roots. A root that's missing
+// is skipped, but a manifest that can't be read stops the collection since
+// the blobs it lists can't be known. A manifest that lists something other
+// than digests is kept as a plain blob, so one bad manifest doesn't stop
+// every collection; Fsck reports it.
+func (s *Store) mark(roots []Digest) (map[Digest]bool, error) {
+	marked := make(map[Digest]bool)
+
+	for len(roots) > 0 {
+		d := roots[len(roots)-1]
+		roots = roots[:len(roots)-1]
+
+		if marked[d] {
+			continue
+		}
+
+		ds, _, err := s.Manifest(d)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			continue
+		case errors.Is(err, ErrInvalidDigest):
+			marked[d] = true
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		marked[d] = true
+		roots = append(roots, ds...)
+	}
+
+	return marked, nil
+}
+
+// walk calls fn for every file under the blob directory in lexical order.
+// The digest is empty for a file whose path isn't a valid digest.
+func (s *Store) walk(fn func(path string, d Digest, info fs.FileInfo) error) error {
+	dir := filepath.Join(s.root, algorithm)
+
+	return filepath.WalkDir(dir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil || e.IsDir() {
+			return err
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		prefix, rest, _ := strings.Cut(filepath.ToSlash(rel), "/")
+		d, err := ParseDigest(algorithm + ":" + prefix + rest)
+		if err != nil || len(prefix) != 2 {
+			d = ""
+		}
+
+		return fn(path, d, info)
+	})
+}
@@ -0,0 +1,150 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package cas
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidRef is returned for a reference name that can't be stored.
+var ErrInvalidRef = errors.New("invalid ref name")
+
+// refRE defines the form of a valid reference name.
+var refRE = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SetRef names a blob, which keeps it and everything its manifest lists
+// from being collected as garbage.
+func (s *Store) SetRef(name string, d Digest) error {
+	if !refRE.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidRef, name)
+	}
+	if _, err := ParseDigest(string(d)); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Join(s.root, "tmp"), "ref-")
+	if err != nil {
+		return fmt.Errorf("writing ref: %w", err)
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err := fmt.Fprintln(f, d); err != nil {
+		f.Close()
+		return fmt.Errorf("writing ref: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing ref: %w", err)
+	}
+
+	if err := os.Rename(tmp, filepath.Join(s.root, "refs", name)); err != nil {
+		return fmt.Errorf("writing ref: %w", err)
+	}
+
+	return nil
+}
+
+// Ref returns the blob a reference names.
+func (s *Store) Ref(name string) (Digest, error) {
+	if !refRE.MatchString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidRef, name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.root, "refs", name))
+	if err != nil {
+		return "", err
+	}
+
+	return ParseDigest(strings.TrimSpace(string(data)))
+}
+
+// DeleteRef removes a reference. The blob it named is collected by the
+// next GC unless something else refers to it.
+func (s *Store) DeleteRef(name string) error {
+	if !refRE.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidRef, name)
+	}
+	return os.Remove(filepath.Join(s.root, "refs", name))
+}
+
+// Refs returns every reference by name.
+func (s *Store) Refs() (map[string]Digest, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, "refs"))
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]Digest, len(entries))
+	for _, e := range entries {
+		d, err := s.Ref(e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("ref %s: %w", e.Name(), err)
+		}
+		refs[e.Name()] = d
+	}
+
+	return refs, nil
+}
+
+// manifestHeader starts every manifest blob.
+const manifestHeader = "cas-manifest/v1\n"
+
+// PutManifest stores a blob listing other blobs, such as the files of a
+// release. The blobs listed are kept as long as the manifest is.
+func (s *Store) PutManifest(ds []Digest) (Digest, error) {
+	var b bytes.Buffer
+	b.WriteString(manifestHeader)
+
+	for _, d := range ds {
+		if _, err := ParseDigest(string(d)); err != nil {
+			return "", err
+		}
+		fmt.Fprintln(&b, d)
+	}
+
+	return s.Put(&b)
+}
+
+// Manifest returns the blobs listed by a manifest. It reports false if the
+// blob isn't a manifest.
+func (s *Store) Manifest(d Digest) ([]Digest, bool, error) {
+	rc, err := s.Get(d)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rc.Close()
+
+	br := bufio.NewReader(rc)
+	header := make([]byte, len(manifestHeader))
+	if _, err := io.ReadFull(br, header); err != nil || string(header) != manifestHeader {
+		return nil, false, nil
+	}
+
+	var ds []Digest
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			c, perr := ParseDigest(strings.TrimSuffix(line, "\n"))
+			if perr != nil {
+				return nil, true, fmt.Errorf("manifest %s: %w", d, perr)
+			}
+			ds = append(ds, c)
+		}
+
+		switch {
+		case err == io.EOF:
+			return ds, true, nil
+		case err != nil:
+			return nil, true, fmt.Errorf("manifest %s: %w", d, err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"maps"
+	"slices"
+)
+
+// algorithm is a hash the program can compute.
+type algorithm struct {
+	new  func() hash.Hash
+	tag  string // Name used by the --tag format, ex. "SHA256".
+	prog string // The coreutils program computing the same sums.
+	size int    // Length of the sum in bytes.
+}
+
+// algorithms are the supported hashes by the name given to -a.
+var algorithms = map[string]algorithm{
+	"md5":     {new: md5.New, tag: "MD5", prog: "md5sum", size: md5.Size},
+	"sha1":    {new: sha1.New, tag: "SHA1", prog: "sha1sum", size: sha1.Size},
+	"sha256":  {new: sha256.New, tag: "SHA256", prog: "sha256sum", size: sha256.Size},
+	"sha512":  {new: sha512.New, tag: "SHA512", prog: "sha512sum", size: sha512.Size},
+	"blake2b": {new: newBLAKE2b, tag: "BLAKE2b", prog: "b2sum", size: blake2bSize},
+}
+
+// algorithmNames returns the names of the supported hashes in order.
+func algorithmNames() []string {
+	return slices.Sorted(maps.Keys(algorithms))
+}
@@ -0,0 +1,53 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"slices"
+	"testing"
+)
+
+func TestBLAKE2b(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+		{"abc", "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+	}
+
+	for _, test := range tests {
+		h := newBLAKE2b()
+		h.Write([]byte(test.input))
+		if got := hex.EncodeToString(h.Sum(nil)); got != test.want {
+			t.Errorf("BLAKE2b(%q) = %s, want %s", test.input, got, test.want)
+		}
+	}
+}
+
+// TestBLAKE2bWrites checks the sum doesn't depend on how the input is split
+// into writes, including writes that end on a block boundary.
+func TestBLAKE2bWrites(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64)
+
+	h := newBLAKE2b()
+	h.Write(data)
+	want := h.Sum(nil)
+
+	for _, size := range []int{1, 7, 64, 128, 129, 1000} {
+		h.Reset()
+		for chunk := range slices.Chunk(data, size) {
+			h.Write(chunk)
+		}
+
+		// Sum must not change the state so more can be written after it.
+		h.Sum(nil)
+
+		if got := h.Sum(nil); !bytes.Equal(got, want) {
+			t.Errorf("Writing %d bytes at a time got %x, want %x", size, got, want)
+		}
+	}
+}
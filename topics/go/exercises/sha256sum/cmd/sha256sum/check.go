@@ -0,0 +1,319 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// entry is a line of a checksum file.
+type entry struct {
+	line     int
+	name     string
+	digest   string // Lower case hex.
+	improper bool   // The line couldn't be parsed.
+}
+
+// verified is the result of checking an entry.
+type verified struct {
+	match bool
+	err   error
+}
+
+// tally counts what happened checking one checksum file.
+type tally struct {
+	proper     int // Lines that could be parsed.
+	improper   int
+	unreadable int
+	mismatched int
+	matched    int
+}
+
+// checkAll checks the sums listed in every checksum file. It reports false
+// if any sum didn't match or couldn't be checked.
+func (p *program) checkAll() bool {
+	ok := true
+	for _, name := range p.files {
+		if !p.checkFile(name) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// checkFile checks the sums listed in one checksum file and prints a
+// summary of the problems found like coreutils does.
+func (p *program) checkFile(checkfile string) bool {
+	f, err := p.open(checkfile)
+	if err != nil {
+		p.errorf("%s: %s", quote(checkfile), errText(err))
+		return false
+	}
+	defer f.Close()
+
+	var t tally
+	var readErr error
+
+	work := func(e entry) verified {
+		if e.improper {
+			return verified{}
+		}
+		sum, err := p.sum(e.name)
+		if err != nil {
+			return verified{err: err}
+		}
+		return verified{match: hex.EncodeToString(sum) == e.digest}
+	}
+
+	emit := func(e entry, v verified) {
+		switch {
+		case e.improper:
+			t.improper++
+			if p.warn {
+				p.errorf("%s: %d: improperly formatted %s checksum line", quote(checkfile), e.line, p.algorithm.tag)
+			}
+			return
+
+		case v.err != nil:
+			t.proper++
+			if p.ignoreMissing && errors.Is(v.err, fs.ErrNotExist) {
+				return
+			}
+			t.unreadable++
+			p.errorf("%s: %s", quote(e.name), errText(v.err))
+			p.printResult(e.name, "FAILED open or read")
+
+		case !v.match:
+			t.proper++
+			t.mismatched++
+			p.printResult(e.name, "FAILED")
+
+		default:
+			t.proper++
+			t.matched++
+			if !p.quiet {
+				p.printResult(e.name, "OK")
+			}
+		}
+	}
+
+	ordered(p.workers, p.entries(f, &readErr), work, emit)
+
+	if readErr != nil {
+		p.errorf("%s: read error", quote(checkfile))
+		return false
+	}
+
+	if t.proper == 0 {
+		p.errorf("%s: no properly formatted checksum lines found", quote(checkfile))
+		return false
+	}
+
+	if !p.status {
+		if t.improper > 0 {
+			p.errorf("WARNING: %s improperly formatted", plural(t.improper, "line is", "lines are"))
+		}
+		if t.unreadable > 0 {
+			p.errorf("WARNING: %s could not be read", plural(t.unreadable, "listed file", "listed files"))
+		}
+		if t.mismatched > 0 {
+			p.errorf("WARNING: %s did NOT match", plural(t.mismatched, "computed checksum", "computed checksums"))
+		}
+		if p.ignoreMissing && t.matched == 0 {
+			p.errorf("%s: no file was verified", quote(checkfile))
+		}
+	}
+
+	return t.matched > 0 && t.mismatched == 0 && t.unreadable == 0 && (!p.strict || t.improper == 0)
+}
+
+// printResult prints the outcome of checking a file unless the status
+// option is set.
+func (p *program) printResult(name string, result string) {
+	if p.status {
+		return
+	}
+
+	// Unlike sum lines, names are only escaped when they hold a newline.
+	if strings.Contains(name, "\n") {
+		name, _ = escapeName(name)
+		p.stdout.WriteByte('\\')
+	}
+
+	p.stdout.WriteString(name + ": " + result + "\n")
+}
+
+// entries yields the lines of a checksum file. Comments and blank lines
+// are skipped. An error reading the file is stored in readErr.
+func (p *program) entries(r io.Reader, readErr *error) iter.Seq[entry] {
+	return func(yield func(entry) bool) {
+		br := bufio.NewReader(r)
+		parser := parser{algorithm: p.algorithm, reversed: -1}
+
+		for n := 1; ; n++ {
+			line, err := br.ReadString('\n')
+			if err != nil && err != io.EOF {
+				*readErr = err
+				return
+			}
+			if line == "" {
+				return
+			}
+
+			if !strings.HasPrefix(line, "#") {
+				line = strings.TrimSuffix(line, "\n")
+				line = strings.TrimSuffix(line, "\r")
+
+				if line != "" {
+					e := parser.parse(line)
+					e.line = n
+					if !yield(e) {
+						return
+					}
+				}
+			}
+
+			if err == io.EOF {
+				return
+			}
+		}
+	}
+}
+
+// parser reads lines of a checksum file in either format.
+//
+//	98ea6e4f216f2fb4b69fff9b3a44842c38686ca685f3f55dc48c5d3fb1107be4  name
+//	SHA256 (name) = 98ea6e4f216f2fb4b69fff9b3a44842c38686ca685f3f55dc48c5d3fb1107be4
+type parser struct {
+	algorithm algorithm
+
+	// reversed is set by the first untagged line. It's 1 when the sum and
+	// name are separated by one space as BSD's "md5 -r" writes them, 0 for
+	// the usual two characters, and -1 before any untagged line was read.
+	// Mixing the two is not allowed.
+	reversed int
+}
+
+// parse splits a line into the name and digest, following the rules of
+// coreutils.
+func (p *parser) parse(line string) entry {
+	improper := entry{improper: true}
+
+	i := 0
+	for i < len(line) && isWhite(line[i]) {
+		i++
+	}
+
+	escaped := i < len(line) && line[i] == '\\'
+	if escaped {
+		i++
+	}
+
+	if rest, found := strings.CutPrefix(line[i:], p.algorithm.tag); found {
+		if p.algorithm.tag == "BLAKE2b" {
+			rest = strings.TrimPrefix(rest, "-512")
+		}
+		rest = strings.TrimPrefix(rest, " ")
+		if tagged, found := strings.CutPrefix(rest, "("); found {
+			return p.parseTagged(tagged, escaped)
+		}
+	}
+
+	hexLen := 2 * p.algorithm.size
+	if len(line)-i < hexLen+2 {
+		return improper
+	}
+
+	digest := line[i : i+hexLen]
+	if !isHex(digest) {
+		return improper
+	}
+	i += hexLen
+
+	if !isWhite(line[i]) {
+		return improper
+	}
+	i++
+
+	if len(line)-i == 1 || (line[i] != ' ' && line[i] != '*') {
+		if p.reversed == 0 {
+			return improper
+		}
+		p.reversed = 1
+	} else if p.reversed != 1 {
+		p.reversed = 0
+		i++
+	}
+
+	name := line[i:]
+	if escaped {
+		var ok bool
+		if name, ok = unescapeName(name); !ok {
+			return improper
+		}
+	}
+
+	return entry{name: name, digest: strings.ToLower(digest)}
+}
+
+// parseTagged parses what follows the "(" of a tagged line.
+func (p *parser) parseTagged(s string, escaped bool) entry {
+	improper := entry{improper: true}
+
+	end := strings.LastIndexByte(s, ')')
+	if end < 0 {
+		return improper
+	}
+
+	name := s[:end]
+	if escaped {
+		var ok bool
+		if name, ok = unescapeName(name); !ok {
+			return improper
+		}
+	}
+
+	rest := strings.TrimLeft(s[end+1:], " \t")
+	rest, found := strings.CutPrefix(rest, "=")
+	if !found {
+		return improper
+	}
+
+	digest := strings.TrimLeft(rest, " \t")
+	if len(digest) != 2*p.algorithm.size || !isHex(digest) {
+		return improper
+	}
+
+	return entry{name: name, digest: strings.ToLower(digest)}
+}
+
+// isWhite reports if c is a space or tab.
+func isWhite(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// isHex reports if s only holds hex digits.
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", rune(s[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// plural returns the count with the singular or plural noun.
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return strconv.Itoa(n) + " " + many
+}
@@ -0,0 +1,190 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+// This program prints or checks the sums of files like the sha256sum
+// family of programs from GNU coreutils, and writes the same output. Files
+// are hashed in parallel and directories can be walked.
+//
+// ./sha256sum -r . > SHA256SUMS
+// ./sha256sum -c SHA256SUMS
+// ./sha256sum -a blake2b --tag main.go
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// config is how the program was asked to run on the command line.
+type config struct {
+	algorithm     algorithm
+	check         bool
+	binary        bool
+	text          bool
+	tag           bool
+	quiet         bool
+	status        bool
+	warn          bool
+	strict        bool
+	ignoreMissing bool
+	recursive     bool
+	workers       int
+	files         []string
+}
+
+// program holds what's needed to write output and report errors.
+type program struct {
+	config
+	stdin  io.Reader
+	stdout *bufio.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the program with the arguments and returns its exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var cfg config
+	var algo string
+
+	fs := flag.NewFlagSet("sha256sum", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&algo, "a", "sha256", "hash `algorithm`: "+strings.Join(algorithmNames(), ", "))
+	fs.BoolVar(&cfg.check, "c", false, "read checksums from the files and check them")
+	fs.BoolVar(&cfg.check, "check", false, "same as -c")
+	fs.BoolVar(&cfg.binary, "b", false, "read in binary mode, marked with a * in the output")
+	fs.BoolVar(&cfg.binary, "binary", false, "same as -b")
+	fs.BoolVar(&cfg.text, "t", false, "read in text mode, the default")
+	fs.BoolVar(&cfg.text, "text", false, "same as -t")
+	fs.BoolVar(&cfg.tag, "tag", false, "create a BSD-style checksum")
+	fs.BoolVar(&cfg.quiet, "quiet", false, "don't print OK for each successfully verified file")
+	fs.BoolVar(&cfg.status, "status", false, "don't output anything, the exit status shows success")
+	fs.BoolVar(&cfg.warn, "w", false, "warn about improperly formatted checksum lines")
+	fs.BoolVar(&cfg.warn, "warn", false, "same as -w")
+	fs.BoolVar(&cfg.strict, "strict", false, "exit non-zero for improperly formatted checksum lines")
+	fs.BoolVar(&cfg.ignoreMissing, "ignore-missing", false, "don't fail or report status for missing files")
+	fs.BoolVar(&cfg.recursive, "r", false, "sum the files in directories and their subdirectories")
+	fs.BoolVar(&cfg.recursive, "recursive", false, "same as -r")
+	fs.IntVar(&cfg.workers, "j", runtime.NumCPU(), "files to hash at the same time")
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 1
+	}
+	cfg.files = fs.Args()
+	if len(cfg.files) == 0 {
+		cfg.files = []string{"-"}
+	}
+
+	a, exists := algorithms[algo]
+	if !exists {
+		fmt.Fprintf(stderr, "sha256sum: unknown algorithm %q\n", algo)
+		return 1
+	}
+	cfg.algorithm = a
+
+	p := program{
+		config: cfg,
+		stdin:  stdin,
+		stdout: bufio.NewWriter(stdout),
+		stderr: stderr,
+	}
+	defer p.stdout.Flush()
+
+	if msg := cfg.invalid(); msg != "" {
+		p.errorf("%s", msg)
+		fmt.Fprintf(stderr, "Try '%s --help' for more information.\n", a.prog)
+		return 1
+	}
+
+	var ok bool
+	if cfg.check {
+		ok = p.checkAll()
+	} else {
+		ok = p.sumAll()
+	}
+
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// invalid returns why the options can't be used together, in the words
+// coreutils uses.
+func (cfg config) invalid() string {
+	if cfg.check {
+		switch {
+		case cfg.tag:
+			return "the --tag option is meaningless when verifying checksums"
+		case cfg.binary || cfg.text:
+			return "the --binary and --text options are meaningless when verifying checksums"
+		case cfg.recursive:
+			return "the --recursive option is meaningless when verifying checksums"
+		}
+	} else {
+		for _, opt := range []struct {
+			set  bool
+			name string
+		}{
+			{cfg.ignoreMissing, "--ignore-missing"},
+			{cfg.status, "--status"},
+			{cfg.warn, "--warn"},
+			{cfg.quiet, "--quiet"},
+			{cfg.strict, "--strict"},
+		} {
+			if opt.set {
+				return fmt.Sprintf("the %s option is meaningful only when verifying checksums", opt.name)
+			}
+		}
+	}
+
+	switch {
+	case cfg.tag && cfg.text:
+		return "--tag does not support --text mode"
+	case cfg.workers < 1:
+		return "-j must be at least 1"
+	}
+
+	return ""
+}
+
+// errorf reports an error on stderr prefixed with the program's name.
+// Output is flushed first so the two appear in order on a terminal.
+func (p *program) errorf(format string, args ...any) {
+	p.stdout.Flush()
+	fmt.Fprintf(p.stderr, "%s: %s\n", p.algorithm.prog, fmt.Sprintf(format, args...))
+}
+
+// open opens a file to read, where "-" is stdin.
+func (p *program) open(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(p.stdin), nil
+	}
+	return os.Open(name)
+}
+
+// sum returns the sum of a file's contents.
+func (p *program) sum(name string) ([]byte, error) {
+	f, err := p.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := p.algorithm.new()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,277 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// update rewrites the golden files with the output of GNU coreutils, which
+// must be installed.
+//
+//	go test -update
+var update = flag.Bool("update", false, "update the golden files in testdata by running coreutils")
+
+// files are the fixtures summed by most of the cases.
+var files = []string{
+	"testdata/tree/hello.txt",
+	"testdata/tree/empty",
+	"testdata/tree/with space.txt",
+	"testdata/tree/sub/data.bin",
+}
+
+// walked are the fixtures in the order walking testdata/tree finds them.
+var walked = []string{
+	"testdata/tree/empty",
+	"testdata/tree/hello.txt",
+	"testdata/tree/sub/data.bin",
+	"testdata/tree/sub/deeper/notes.md",
+	"testdata/tree/with space.txt",
+}
+
+// goldenCase runs the program and compares its output with what coreutils
+// wrote for the same arguments.
+type goldenCase struct {
+	name  string
+	args  []string
+	stdin string   // File to read as stdin.
+	gnu   []string // Arguments for coreutils when they differ.
+}
+
+// goldenCases are the cases with golden files.
+var goldenCases = []goldenCase{
+	{name: "sum", args: slices.Concat(files, []string{"testdata/tree/sub", "testdata/tree/missing"})},
+	{name: "sum-binary", args: slices.Concat([]string{"-b"}, files)},
+	{name: "sum-stdin", stdin: "testdata/tree/hello.txt"},
+	{name: "sum-md5", args: slices.Concat([]string{"-a", "md5"}, files)},
+	{name: "sum-sha1", args: slices.Concat([]string{"-a", "sha1"}, files)},
+	{name: "sum-sha512", args: slices.Concat([]string{"-a", "sha512"}, files)},
+	{name: "sum-blake2b", args: slices.Concat([]string{"-a", "blake2b"}, files)},
+	{name: "tag-md5", args: slices.Concat([]string{"-a", "md5", "--tag"}, files)},
+	{name: "tag-sha1", args: slices.Concat([]string{"-a", "sha1", "--tag"}, files)},
+	{name: "tag-sha256", args: slices.Concat([]string{"--tag"}, files)},
+	{name: "tag-sha512", args: slices.Concat([]string{"-a", "sha512", "--tag"}, files)},
+	{name: "tag-blake2b", args: slices.Concat([]string{"-a", "blake2b", "--tag"}, files)},
+	{name: "recursive", args: []string{"-r", "testdata/tree"}, gnu: walked},
+	{name: "recursive-one-worker", args: []string{"-r", "-j", "1", "testdata/tree"}, gnu: walked},
+	{name: "check", args: []string{"-c", "testdata/check/sha256.sums"}},
+	{name: "check-quiet", args: []string{"-c", "--quiet", "testdata/check/sha256.sums"}},
+	{name: "check-status", args: []string{"-c", "--status", "testdata/check/sha256.sums"}},
+	{name: "check-warn", args: []string{"-c", "--warn", "testdata/check/sha256.sums"}},
+	{name: "check-good", args: []string{"-c", "testdata/check/good.sums"}},
+	{name: "check-strict", args: []string{"-c", "--strict", "testdata/check/good.sums", "testdata/check/sha256.sums"}},
+	{name: "check-stdin", args: []string{"-c"}, stdin: "testdata/check/good.sums"},
+	{name: "check-ignore-missing", args: []string{"-c", "--ignore-missing", "testdata/check/missing.sums"}},
+	{name: "check-nothing-verified", args: []string{"-c", "--ignore-missing", "testdata/check/allmissing.sums"}},
+	{name: "check-reversed", args: []string{"-c", "testdata/check/reversed.sums"}},
+	{name: "check-garbage", args: []string{"-c", "testdata/check/garbage.sums"}},
+	{name: "check-no-file", args: []string{"-c", "testdata/check/nope.sums"}},
+	{name: "check-md5", args: []string{"-a", "md5", "-c", "testdata/check/md5.sums"}},
+	{name: "check-sha1", args: []string{"-a", "sha1", "-c", "testdata/check/sha1.sums"}},
+	{name: "check-sha512", args: []string{"-a", "sha512", "-c", "testdata/check/sha512.sums"}},
+	{name: "check-blake2b", args: []string{"-a", "blake2b", "-c", "testdata/check/blake2b.sums"}},
+	{name: "check-wrong-algorithm", args: []string{"-a", "sha1", "-c", "testdata/check/md5.sums"}},
+	{name: "usage-tag-check", args: []string{"--tag", "-c", "testdata/check/good.sums"}},
+	{name: "usage-quiet", args: []string{"--quiet", "testdata/tree/hello.txt"}},
+}
+
+// command returns the coreutils program and arguments for the case.
+func (gc goldenCase) command() (string, []string) {
+	algo := "sha256"
+	var args []string
+
+	for i := 0; i < len(gc.args); i++ {
+		switch gc.args[i] {
+		case "-a":
+			i++
+			algo = gc.args[i]
+		case "-j":
+			i++
+		default:
+			args = append(args, gc.args[i])
+		}
+	}
+
+	if gc.gnu != nil {
+		args = gc.gnu
+	}
+
+	return algorithms[algo].prog, args
+}
+
+// result formats the outcome of running a program for a golden file.
+func result(code int, stdout, stderr []byte) string {
+	return fmt.Sprintf("exit %d\n-- stdout --\n%s-- stderr --\n%s", code, stdout, stderr)
+}
+
+// runGNU runs the coreutils program for the case in the C locale.
+func runGNU(t *testing.T, gc goldenCase) string {
+	t.Helper()
+
+	prog, args := gc.command()
+	cmd := exec.Command(prog, args...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+
+	if gc.stdin != "" {
+		f, err := os.Open(gc.stdin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		cmd.Stdin = f
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatalf("Running %s: %v", prog, err)
+	}
+
+	return result(cmd.ProcessState.ExitCode(), stdout.Bytes(), stderr.Bytes())
+}
+
+func TestGolden(t *testing.T) {
+	for _, gc := range goldenCases {
+		fn := func(t *testing.T) {
+			golden := filepath.Join("testdata", "golden", gc.name+".golden")
+
+			if *update {
+				if err := os.WriteFile(golden, []byte(runGNU(t, gc)), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stdin := strings.NewReader("")
+			if gc.stdin != "" {
+				data, err := os.ReadFile(gc.stdin)
+				if err != nil {
+					t.Fatal(err)
+				}
+				stdin = strings.NewReader(string(data))
+			}
+
+			var stdout, stderr bytes.Buffer
+			code := run(gc.args, stdin, &stdout, &stderr)
+
+			if got := result(code, stdout.Bytes(), stderr.Bytes()); got != string(want) {
+				prog, args := gc.command()
+				t.Errorf("Output did not match %s %q", prog, args)
+				t.Logf("Got:\n%s", got)
+				t.Logf("Want:\n%s", want)
+			}
+		}
+		t.Run(gc.name, fn)
+	}
+}
+
+// TestNames compares the output with coreutils for file names that have
+// to be escaped or quoted. The names can't all be created on every system
+// so they aren't in testdata.
+func TestNames(t *testing.T) {
+	if _, err := exec.LookPath("sha256sum"); err != nil {
+		t.Skip("coreutils is not installed")
+	}
+
+	names := []string{"b\\c", "n\nl", "x\\y\nz", "it's", "sp ace", "tab\tx", "\xc3\xa9", "~home", "a~b", "#a", "{", "a=b"}
+
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Work in the temp dir so the names are used as they are.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	var missing []string
+	for _, name := range names {
+		missing = append(missing, "missing "+name)
+	}
+
+	tests := []goldenCase{
+		{name: "sum", args: names},
+		{name: "tag", args: slices.Concat([]string{"--tag"}, names)},
+		{name: "missing", args: missing},
+	}
+
+	for _, gc := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(gc.args, strings.NewReader(""), &stdout, &stderr)
+
+		got := result(code, stdout.Bytes(), stderr.Bytes())
+		if want := runGNU(t, gc); got != want {
+			t.Errorf("%s: output did not match coreutils", gc.name)
+			t.Logf("Got:\n%s", got)
+			t.Logf("Want:\n%s", want)
+		}
+
+		// The sums written should check out the same way.
+		if gc.name == "missing" {
+			continue
+		}
+		if err := os.WriteFile(gc.name+".sums", stdout.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		check := goldenCase{name: "check", args: []string{"-c", gc.name + ".sums"}}
+
+		stdout.Reset()
+		stderr.Reset()
+		code = run(check.args, strings.NewReader(""), &stdout, &stderr)
+
+		got = result(code, stdout.Bytes(), stderr.Bytes())
+		if want := runGNU(t, check); got != want {
+			t.Errorf("%s: checking did not match coreutils", gc.name)
+			t.Logf("Got:\n%s", got)
+			t.Logf("Want:\n%s", want)
+		}
+	}
+}
+
+// TestInputsStop checks the walk stops when the consumer does, even with
+// more directories to walk.
+func TestInputsStop(t *testing.T) {
+	p := program{config: config{
+		recursive: true,
+		files:     []string{"testdata/tree", "testdata/tree"},
+	}}
+
+	var got []string
+	for in := range p.inputs() {
+		got = append(got, in.name)
+		if len(got) == 2 {
+			break
+		}
+	}
+
+	if len(got) != 2 {
+		t.Errorf("got %d inputs, want 2: %v", len(got), got)
+	}
+}
@@ -0,0 +1,54 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"iter"
+	"sync"
+)
+
+// ordered calls work for each job on up to workers goroutines and calls
+// emit with the results on the calling goroutine in the order the jobs
+// were produced. Only a few jobs are allowed to get ahead of the one being
+// waited on so a slow file doesn't hold every other result in memory.
+func ordered[J, R any](workers int, jobs iter.Seq[J], work func(J) R, emit func(J, R)) {
+	type task struct {
+		job    J
+		result R
+		done   chan struct{}
+	}
+
+	// The window is the tasks started but not yet emitted, in order.
+	window := make(chan *task, 2*workers)
+	queue := make(chan *task)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range queue {
+				t.result = work(t.job)
+				close(t.done)
+			}
+		}()
+	}
+
+	go func() {
+		for job := range jobs {
+			t := task{job: job, done: make(chan struct{})}
+			window <- &t
+			queue <- &t
+		}
+		close(queue)
+		close(window)
+	}()
+
+	for t := range window {
+		<-t.done
+		emit(t.job, t.result)
+	}
+
+	wg.Wait()
+}
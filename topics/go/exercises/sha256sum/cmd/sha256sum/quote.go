@@ -0,0 +1,158 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+// These functions reproduce how GNU coreutils writes file names so the
+// output of the two programs can be compared byte for byte.
+
+// escapeName escapes backslashes and newlines in a file name so it fits on
+// one line of a checksum file. It reports if anything was escaped, in which
+// case coreutils starts the line with a backslash.
+func escapeName(name string) (string, bool) {
+	if !strings.ContainsAny(name, "\\\n") {
+		return name, false
+	}
+
+	r := strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	return r.Replace(name), true
+}
+
+// unescapeName reverses escapeName. It reports false for any other escape.
+func unescapeName(name string) (string, bool) {
+	var b strings.Builder
+
+	for i := 0; i < len(name); i++ {
+		if name[i] != '\\' {
+			b.WriteByte(name[i])
+			continue
+		}
+
+		if i++; i == len(name) {
+			return "", false
+		}
+		switch name[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			return "", false
+		}
+	}
+
+	return b.String(), true
+}
+
+// quote returns a file name for an error message, quoted like a shell
+// argument when it holds anything special. Bytes outside of printable
+// ASCII are escaped as coreutils does in the C locale.
+//
+//	sp ace  'sp ace'
+//	it's    "it's"
+//	a\nb    'a'$'\n''b'
+func quote(name string) string {
+	if name == "" {
+		return "''"
+	}
+
+	special, control := false, false
+	for i := 0; i < len(name); i++ {
+		switch c := name[i]; {
+		case c < ' ' || c > '~':
+			control = true
+		case strings.IndexByte(" !\"$&'()*;<=>?[\\^`|:", c) >= 0:
+			special = true
+		case (c == '#' || c == '~') && i == 0:
+			special = true
+		case (c == '{' || c == '}') && len(name) == 1:
+			special = true
+		}
+	}
+
+	switch {
+	case !special && !control:
+		return name
+	case !control && strings.Contains(name, "'") && !strings.ContainsAny(name, "\"$`\\!"):
+		return `"` + name + `"`
+	}
+
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		if c >= ' ' && c <= '~' {
+			if !quoted {
+				b.WriteByte('\'')
+				quoted = true
+			}
+			if c == '\'' {
+				b.WriteString(`'\''`)
+				continue
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		// Control characters go in a $'...' section between quotes.
+		if quoted || i == 0 {
+			b.WriteByte('\'')
+			if i == 0 {
+				b.WriteByte('\'')
+			}
+			quoted = false
+		}
+		b.WriteString("$'")
+		for ; i < len(name) && (name[i] < ' ' || name[i] > '~'); i++ {
+			b.WriteString(cEscape(name[i]))
+		}
+		b.WriteByte('\'')
+		i--
+	}
+	if quoted {
+		b.WriteByte('\'')
+	}
+
+	return b.String()
+}
+
+// cEscape returns the C escape for a byte.
+func cEscape(c byte) string {
+	switch c {
+	case '\a':
+		return `\a`
+	case '\b':
+		return `\b`
+	case '\f':
+		return `\f`
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\t':
+		return `\t`
+	case '\v':
+		return `\v`
+	}
+	return fmt.Sprintf(`\%03o`, c)
+}
+
+// errText returns the message for an error like strerror does, ex.
+// "No such file or directory".
+func errText(err error) string {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return err.Error()
+	}
+
+	msg := errno.Error()
+	return strings.ToUpper(msg[:1]) + msg[1:]
+}
@@ -0,0 +1,117 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+)
+
+// input is a file to sum, or the error found looking for it.
+type input struct {
+	name string
+	err  error
+}
+
+// summed is the sum of an input.
+type summed struct {
+	sum []byte
+	err error
+}
+
+// sumAll prints the sum of every file in the order given. It reports false
+// if any file couldn't be read.
+func (p *program) sumAll() bool {
+	ok := true
+
+	work := func(in input) summed {
+		if in.err != nil {
+			return summed{err: in.err}
+		}
+		sum, err := p.sum(in.name)
+		return summed{sum: sum, err: err}
+	}
+
+	emit := func(in input, s summed) {
+		if s.err != nil {
+			p.errorf("%s: %s", quote(in.name), errText(s.err))
+			ok = false
+			return
+		}
+		p.printSum(in.name, s.sum)
+	}
+
+	ordered(p.workers, p.inputs(), work, emit)
+
+	return ok
+}
+
+// inputs yields the files to sum. Directories are walked in lexical order
+// when the recursive option is set, otherwise they're reported as errors
+// when read.
+func (p *program) inputs() iter.Seq[input] {
+	return func(yield func(input) bool) {
+		for _, name := range p.files {
+			if !p.recursive || name == "-" {
+				if !yield(input{name: name}) {
+					return
+				}
+				continue
+			}
+
+			if info, err := os.Stat(name); err != nil || !info.IsDir() {
+				if !yield(input{name: name}) {
+					return
+				}
+				continue
+			}
+
+			// WalkDir returns nil for SkipAll, so record when the
+			// consumer stops to stop the outer loop too.
+			var stopped bool
+			walk := func(path string, d fs.DirEntry, err error) error {
+				switch {
+				case err != nil:
+					stopped = !yield(input{name: path, err: err})
+				case !d.IsDir():
+					stopped = !yield(input{name: path})
+				}
+				if stopped {
+					return fs.SkipAll
+				}
+				return nil
+			}
+
+			filepath.WalkDir(name, walk)
+			if stopped {
+				return
+			}
+		}
+	}
+}
+
+// printSum writes a line of a checksum file for the file.
+//
+//	98ea6e4f216f2fb4b69fff9b3a44842c38686ca685f3f55dc48c5d3fb1107be4  name
+//	SHA256 (name) = 98ea6e4f216f2fb4b69fff9b3a44842c38686ca685f3f55dc48c5d3fb1107be4
+func (p *program) printSum(name string, sum []byte) {
+	name, escaped := escapeName(name)
+	if escaped {
+		p.stdout.WriteByte('\\')
+	}
+
+	if p.tag {
+		fmt.Fprintf(p.stdout, "%s (%s) = %x\n", p.algorithm.tag, name, sum)
+		return
+	}
+
+	mode := ' '
+	if p.binary {
+		mode = '*'
+	}
+	fmt.Fprintf(p.stdout, "%x %c%s\n", sum, mode, name)
+}
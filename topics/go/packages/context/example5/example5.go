@@ -14,6 +14,7 @@ import (
 	"time"
 )
 
+//runexamples:skip needs a network connection
 func main() {
 
 	// Create a new request.
@@ -28,6 +28,7 @@ type User struct {
 	Email string
 }
 
+//runexamples:skip listens for connections
 func main() {
 	routes()
 
@@ -12,6 +12,7 @@ import (
 	"os"
 )
 
+//runexamples:skip needs a network connection
 func main() {
 
 	// Retrieve the RSS feed for the blog.
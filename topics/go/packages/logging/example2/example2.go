@@ -53,6 +53,7 @@ func initLog(traceHandle io.Writer, infoHandle io.Writer, warningHandle io.Write
 		log.Ldate|log.Ltime|log.Lshortfile)
 }
 
+//runexamples:skip writes files next to its source
 func main() {
 
 	// Open a file for warnings.
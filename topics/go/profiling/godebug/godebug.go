@@ -16,6 +16,7 @@ import (
 
 var leak bool
 
+//runexamples:skip listens for connections
 func main() {
 	http.HandleFunc("/sendjson", sendJSON)
 
@@ -12,6 +12,7 @@ import (
 	"net/http/httptrace"
 )
 
+//runexamples:skip needs a network connection
 func main() {
 
 	// Create a new request for the call.
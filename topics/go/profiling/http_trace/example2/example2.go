@@ -30,6 +30,7 @@ func (t *transport) GotConn(info httptrace.GotConnInfo) {
 	log.Printf("Connection reused for %v? %v\n", t.current.URL, info.Reused)
 }
 
+//runexamples:skip needs a network connection
 func main() {
 
 	// Create a new request for the call.
@@ -0,0 +1,38 @@
+// This program is a stand in for an OpenTelemetry Collector. It accepts the
+// spans the search service exports over OTLP/HTTP and prints a line for each
+// one, so traces can be watched during development.
+//
+// ./collector -addr localhost:4318
+// ./project -trace-endpoint http://localhost:4318/v1/traces
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
+)
+
+//runexamples:skip listens for connections
+func main() {
+	addr := flag.String("addr", "localhost:4318", "address to listen on")
+	flag.Parse()
+
+	c := telemetry.Collector{
+		OnSpans: func(spans []telemetry.SpanData) {
+			for _, sd := range spans {
+				status := ""
+				if sd.Status == telemetry.StatusError {
+					status = " ERROR: " + sd.StatusMessage
+				}
+				fmt.Printf("%s %s %-16s %-40s %10v%s\n", sd.TraceID, sd.SpanID, sd.ParentSpanID, sd.Name, sd.Duration().Round(time.Microsecond), status)
+			}
+		},
+	}
+
+	log.Println("Listening on:", *addr)
+	log.Fatalln(http.ListenAndServe(*addr, &c))
+}
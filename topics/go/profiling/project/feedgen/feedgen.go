@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// words are used to build the titles and descriptions. The terms searched
+// by loadgen by default are included so searches find results.
+var words = strings.Fields(`
+	trump president weather climate go gophers election senate congress court
+	market stocks economy inflation jobs energy storm flood wildfire science
+	space rocket health vaccine hospital school students city council police
+	football soccer olympics music film festival travel airline border trade
+	tariff china europe russia india africa brazil canada mexico japan korea
+	report says new first year week today after before amid over under against
+	talks deal plan vote law bill budget tax water power data security privacy
+	technology software cloud network phone battery electric cars train bridge
+	`)
+
+// Config describes the feed to generate.
+type Config struct {
+	Title string
+	Link  string
+	Items int
+	Words int // Number of words in each description.
+	Seed  uint64
+}
+
+// The types below are the parts of an RSS 2.0 document that are generated.
+type (
+	rss struct {
+		XMLName xml.Name `xml:"rss"`
+		Version string   `xml:"version,attr"`
+		Channel channel  `xml:"channel"`
+	}
+
+	channel struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		Items       []item `xml:"item"`
+	}
+
+	item struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		GUID        string `xml:"guid"`
+		Description string `xml:"description"`
+		PubDate     string `xml:"pubDate"`
+	}
+)
+
+// Generate writes an RSS document of random news items. The same config
+// always produces the same document.
+func Generate(w io.Writer, cfg Config) error {
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed>>32|1))
+
+	doc := rss{
+		Version: "2.0",
+		Channel: channel{
+			Title:       cfg.Title,
+			Link:        cfg.Link,
+			Description: fmt.Sprintf("%d generated items", cfg.Items),
+			Items:       make([]item, cfg.Items),
+		},
+	}
+
+	// Items are a few minutes apart, newest first.
+	published := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := range doc.Channel.Items {
+		link := fmt.Sprintf("%s/%d", strings.TrimSuffix(cfg.Link, "/"), i)
+		published = published.Add(-time.Duration(1+rng.IntN(30)) * time.Minute)
+
+		doc.Channel.Items[i] = item{
+			Title:       sentence(rng, 6+rng.IntN(6)),
+			Link:        link,
+			GUID:        link,
+			Description: "<p>" + sentence(rng, cfg.Words) + "</p>",
+			PubDate:     published.Format(time.RFC1123Z),
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// sentence returns n random words starting with a capital letter.
+func sentence(rng *rand.Rand, n int) string {
+	var b strings.Builder
+	for i := range n {
+		w := words[rng.IntN(len(words))]
+		if i == 0 {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		} else {
+			b.WriteByte(' ')
+		}
+		b.WriteString(w)
+	}
+	b.WriteByte('.')
+	return b.String()
+}
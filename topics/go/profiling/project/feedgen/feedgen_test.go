@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"path/filepath"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/search"
+)
+
+const (
+	succeed = "\u2713"
+	failed  = "\u2717"
+)
+
+// TestGenerate validates the generated feeds are repeatable and can be
+// searched in replay mode.
+func TestGenerate(t *testing.T) {
+	cfg := Config{Title: "Generated", Link: "http://example.com/gen.rss", Items: 200, Words: 40, Seed: 7}
+
+	t.Log("Given the need to load test the search with large feeds.")
+	{
+		t.Log("\tTest 0:\tWhen generating a feed twice with the same config.")
+		{
+			var a, b bytes.Buffer
+			if err := Generate(&a, cfg); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould generate the feed : %v", failed, err)
+			}
+			Generate(&b, cfg)
+
+			if !bytes.Equal(a.Bytes(), b.Bytes()) {
+				t.Fatalf("\t%s\tTest 0:\tShould generate the same document.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould generate the same document.", succeed)
+
+			var doc rss
+			if err := xml.Unmarshal(a.Bytes(), &doc); err != nil || len(doc.Channel.Items) != cfg.Items {
+				t.Fatalf("\t%s\tTest 0:\tShould contain %d items : %v", failed, cfg.Items, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould contain %d items.", succeed, cfg.Items)
+		}
+
+		t.Log("\tTest 1:\tWhen searching the feed in replay mode.")
+		{
+			dir := t.TempDir()
+			if err := write(search.FixturePath(dir, cfg.Link), cfg); err != nil {
+				t.Fatalf("\t%s\tTest 1:\tShould write the fixture : %v", failed, err)
+			}
+
+			if err := search.UseFixtures(search.FixturesReplay, dir); err != nil {
+				t.Fatalf("\t%s\tTest 1:\tShould switch to replay mode : %v", failed, err)
+			}
+			defer search.UseFixtures(search.FixturesLive, "")
+
+			results, err := search.NewRSS("Generated", []string{cfg.Link}).Search(context.Background(), "", "president")
+			if err != nil || len(results) == 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould find results : %d %v", failed, len(results), err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould find results : %d", succeed, len(results))
+
+			_, err = search.NewRSS("Missing", []string{"http://example.com/" + filepath.Base(dir)}).Search(context.Background(), "", "president")
+			if err == nil {
+				t.Fatalf("\t%s\tTest 1:\tShould fail for a feed that wasn't recorded.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould fail for a feed that wasn't recorded : %v", succeed, err)
+		}
+	}
+}
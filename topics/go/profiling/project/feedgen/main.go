@@ -0,0 +1,88 @@
+// This program writes a synthetic feed for every feed of the registered
+// providers into a fixtures directory, so the search service can be load
+// tested in replay mode without the network.
+//
+// ./feedgen -dir ../fixtures -items 2000
+// ../project -fixtures replay -fixtures-dir ../fixtures
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/search"
+)
+
+//runexamples:skip writes files next to its source
+func main() {
+	dir := flag.String("dir", "fixtures", "fixtures directory to write the feeds to")
+	providers := flag.String("providers", "", "YAML file of search providers to add or replace")
+	items := flag.Int("items", 500, "number of items in each feed")
+	words := flag.Int("words", 80, "number of words in each item's description")
+	seed := flag.Uint64("seed", 1, "seed for the generated text")
+	flag.Parse()
+
+	if *providers != "" {
+		f, err := os.Open(*providers)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		err = search.LoadProviders(f)
+		f.Close()
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		log.Fatalln(err)
+	}
+
+	for _, p := range search.Providers() {
+		for _, feed := range p.Feeds {
+
+			// Every feed gets different text for the same seed.
+			h := fnv.New64a()
+			h.Write([]byte(feed))
+
+			cfg := Config{
+				Title: p.Title,
+				Link:  feed,
+				Items: *items,
+				Words: *words,
+				Seed:  *seed ^ h.Sum64(),
+			}
+
+			path := search.FixturePath(*dir, feed)
+			if err := write(path, cfg); err != nil {
+				log.Fatalln(err)
+			}
+
+			fmt.Println(path)
+		}
+	}
+}
+
+// write generates the feed into the file.
+func write(path string, cfg Config) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	if err := Generate(w, cfg); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
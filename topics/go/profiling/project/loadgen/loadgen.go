@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Term is a search term and how often it's searched relative to the other
+// terms in the mix.
+type Term struct {
+	Term   string
+	Weight int
+}
+
+// ParseMix parses a comma separated list of terms with optional weights,
+// which default to 1.
+//
+//	trump:5,climate change:2,"go conference"
+func ParseMix(s string) ([]Term, error) {
+	var mix []Term
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		t := Term{Term: part, Weight: 1}
+		if i := strings.LastIndexByte(part, ':'); i >= 0 {
+			w, err := strconv.Atoi(part[i+1:])
+			if err != nil || w < 1 {
+				return nil, fmt.Errorf("term %q: weight must be a positive number", part)
+			}
+			t = Term{Term: strings.TrimSpace(part[:i]), Weight: w}
+		}
+
+		if t.Term == "" {
+			return nil, fmt.Errorf("term %q: term is empty", part)
+		}
+		mix = append(mix, t)
+	}
+
+	if len(mix) == 0 {
+		return nil, errors.New("no terms in the mix")
+	}
+
+	return mix, nil
+}
+
+// Config provides the settings for a load test.
+type Config struct {
+	URL         string        // Base URL of the service.
+	Mix         []Term        // Terms to search for.
+	Engines     string        // Comma separated providers, empty searches every provider.
+	Concurrency int           // Requests in flight at once.
+	Requests    int           // Requests to make, zero runs until the duration passes.
+	Duration    time.Duration // Time limit, zero runs until the requests are made.
+	Seed        uint64        // Seed for picking terms, so a run can be repeated.
+	Client      *http.Client
+}
+
+// Report summarizes the requests made by a load test.
+type Report struct {
+	Requests  int
+	Errors    int         // Requests that failed or didn't return a 200.
+	Statuses  map[int]int // Count of each status code, 0 for transport errors.
+	Terms     map[string]int
+	Elapsed   time.Duration
+	Latencies []time.Duration // Sorted latency of every request.
+}
+
+// Run makes the requests against the service, with up to Concurrency
+// requests in flight, until the requests are made, the duration passes or
+// the context is cancelled.
+func Run(ctx context.Context, cfg Config) (Report, error) {
+	if len(cfg.Mix) == 0 {
+		return Report{}, errors.New("no terms in the mix")
+	}
+	if cfg.Requests <= 0 && cfg.Duration <= 0 {
+		return Report{}, errors.New("a number of requests or a duration is required")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+
+	// The terms are picked by a single goroutine so the sequence only
+	// depends on the seed.
+	terms := make(chan string)
+	go func() {
+		defer close(terms)
+
+		pick := picker(cfg.Mix, cfg.Seed)
+		for i := 0; cfg.Requests <= 0 || i < cfg.Requests; i++ {
+			select {
+			case terms <- pick():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	type sample struct {
+		term    string
+		status  int
+		latency time.Duration
+	}
+
+	samples := make(chan sample, cfg.Concurrency)
+
+	var wg sync.WaitGroup
+	for range cfg.Concurrency {
+		wg.Go(func() {
+			for term := range terms {
+				status, latency := request(ctx, cfg, term)
+
+				// Requests cut short by the end of the run aren't counted.
+				if ctx.Err() != nil && status == 0 {
+					continue
+				}
+				samples <- sample{term, status, latency}
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(samples)
+	}()
+
+	r := Report{
+		Statuses: make(map[int]int),
+		Terms:    make(map[string]int),
+	}
+
+	start := time.Now()
+	for s := range samples {
+		r.Requests++
+		r.Statuses[s.status]++
+		r.Terms[s.term]++
+		if s.status != http.StatusOK {
+			r.Errors++
+		}
+		r.Latencies = append(r.Latencies, s.latency)
+	}
+	r.Elapsed = time.Since(start)
+
+	slices.Sort(r.Latencies)
+
+	return r, nil
+}
+
+// picker returns a function that picks terms at random in proportion to
+// their weights.
+func picker(mix []Term, seed uint64) func() string {
+	rnd := rand.New(rand.NewPCG(seed, seed))
+
+	var total int
+	for _, t := range mix {
+		total += t.Weight
+	}
+
+	return func() string {
+		n := rnd.IntN(total)
+		for _, t := range mix {
+			if n < t.Weight {
+				return t.Term
+			}
+			n -= t.Weight
+		}
+		return mix[len(mix)-1].Term
+	}
+}
+
+// request makes a single search, returning the status code and how long
+// the request took. The status is 0 if the request failed.
+func request(ctx context.Context, cfg Config, term string) (int, time.Duration) {
+	q := url.Values{"term": {term}}
+	if cfg.Engines != "" {
+		q.Set("engines", cfg.Engines)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(cfg.URL, "/")+"/api/v1/search?"+q.Encode(), nil)
+	if err != nil {
+		return 0, 0
+	}
+
+	start := time.Now()
+
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		return 0, time.Since(start)
+	}
+
+	// Read the whole response so the latency includes the body.
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return resp.StatusCode, time.Since(start)
+}
+
+// =============================================================================
+
+// Percentile returns the latency that p percent of the requests were
+// faster than or equal to.
+func (r Report) Percentile(p float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+
+	i := int(float64(len(r.Latencies))*p/100+0.5) - 1
+	return r.Latencies[min(max(i, 0), len(r.Latencies)-1)]
+}
+
+// Bucket is a range of latencies in a histogram.
+type Bucket struct {
+	Max   time.Duration // Upper bound of the bucket, inclusive.
+	Count int
+}
+
+// Histogram groups the latencies into buckets whose bounds double, starting
+// at 1ms.
+func (r Report) Histogram() []Bucket {
+	if len(r.Latencies) == 0 {
+		return nil
+	}
+
+	// The latencies are sorted, so buckets are added as they're needed.
+	buckets := []Bucket{{Max: time.Millisecond}}
+	for _, l := range r.Latencies {
+		for l > buckets[len(buckets)-1].Max {
+			buckets = append(buckets, Bucket{Max: buckets[len(buckets)-1].Max * 2})
+		}
+		buckets[len(buckets)-1].Count++
+	}
+
+	return buckets
+}
+
+// Print writes the report in a form meant to be read by people.
+func (r Report) Print(w io.Writer) {
+	rate := float64(r.Requests) / max(r.Elapsed.Seconds(), 0.001)
+
+	fmt.Fprintf(w, "Requests:\t%d in %v (%.1f/s)\n", r.Requests, r.Elapsed.Round(time.Millisecond), rate)
+	fmt.Fprintf(w, "Errors:\t\t%d\n", r.Errors)
+
+	codes := make([]int, 0, len(r.Statuses))
+	for code := range r.Statuses {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+
+	fmt.Fprintln(w, "\nStatus codes:")
+	for _, code := range codes {
+		name := http.StatusText(code)
+		if code == 0 {
+			name = "request failed"
+		}
+		fmt.Fprintf(w, "  %3d %-20s %d\n", code, name, r.Statuses[code])
+	}
+
+	if len(r.Latencies) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nLatency:")
+	fmt.Fprintf(w, "  min %v  p50 %v  p90 %v  p99 %v  max %v\n",
+		r.Latencies[0], r.Percentile(50), r.Percentile(90), r.Percentile(99), r.Latencies[len(r.Latencies)-1])
+
+	fmt.Fprintln(w, "\nHistogram:")
+	buckets := r.Histogram()
+
+	var most int
+	for _, b := range buckets {
+		most = max(most, b.Count)
+	}
+	for _, b := range buckets {
+		bar := strings.Repeat("#", b.Count*40/most)
+		fmt.Fprintf(w, "  <= %-8v %6d %s\n", b.Max, b.Count, bar)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/search"
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/service"
+)
+
+const (
+	succeed = "\u2713"
+	failed  = "\u2717"
+)
+
+// stubSearcher returns a result for every term after a short delay.
+type stubSearcher struct{}
+
+func (stubSearcher) Search(ctx context.Context, uid string, term string) ([]search.Result, error) {
+	time.Sleep(time.Millisecond)
+	return []search.Result{{Engine: "Stub", Title: term}}, nil
+}
+
+func init() {
+	search.MustRegister(search.Provider{Name: "stub", Title: "Stub", New: func(search.Provider) search.Searcher {
+		return stubSearcher{}
+	}})
+}
+
+// TestParseMix validates the mix of terms is parsed.
+func TestParseMix(t *testing.T) {
+	tt := []struct {
+		mix string
+		exp []Term
+		err bool
+	}{
+		{"trump:5, climate change:2,go", []Term{{"trump", 5}, {"climate change", 2}, {"go", 1}}, false},
+		{"go:0", nil, true},
+		{"go:x", nil, true},
+		{":3", nil, true},
+		{" , ", nil, true},
+	}
+
+	t.Log("Given the need to parse a mix of search terms.")
+	{
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen parsing %q.", i, tst.mix)
+			{
+				mix, err := ParseMix(tst.mix)
+				if (err != nil) != tst.err || !reflect.DeepEqual(mix, tst.exp) {
+					t.Fatalf("\t%s\tTest %d:\tShould get %v : got %v, %v", failed, i, tst.exp, mix, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get %v.", succeed, i, tst.exp)
+			}
+		}
+	}
+}
+
+// TestRun validates load is generated against the service and reported.
+func TestRun(t *testing.T) {
+	srv := httptest.NewServer(service.New(service.Config{}))
+	defer srv.Close()
+
+	cfg := Config{
+		URL:         srv.URL,
+		Mix:         []Term{{"go", 3}, {"gophers", 1}},
+		Engines:     "stub",
+		Concurrency: 8,
+		Requests:    400,
+		Seed:        1,
+		Client:      srv.Client(),
+	}
+
+	t.Log("Given the need to generate load against the service.")
+	{
+		t.Log("\tTest 0:\tWhen making a number of requests.")
+		{
+			r, err := Run(context.Background(), cfg)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to run : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould be able to run.", succeed)
+
+			if r.Requests != 400 || r.Errors != 0 || r.Statuses[200] != 400 {
+				t.Fatalf("\t%s\tTest 0:\tShould make every request : %d requests, %d errors", failed, r.Requests, r.Errors)
+			}
+			t.Logf("\t%s\tTest 0:\tShould make every request.", succeed)
+
+			if go3 := r.Terms["go"]; go3 < 250 || go3 > 350 {
+				t.Fatalf("\t%s\tTest 0:\tShould search the terms in proportion to their weights : %v", failed, r.Terms)
+			}
+			t.Logf("\t%s\tTest 0:\tShould search the terms in proportion to their weights.", succeed)
+
+			p50, p90, p99 := r.Percentile(50), r.Percentile(90), r.Percentile(99)
+			if p50 <= 0 || p50 > p90 || p90 > p99 {
+				t.Fatalf("\t%s\tTest 0:\tShould calculate the percentiles : %v %v %v", failed, p50, p90, p99)
+			}
+			t.Logf("\t%s\tTest 0:\tShould calculate the percentiles.", succeed)
+
+			var total int
+			for _, b := range r.Histogram() {
+				total += b.Count
+			}
+			if total != r.Requests {
+				t.Fatalf("\t%s\tTest 0:\tShould put every request in the histogram : %d", failed, total)
+			}
+			t.Logf("\t%s\tTest 0:\tShould put every request in the histogram.", succeed)
+
+			var buf bytes.Buffer
+			r.Print(&buf)
+			if !strings.Contains(buf.String(), "p99") || !strings.Contains(buf.String(), "200 OK") {
+				t.Fatalf("\t%s\tTest 0:\tShould print the report :\n%s", failed, buf.String())
+			}
+			t.Logf("\t%s\tTest 0:\tShould print the report.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen running for a duration.")
+		{
+			cfg.Requests = 0
+			cfg.Duration = 200 * time.Millisecond
+
+			start := time.Now()
+			r, err := Run(context.Background(), cfg)
+			if err != nil || r.Requests == 0 || r.Errors != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould make requests until the time is up : %d requests, %d errors, %v", failed, r.Requests, r.Errors, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould make requests until the time is up.", succeed)
+
+			if d := time.Since(start); d > time.Second {
+				t.Fatalf("\t%s\tTest 1:\tShould stop once the time is up : took %v", failed, d)
+			}
+			t.Logf("\t%s\tTest 1:\tShould stop once the time is up.", succeed)
+		}
+	}
+}
+
+// TestPercentile validates the percentiles of known latencies.
+func TestPercentile(t *testing.T) {
+	var r Report
+	for i := 1; i <= 100; i++ {
+		r.Latencies = append(r.Latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	tt := map[float64]time.Duration{50: 50 * time.Millisecond, 90: 90 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond}
+
+	t.Log("Given the need to report latency percentiles.")
+	{
+		for p, exp := range tt {
+			t.Logf("\tTest:\tWhen asking for p%v.", p)
+			{
+				if got := r.Percentile(p); got != exp {
+					t.Fatalf("\t%s\tShould get %v : got %v", failed, exp, got)
+				}
+				t.Logf("\t%s\tShould get %v.", succeed, exp)
+			}
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// This program generates load against the search service by replaying a
+// weighted mix of search terms against the JSON API, and reports the
+// latency of the requests.
+//
+// ./loadgen -url http://localhost:5000 -mix "trump:5,climate:2,go" -c 50 -d 30s
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+)
+
+//runexamples:skip needs the service to send requests to
+func main() {
+	var cfg Config
+	flag.StringVar(&cfg.URL, "url", "http://localhost:5000", "base URL of the search service")
+	mix := flag.String("mix", "trump:3,president:2,weather", "comma separated search terms with optional weights, as in term:weight")
+	flag.StringVar(&cfg.Engines, "engines", "", "comma separated providers to search, empty searches all of them")
+	flag.IntVar(&cfg.Concurrency, "c", 10, "number of requests in flight at once")
+	flag.IntVar(&cfg.Requests, "n", 0, "number of requests to make, 0 runs for the duration")
+	flag.DurationVar(&cfg.Duration, "d", 10*time.Second, "how long to run, 0 runs until the requests are made")
+	flag.Uint64Var(&cfg.Seed, "seed", uint64(time.Now().UnixNano()), "seed for picking terms")
+	flag.Parse()
+
+	var err error
+	if cfg.Mix, err = ParseMix(*mix); err != nil {
+		log.Fatalln(err)
+	}
+
+	// Stop early and still report when we are interrupted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("Searching %s with %d concurrent requests\n\n", cfg.URL, cfg.Concurrency)
+
+	r, err := Run(ctx, cfg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	r.Print(os.Stdout)
+}
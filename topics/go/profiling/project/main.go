@@ -6,14 +6,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"expvar"
+	"flag"
 	"log"
-	_ "net/http/pprof"
+	"log/slog"
 	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
 	"time"
 
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/search"
 	"github.com/ardanlabs/gotraining/topics/go/profiling/project/service"
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
 )
 
 // init is called before main. We are using init to
@@ -35,8 +42,135 @@ func expvars() {
 	}()
 }
 
-// main is the entry point for the application.
+// loadProviders registers the providers found in the YAML file.
+func loadProviders(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer f.Close()
+
+	if err := search.LoadProviders(f); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// Environment variables that provide the defaults for the flags.
+const (
+	envAddr            = "SEARCH_ADDR"
+	envAdminAddr       = "SEARCH_ADMIN_ADDR"
+	envProviders       = "SEARCH_PROVIDERS"
+	envReadTimeout     = "SEARCH_READ_TIMEOUT"
+	envWriteTimeout    = "SEARCH_WRITE_TIMEOUT"
+	envShutdownTimeout = "SEARCH_SHUTDOWN_TIMEOUT"
+	envTraceFile       = "SEARCH_TRACE_FILE"
+	envTraceEndpoint   = "SEARCH_TRACE_ENDPOINT"
+	envFixtures        = "SEARCH_FIXTURES"
+	envFixturesDir     = "SEARCH_FIXTURES_DIR"
+)
+
+// env returns the value of the environment variable or the default.
+func env(key string, def string) string {
+	if v, exists := os.LookupEnv(key); exists {
+		return v
+	}
+	return def
+}
+
+// envDuration returns the environment variable as a duration or the
+// default.
+func envDuration(key string, def time.Duration) time.Duration {
+	v, exists := os.LookupEnv(key)
+	if !exists {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("%s: %v", key, err)
+	}
+	return d
+}
+
+// newRecorder constructs the span recorder, exporting the spans to a file
+// and/or a collector when they are configured.
+func newRecorder(file string, endpoint string) *telemetry.Recorder {
+	var exporters multiExporter
+
+	if file != "" {
+		fe, err := telemetry.NewFileExporter(file)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		exporters = append(exporters, fe)
+	}
+
+	if endpoint != "" {
+		exporters = append(exporters, &telemetry.HTTPExporter{URL: endpoint})
+	}
+
+	if len(exporters) == 0 {
+		return telemetry.NewRecorder("search", nil)
+	}
+	return telemetry.NewRecorder("search", exporters)
+}
+
+// multiExporter sends the spans to every exporter.
+type multiExporter []telemetry.Exporter
+
+// Export implements the telemetry.Exporter interface.
+func (me multiExporter) Export(ctx context.Context, service string, spans []telemetry.SpanData) error {
+	var errs []error
+	for _, e := range me {
+		errs = append(errs, e.Export(ctx, service, spans))
+	}
+	return errors.Join(errs...)
+}
+
+//runexamples:skip listens for connections
+
+// main is the entry point for the application. Every setting can be
+// provided as a flag or an environment variable, with the flag taking
+// precedence.
 func main() {
+	var cfg service.Config
+	flag.StringVar(&cfg.Addr, "addr", env(envAddr, "0.0.0.0:5000"), "address to listen on ($"+envAddr+")")
+	flag.StringVar(&cfg.AdminAddr, "admin-addr", env(envAdminAddr, "localhost:6060"), "address for profiling and expvar, empty disables it ($"+envAdminAddr+")")
+	flag.DurationVar(&cfg.ReadTimeout, "read-timeout", envDuration(envReadTimeout, 10*time.Second), "maximum duration for reading a request ($"+envReadTimeout+")")
+	flag.DurationVar(&cfg.WriteTimeout, "write-timeout", envDuration(envWriteTimeout, 31*time.Second), "maximum duration for writing a response ($"+envWriteTimeout+")")
+	flag.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", envDuration(envShutdownTimeout, 60*time.Second), "time allowed for requests to finish on shutdown ($"+envShutdownTimeout+")")
+	providers := flag.String("providers", env(envProviders, ""), "YAML file of search providers to add or replace ($"+envProviders+")")
+	traceFile := flag.String("trace-file", env(envTraceFile, ""), "file to append spans to as OTLP JSON ($"+envTraceFile+")")
+	traceEndpoint := flag.String("trace-endpoint", env(envTraceEndpoint, ""), "OTLP/HTTP endpoint to send spans to, such as http://localhost:4318/v1/traces ($"+envTraceEndpoint+")")
+	fixtures := flag.String("fixtures", env(envFixtures, search.FixturesLive), "how feeds are fetched: live, record or replay ($"+envFixtures+")")
+	fixturesDir := flag.String("fixtures-dir", env(envFixturesDir, "fixtures"), "directory feeds are recorded to and replayed from ($"+envFixturesDir+")")
+	flag.Parse()
+
+	// Structured logs go to stdout with the rest of the logging.
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
+	if *providers != "" {
+		loadProviders(*providers)
+	}
+
+	if err := search.UseFixtures(*fixtures, *fixturesDir); err != nil {
+		log.Fatalln(err)
+	}
+
 	expvars()
-	service.Run()
+
+	cfg.Recorder = newRecorder(*traceFile, *traceEndpoint)
+
+	// Shutdown cleanly when we are interrupted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := service.Run(ctx, cfg)
+
+	// Export the spans that haven't been sent yet.
+	cfg.Recorder.Close()
+
+	if err != nil {
+		log.Fatalln(err)
+	}
 }
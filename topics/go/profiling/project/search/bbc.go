@@ -1,7 +1,5 @@
 package search
 
-import "log"
-
 var bbcFeeds = []string{
 	"http://feeds.bbci.co.uk/news/rss.xml",
 	"http://feeds.bbci.co.uk/news/world/rss.xml",
@@ -9,27 +7,14 @@ var bbcFeeds = []string{
 	"http://feeds.bbci.co.uk/news/world/us_and_canada/rss.xml",
 }
 
-// BBC provides support for BBC searches.
-type BBC struct{}
-
-// NewBBC returns a BBC Searcher value.
-func NewBBC() Searcher {
-	return BBC{}
+// init registers the BBC provider.
+func init() {
+	MustRegister(Provider{Name: "bbc", Title: "BBC", Feeds: bbcFeeds})
 }
 
-// Search performs a search against the CNN RSS feeds.
-func (BBC) Search(uid string, term string, found chan<- []Result) {
-	results := []Result{}
-
-	for _, feed := range bbcFeeds {
-		res, err := rssSearch(uid, term, "BBC", feed)
-		if err != nil {
-			log.Println("ERROR: ", err)
-			continue
-		}
-
-		results = append(results, res...)
-	}
-
-	found <- results
+// NewBBC returns a BBC Searcher value using the feeds currently
+// registered for the provider.
+func NewBBC() Searcher {
+	p, _ := Lookup("bbc")
+	return p.Searcher()
 }
@@ -1,7 +1,5 @@
 package search
 
-import "log"
-
 var cnnFeeds = []string{
 	"http://rss.cnn.com/rss/cnn_topstories.rss",
 	"http://rss.cnn.com/rss/cnn_world.rss",
@@ -9,27 +7,14 @@ var cnnFeeds = []string{
 	"http://rss.cnn.com/rss/cnn_allpolitics.rss",
 }
 
-// CNN provides support for CNN searches.
-type CNN struct{}
-
-// NewCNN returns a CNN Searcher value.
-func NewCNN() Searcher {
-	return CNN{}
+// init registers the CNN provider.
+func init() {
+	MustRegister(Provider{Name: "cnn", Title: "CNN", Feeds: cnnFeeds})
 }
 
-// Search performs a search against the CNN RSS feeds.
-func (CNN) Search(uid string, term string, found chan<- []Result) {
-	results := []Result{}
-
-	for _, feed := range cnnFeeds {
-		res, err := rssSearch(uid, term, "CNN", feed)
-		if err != nil {
-			log.Println("ERROR: ", err)
-			continue
-		}
-
-		results = append(results, res...)
-	}
-
-	found <- results
+// NewCNN returns a CNN Searcher value using the feeds currently
+// registered for the provider.
+func NewCNN() Searcher {
+	p, _ := Lookup("cnn")
+	return p.Searcher()
 }
@@ -0,0 +1,327 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// Item is the common model for an entry in a feed, regardless of the format
+// the feed was published in. The Title and Description are always HTML.
+type Item struct {
+	Title       string
+	Link        string
+	Description string
+	Published   time.Time
+}
+
+// Feed is the common model for a feed document.
+type Feed struct {
+	Format string
+	Title  string
+	Items  []Item
+}
+
+// Set of feed formats that can be decoded.
+const (
+	FormatRSS      = "rss"
+	FormatAtom     = "atom"
+	FormatJSONFeed = "jsonfeed"
+)
+
+// ErrUnknownFormat is returned when a document is not in a supported format.
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+// decodeFeed detects the format of the document and decodes it.
+func decodeFeed(data []byte) (Feed, error) {
+	format, err := detectFormat(data)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	switch format {
+	case FormatAtom:
+		return decodeAtom(data)
+	case FormatJSONFeed:
+		return decodeJSONFeed(data)
+	}
+	return decodeRSS(data)
+}
+
+// detectFormat looks at the start of the document to determine its format.
+// JSON documents start with an object and XML documents are identified by
+// their root element.
+func detectFormat(data []byte) (string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.TrimSpace(data)
+
+	if len(data) == 0 {
+		return "", fmt.Errorf("%w: empty document", ErrUnknownFormat)
+	}
+
+	if data[0] == '{' {
+		return FormatJSONFeed, nil
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+		}
+
+		if se, ok := tok.(xml.StartElement); ok {
+			switch se.Name.Local {
+			case "rss":
+				return FormatRSS, nil
+			case "feed":
+				return FormatAtom, nil
+			}
+			return "", fmt.Errorf("%w: root element <%s>", ErrUnknownFormat, se.Name.Local)
+		}
+	}
+}
+
+// timeLayouts are the date formats seen in feeds. RSS uses RFC 822 dates
+// with a number of variations, Atom and JSON Feed use RFC 3339.
+var timeLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+}
+
+// parseTime parses a feed date, returning the zero time if the date is not
+// in a known format.
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// =============================================================================
+
+type (
+
+	// rssItem defines the fields associated with the item tag in the RSS document.
+	rssItem struct {
+		PubDate     string `xml:"pubDate"`
+		Title       string `xml:"title"`
+		Description string `xml:"description"`
+		Link        string `xml:"link"`
+	}
+
+	// rssChannel defines the fields associated with the channel tag in the RSS document.
+	rssChannel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	}
+
+	// rssDocument defines the fields associated with the RSS document.
+	rssDocument struct {
+		XMLName xml.Name   `xml:"rss"`
+		Channel rssChannel `xml:"channel"`
+	}
+)
+
+// decodeRSS decodes an RSS 2.0 document. The description of an item is HTML
+// that is either escaped or wrapped in a CDATA section, both of which the
+// XML decoder returns as the unescaped HTML.
+func decodeRSS(data []byte) (Feed, error) {
+	var d rssDocument
+	if err := xml.Unmarshal(data, &d); err != nil {
+		return Feed{}, err
+	}
+
+	feed := Feed{
+		Format: FormatRSS,
+		Title:  d.Channel.Title,
+		Items:  make([]Item, len(d.Channel.Items)),
+	}
+
+	for i, item := range d.Channel.Items {
+		feed.Items[i] = Item{
+			Title:       strings.TrimSpace(item.Title),
+			Link:        strings.TrimSpace(item.Link),
+			Description: strings.TrimSpace(item.Description),
+			Published:   parseTime(item.PubDate),
+		}
+	}
+
+	return feed, nil
+}
+
+// =============================================================================
+
+type (
+
+	// atomText defines an Atom text construct which can be plain text,
+	// escaped HTML or inline XHTML.
+	atomText struct {
+		Type  string `xml:"type,attr"`
+		Body  string `xml:",chardata"`
+		Inner string `xml:",innerxml"`
+	}
+
+	// atomLink defines the fields associated with the link tag.
+	atomLink struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	}
+
+	// atomEntry defines the fields associated with the entry tag.
+	atomEntry struct {
+		Title     atomText   `xml:"title"`
+		Links     []atomLink `xml:"link"`
+		Summary   atomText   `xml:"summary"`
+		Content   atomText   `xml:"content"`
+		Published string     `xml:"published"`
+		Updated   string     `xml:"updated"`
+	}
+
+	// atomFeed defines the fields associated with the Atom document.
+	atomFeed struct {
+		XMLName xml.Name    `xml:"feed"`
+		Title   atomText    `xml:"title"`
+		Entries []atomEntry `xml:"entry"`
+	}
+)
+
+// html returns the text construct as HTML.
+func (t atomText) html() string {
+	switch t.Type {
+	case "html":
+		return strings.TrimSpace(t.Body)
+	case "xhtml":
+		return strings.TrimSpace(t.Inner)
+	}
+	return html.EscapeString(strings.TrimSpace(t.Body))
+}
+
+// decodeAtom decodes an Atom 1.0 document.
+func decodeAtom(data []byte) (Feed, error) {
+	var d atomFeed
+	if err := xml.Unmarshal(data, &d); err != nil {
+		return Feed{}, err
+	}
+
+	feed := Feed{
+		Format: FormatAtom,
+		Title:  d.Title.html(),
+		Items:  make([]Item, len(d.Entries)),
+	}
+
+	for i, e := range d.Entries {
+		desc := e.Summary
+		if strings.TrimSpace(desc.Body) == "" && strings.TrimSpace(desc.Inner) == "" {
+			desc = e.Content
+		}
+
+		published := e.Published
+		if published == "" {
+			published = e.Updated
+		}
+
+		feed.Items[i] = Item{
+			Title:       e.Title.html(),
+			Link:        atomAlternate(e.Links),
+			Description: desc.html(),
+			Published:   parseTime(published),
+		}
+	}
+
+	return feed, nil
+}
+
+// atomAlternate returns the link to the entry itself.
+func atomAlternate(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// =============================================================================
+
+type (
+
+	// jsonItem defines the fields associated with a JSON Feed item.
+	jsonItem struct {
+		ID            string `json:"id"`
+		URL           string `json:"url"`
+		Title         string `json:"title"`
+		ContentHTML   string `json:"content_html"`
+		ContentText   string `json:"content_text"`
+		Summary       string `json:"summary"`
+		DatePublished string `json:"date_published"`
+	}
+
+	// jsonFeed defines the fields associated with a JSON Feed document.
+	jsonFeed struct {
+		Version string     `json:"version"`
+		Title   string     `json:"title"`
+		Items   []jsonItem `json:"items"`
+	}
+)
+
+// decodeJSONFeed decodes a JSON Feed 1.0 or 1.1 document.
+func decodeJSONFeed(data []byte) (Feed, error) {
+	var d jsonFeed
+	if err := json.Unmarshal(data, &d); err != nil {
+		return Feed{}, err
+	}
+
+	if !strings.HasPrefix(d.Version, "https://jsonfeed.org/version/") {
+		return Feed{}, fmt.Errorf("%w: JSON document is not a JSON Feed", ErrUnknownFormat)
+	}
+
+	feed := Feed{
+		Format: FormatJSONFeed,
+		Title:  d.Title,
+		Items:  make([]Item, len(d.Items)),
+	}
+
+	for i, item := range d.Items {
+
+		// JSON Feed titles and summaries are plain text, the content
+		// may be provided as HTML or plain text.
+		var desc string
+		switch {
+		case item.ContentHTML != "":
+			desc = item.ContentHTML
+		case item.Summary != "":
+			desc = html.EscapeString(item.Summary)
+		default:
+			desc = html.EscapeString(item.ContentText)
+		}
+
+		link := item.URL
+		if link == "" && strings.HasPrefix(item.ID, "http") {
+			link = item.ID
+		}
+
+		feed.Items[i] = Item{
+			Title:       html.EscapeString(item.Title),
+			Link:        link,
+			Description: strings.TrimSpace(desc),
+			Published:   parseTime(item.DatePublished),
+		}
+	}
+
+	return feed, nil
+}
@@ -0,0 +1,147 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// TestLoadFeed validates each feed format is detected and decoded into the
+// common model when served over HTTP.
+func TestLoadFeed(t *testing.T) {
+	srv := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer srv.Close()
+
+	fc := newTestCache(srv, clock.Real())
+
+	tt := []struct {
+		name   string
+		file   string
+		format string
+		title  string
+		items  []Item
+	}{
+		{
+			name:   "RSS",
+			file:   "rss.xml",
+			format: FormatRSS,
+			title:  "Test RSS",
+			items: []Item{
+				{"Gophers win the cup", "https://example.com/rss/1", "<p>The <b>gophers</b> won.</p>", time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)},
+				{"Escaped description", "https://example.com/rss/2", "<p>Escaped &amp; gophers</p>", time.Date(2006, 1, 3, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:   "Atom",
+			file:   "atom.xml",
+			format: FormatAtom,
+			title:  "Test Atom",
+			items: []Item{
+				{"Gophers &amp; friends", "https://example.com/atom/1", "<p>The <b>gophers</b> won.</p>", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+				{"Inline XHTML", "https://example.com/atom/2", `<div xmlns="http://www.w3.org/1999/xhtml"><p>XHTML gophers</p></div>`, time.Date(2006, 1, 3, 8, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name:   "JSON Feed",
+			file:   "feed.json",
+			format: FormatJSONFeed,
+			title:  "Test JSON Feed",
+			items: []Item{
+				{"Gophers win the cup", "https://example.com/json/1", "<p>The <b>gophers</b> won.</p>", time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)},
+				{"Plain text", "https://example.com/json/2", "Gophers &lt;3 text", time.Date(2006, 1, 3, 10, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	t.Log("Given the need to decode feeds in different formats.")
+	{
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen serving a %s feed.", i, tst.name)
+			{
+				feed, err := fc.load(context.Background(), srv.URL+"/"+tst.file)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to load the feed : %v", failed, i, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to load the feed.", succeed, i)
+
+				if feed.Format != tst.format || feed.Title != tst.title {
+					t.Fatalf("\t%s\tTest %d:\tShould detect the format %q : got %q %q", failed, i, tst.format, feed.Format, feed.Title)
+				}
+				t.Logf("\t%s\tTest %d:\tShould detect the format %q.", succeed, i, tst.format)
+
+				if len(feed.Items) != len(tst.items) {
+					t.Fatalf("\t%s\tTest %d:\tShould decode %d items : got %d", failed, i, len(tst.items), len(feed.Items))
+				}
+
+				for j, exp := range tst.items {
+					got := feed.Items[j]
+					if got.Title != exp.Title || got.Link != exp.Link || got.Description != exp.Description {
+						t.Fatalf("\t%s\tTest %d:\tShould decode item %d : got %+v, exp %+v", failed, i, j, got, exp)
+					}
+					if !got.Published.Equal(exp.Published) {
+						t.Fatalf("\t%s\tTest %d:\tShould parse the published time of item %d : got %v, exp %v", failed, i, j, got.Published, exp.Published)
+					}
+				}
+				t.Logf("\t%s\tTest %d:\tShould decode every item.", succeed, i)
+			}
+		}
+	}
+}
+
+// TestLoadFeedMalformed validates malformed documents are reported as
+// errors and never loaded into the cache.
+func TestLoadFeedMalformed(t *testing.T) {
+	srv := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer srv.Close()
+
+	fc := newTestCache(srv, clock.Real())
+
+	files := []string{"rss_malformed.xml", "atom_malformed.xml", "feed_malformed.json"}
+
+	t.Log("Given the need to reject malformed feeds.")
+	{
+		for i, file := range files {
+			t.Logf("\tTest %d:\tWhen serving %s.", i, file)
+			{
+				uri := srv.URL + "/" + file
+				if _, err := fc.load(context.Background(), uri); err == nil {
+					t.Fatalf("\t%s\tTest %d:\tShould receive an error.", failed, i)
+				}
+				t.Logf("\t%s\tTest %d:\tShould receive an error.", succeed, i)
+
+				if e := fc.entries[uri]; e == nil || e.loaded {
+					t.Fatalf("\t%s\tTest %d:\tShould not cache the document.", failed, i)
+				}
+				t.Logf("\t%s\tTest %d:\tShould not cache the document.", succeed, i)
+			}
+		}
+	}
+}
+
+// TestDetectFormat validates documents in unsupported formats are rejected.
+func TestDetectFormat(t *testing.T) {
+	docs := []string{
+		"",
+		"<html><body>not a feed</body></html>",
+		`{"version": "1", "items": []}`,
+	}
+
+	t.Log("Given the need to detect the format of a document.")
+	{
+		for i, doc := range docs {
+			t.Logf("\tTest %d:\tWhen decoding %q.", i, doc)
+			{
+				_, err := decodeFeed([]byte(doc))
+				if !errors.Is(err, ErrUnknownFormat) {
+					t.Fatalf("\t%s\tTest %d:\tShould receive ErrUnknownFormat : %v", failed, i, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould receive ErrUnknownFormat.", succeed, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package search
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// Settings for the feed cache. A feed is fresh for the TTL, after which it's
+// still served while it's revalidated in the background. A feed that fails
+// to load isn't requested again until its backoff has passed, which doubles
+// with every failure up to the maximum.
+const (
+	feedTTL      = 15 * time.Minute
+	fetchTimeout = 30 * time.Second
+	minBackoff   = 5 * time.Second
+	maxBackoff   = 5 * time.Minute
+)
+
+// maxFeedSize is the largest feed document that will be read.
+const maxFeedSize = 10 << 20
+
+// feedEntry is the cached state of a feed.
+type feedEntry struct {
+	feed       Feed
+	loaded     bool       // The feed has been successfully loaded at least once.
+	v          validators // Sent with the next request to make it conditional.
+	validated  time.Time  // When the feed was last known to be current.
+	err        error      // The last fetch error.
+	failures   int        // Fetches that have failed in a row.
+	retryAt    time.Time  // No fetches are made before this time.
+	refreshing bool       // A background revalidation is running.
+}
+
+// fetchCall is a fetch in progress that any number of callers can wait on.
+type fetchCall struct {
+	done chan struct{}
+	feed Feed
+	err  error
+}
+
+// feedCache caches feeds by URI. Concurrent requests for a feed that isn't
+// cached are coalesced into a single fetch, and a stale feed is served
+// while it's revalidated with a conditional request.
+type feedCache struct {
+	client *http.Client
+	clock  clock.Clock
+	ttl    time.Duration
+	stats  *expvar.Map
+	onLoad func(uri string, feed Feed) // Called when new feed content is loaded.
+
+	mu      sync.Mutex
+	entries map[string]*feedEntry
+	calls   map[string]*fetchCall
+}
+
+// newFeedCache constructs a feed cache for use. The stats are updated with
+// the number of hits, misses, revalidations and fetch errors.
+func newFeedCache(client *http.Client, clk clock.Clock, ttl time.Duration, stats *expvar.Map) *feedCache {
+	return &feedCache{
+		client:  client,
+		clock:   clk,
+		ttl:     ttl,
+		stats:   stats,
+		onLoad:  func(string, Feed) {},
+		entries: make(map[string]*feedEntry),
+		calls:   make(map[string]*fetchCall),
+	}
+}
+
+// feeds caches the feeds for the searchers, indexing every feed as new
+// content is loaded. The stats are published as "feeds".
+var feeds = func() *feedCache {
+	fc := newFeedCache(http.DefaultClient, clock.Real(), feedTTL, expvar.NewMap("feeds"))
+	fc.onLoad = func(uri string, feed Feed) {
+		idx.update(uri, feed.Items)
+	}
+	return fc
+}()
+
+// load returns the feed for the URI. A fresh feed is returned from the
+// cache. A stale feed is also returned from the cache, but a revalidation
+// is started in the background. Otherwise the caller waits for the feed to
+// be fetched, or for the context to be cancelled.
+func (fc *feedCache) load(ctx context.Context, uri string) (Feed, error) {
+	fc.mu.Lock()
+
+	now := fc.clock.Now()
+	e, found := fc.entries[uri]
+
+	switch {
+	case found && e.loaded:
+		fc.stats.Add("hits", 1)
+
+		stale := now.Sub(e.validated) >= fc.ttl
+		if stale && !e.refreshing && !now.Before(e.retryAt) {
+			e.refreshing = true
+			fc.stats.Add("revalidations", 1)
+			go fc.fetch(uri, e.v)
+		}
+
+		feed := e.feed
+		fc.mu.Unlock()
+		return feed, nil
+
+	case found && now.Before(e.retryAt):
+
+		// The feed failed to load and is backing off.
+		err := e.err
+		fc.mu.Unlock()
+		return Feed{}, err
+	}
+
+	fc.stats.Add("misses", 1)
+
+	// Join the fetch in progress or start a new one.
+	c, found := fc.calls[uri]
+	if !found {
+		c = fc.start(uri)
+	}
+	fc.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.feed, c.err
+	case <-ctx.Done():
+		return Feed{}, ctx.Err()
+	}
+}
+
+// start begins a fetch for the URI that callers can wait on. The caller
+// must hold the lock.
+func (fc *feedCache) start(uri string) *fetchCall {
+	c := fetchCall{
+		done: make(chan struct{}),
+	}
+	fc.calls[uri] = &c
+
+	go func() {
+		c.feed, c.err = fc.fetch(uri, validators{})
+
+		fc.mu.Lock()
+		delete(fc.calls, uri)
+		fc.mu.Unlock()
+
+		close(c.done)
+	}()
+
+	return &c
+}
+
+// fetch downloads the feed and updates the cache. The fetch isn't tied to
+// any caller's context so one caller giving up doesn't fail the others.
+// When validators are provided the request is conditional.
+func (fc *feedCache) fetch(uri string, v validators) (Feed, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	feed, modified, v, err := fc.download(ctx, uri, v)
+	if err == nil && modified {
+		fc.onLoad(uri, feed)
+	}
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	e, found := fc.entries[uri]
+	if !found {
+		e = &feedEntry{}
+		fc.entries[uri] = e
+	}
+	e.refreshing = false
+
+	now := fc.clock.Now()
+
+	if err != nil {
+		fc.stats.Add("fetch_errors", 1)
+		slog.Warn("fetching feed", "feed", uri, "error", err)
+
+		e.err = err
+		e.failures++
+		e.retryAt = now.Add(backoff(e.failures))
+
+		// Keep serving the feed we have.
+		if e.loaded {
+			return e.feed, nil
+		}
+		return Feed{}, err
+	}
+
+	e.err = nil
+	e.failures = 0
+	e.retryAt = time.Time{}
+	e.validated = now
+	e.v = v
+
+	if modified {
+		e.feed = feed
+		e.loaded = true
+		slog.Info("reloaded feed", "feed", uri)
+	}
+
+	return e.feed, nil
+}
+
+// validators are the response headers used to make a conditional request.
+type validators struct {
+	etag         string
+	lastModified string
+}
+
+// download requests the feed. If the server reports the feed hasn't been
+// modified since the validators were issued, modified is false.
+func (fc *feedCache) download(ctx context.Context, uri string, v validators) (Feed, bool, validators, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return Feed{}, false, validators{}, err
+	}
+
+	if v.etag != "" {
+		req.Header.Set("If-None-Match", v.etag)
+	}
+	if v.lastModified != "" {
+		req.Header.Set("If-Modified-Since", v.lastModified)
+	}
+
+	resp, err := fc.client.Do(req)
+	if err != nil {
+		return Feed{}, false, validators{}, err
+	}
+
+	// Schedule the close of the response body.
+	defer resp.Body.Close()
+
+	// Servers may leave out the validators on a 304, in which case the
+	// ones we sent are still current.
+	if etag := resp.Header.Get("ETag"); etag != "" || resp.StatusCode == http.StatusOK {
+		v.etag = etag
+	}
+	if lm := resp.Header.Get("Last-Modified"); lm != "" || resp.StatusCode == http.StatusOK {
+		v.lastModified = lm
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return Feed{}, false, v, nil
+	default:
+		return Feed{}, false, validators{}, fmt.Errorf("fetching %s: %s", uri, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return Feed{}, false, validators{}, fmt.Errorf("reading %s: %w", uri, err)
+	}
+
+	// Decode the document into a feed.
+	feed, err := decodeFeed(data)
+	if err != nil {
+		return Feed{}, false, validators{}, fmt.Errorf("decoding %s: %w", uri, err)
+	}
+
+	return feed, true, v, nil
+}
+
+// backoff returns how long to wait before fetching a feed again after the
+// number of failures.
+func backoff(failures int) time.Duration {
+	d := minBackoff
+	for i := 1; i < failures && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
@@ -0,0 +1,301 @@
+package search
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/clock"
+)
+
+// newTestCache constructs a feed cache with its own stats that uses the
+// test server's client.
+func newTestCache(srv *httptest.Server, clk clock.Clock) *feedCache {
+	return newFeedCache(srv.Client(), clk, time.Minute, new(expvar.Map).Init())
+}
+
+// feedServer serves a single RSS feed with an ETag and counts the requests
+// it receives.
+type feedServer struct {
+	mu          sync.Mutex
+	requests    int
+	conditional int // Requests that were answered with a 304.
+	etag        string
+	title       string
+	status      int
+	block       chan struct{} // When set, requests wait for it to close.
+}
+
+func (fs *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fs.mu.Lock()
+	fs.requests++
+	block := fs.block
+	etag, title, status := fs.etag, fs.title, fs.status
+	fs.mu.Unlock()
+
+	if block != nil {
+		<-block
+	}
+
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	if etag != "" && r.Header.Get("If-None-Match") == etag {
+		fs.mu.Lock()
+		fs.conditional++
+		fs.mu.Unlock()
+
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Write([]byte(`<rss><channel><title>` + title + `</title><item><title>Gophers</title></item></channel></rss>`))
+}
+
+// set changes the state of the server.
+func (fs *feedServer) set(f func(fs *feedServer)) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	f(fs)
+}
+
+// counts returns the number of requests and conditional requests.
+func (fs *feedServer) counts() (int, int) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.requests, fs.conditional
+}
+
+// waitFor polls until the condition is true or fails the test.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for range 500 {
+		if cond() {
+			return
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	t.Fatalf("\t%s\tTimed out waiting for %s.", failed, what)
+}
+
+// idle reports if the feed has no fetch or revalidation running.
+func (fc *feedCache) idle(uri string) bool {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	e := fc.entries[uri]
+	return e != nil && !e.refreshing && fc.calls[uri] == nil
+}
+
+// stat returns the value of a counter.
+func (fc *feedCache) stat(key string) int64 {
+	if v, ok := fc.stats.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+// TestFeedCache validates requests are coalesced and stale feeds are served
+// while they are revalidated.
+func TestFeedCache(t *testing.T) {
+	fs := feedServer{etag: `"v1"`, title: "v1", block: make(chan struct{})}
+	srv := httptest.NewServer(&fs)
+	defer srv.Close()
+
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	fc := newTestCache(srv, clk)
+
+	var loads []string
+	fc.onLoad = func(uri string, feed Feed) { loads = append(loads, feed.Title) }
+
+	uri := srv.URL + "/feed"
+	ctx := context.Background()
+
+	t.Log("Given the need to cache feeds.")
+	{
+		t.Log("\tTest 0:\tWhen many searchers miss the cache at once.")
+		{
+			const searchers = 10
+
+			var wg sync.WaitGroup
+			titles := make([]string, searchers)
+			for i := range searchers {
+				wg.Go(func() {
+					feed, err := fc.load(ctx, uri)
+					if err != nil {
+						t.Errorf("\t%s\tTest 0:\tShould load the feed : %v", failed, err)
+					}
+					titles[i] = feed.Title
+				})
+			}
+
+			waitFor(t, "every searcher to miss", func() bool { return fc.stat("misses") == searchers })
+			close(fs.block)
+			fs.set(func(fs *feedServer) { fs.block = nil })
+			wg.Wait()
+
+			if n, _ := fs.counts(); n != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould make one request : made %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 0:\tShould make one request.", succeed)
+
+			for _, title := range titles {
+				if title != "v1" {
+					t.Fatalf("\t%s\tTest 0:\tShould give every searcher the feed : got %q", failed, title)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould give every searcher the feed.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the feed is fresh.")
+		{
+			feed, err := fc.load(ctx, uri)
+			if n, _ := fs.counts(); err != nil || feed.Title != "v1" || n != 1 {
+				t.Fatalf("\t%s\tTest 1:\tShould serve the feed from the cache : %v, %d requests", failed, err, n)
+			}
+			t.Logf("\t%s\tTest 1:\tShould serve the feed from the cache.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen the feed is stale and hasn't changed.")
+		{
+			clk.Advance(time.Minute)
+
+			feed, err := fc.load(ctx, uri)
+			if err != nil || feed.Title != "v1" {
+				t.Fatalf("\t%s\tTest 2:\tShould serve the stale feed : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 2:\tShould serve the stale feed.", succeed)
+
+			waitFor(t, "the revalidation", func() bool { return fc.idle(uri) })
+
+			if n, c := fs.counts(); n != 2 || c != 1 {
+				t.Fatalf("\t%s\tTest 2:\tShould make a conditional request : %d requests, %d not modified", failed, n, c)
+			}
+			t.Logf("\t%s\tTest 2:\tShould make a conditional request.", succeed)
+
+			fc.load(ctx, uri)
+			if n, _ := fs.counts(); n != 2 {
+				t.Fatalf("\t%s\tTest 2:\tShould treat the feed as fresh again : %d requests", failed, n)
+			}
+			t.Logf("\t%s\tTest 2:\tShould treat the feed as fresh again.", succeed)
+		}
+
+		t.Log("\tTest 3:\tWhen the feed is stale and has changed.")
+		{
+			fs.set(func(fs *feedServer) { fs.etag, fs.title = `"v2"`, "v2" })
+			clk.Advance(time.Minute)
+
+			if feed, _ := fc.load(ctx, uri); feed.Title != "v1" {
+				t.Fatalf("\t%s\tTest 3:\tShould serve the stale feed : got %q", failed, feed.Title)
+			}
+			t.Logf("\t%s\tTest 3:\tShould serve the stale feed.", succeed)
+
+			waitFor(t, "the revalidation", func() bool { return fc.idle(uri) })
+
+			if feed, _ := fc.load(ctx, uri); feed.Title != "v2" {
+				t.Fatalf("\t%s\tTest 3:\tShould serve the new feed once revalidated : got %q", failed, feed.Title)
+			}
+			t.Logf("\t%s\tTest 3:\tShould serve the new feed once revalidated.", succeed)
+
+			if len(loads) != 2 || loads[1] != "v2" {
+				t.Fatalf("\t%s\tTest 3:\tShould only index new content : %q", failed, loads)
+			}
+			t.Logf("\t%s\tTest 3:\tShould only index new content.", succeed)
+		}
+
+		t.Log("\tTest 4:\tWhen the feed is stale and the server fails.")
+		{
+			fs.set(func(fs *feedServer) { fs.status = http.StatusInternalServerError })
+			clk.Advance(time.Minute)
+
+			fc.load(ctx, uri)
+			waitFor(t, "the revalidation", func() bool { return fc.idle(uri) })
+
+			if feed, err := fc.load(ctx, uri); err != nil || feed.Title != "v2" {
+				t.Fatalf("\t%s\tTest 4:\tShould keep serving the stale feed : %q, %v", failed, feed.Title, err)
+			}
+			t.Logf("\t%s\tTest 4:\tShould keep serving the stale feed.", succeed)
+
+			if n, _ := fs.counts(); n != 4 {
+				t.Fatalf("\t%s\tTest 4:\tShould not revalidate until the backoff passes : %d requests", failed, n)
+			}
+			t.Logf("\t%s\tTest 4:\tShould not revalidate until the backoff passes.", succeed)
+		}
+
+		t.Log("\tTest 5:\tWhen checking the stats.")
+		{
+			exp := map[string]int64{"misses": 10, "hits": 7, "revalidations": 3, "fetch_errors": 1}
+			for key, v := range exp {
+				if got := fc.stat(key); got != v {
+					t.Fatalf("\t%s\tTest 5:\tShould count %d %s : got %d", failed, v, key, got)
+				}
+			}
+			t.Logf("\t%s\tTest 5:\tShould count the hits, misses, revalidations and errors.", succeed)
+		}
+	}
+}
+
+// TestFeedCacheBackoff validates fetch errors are cached and retried with
+// a growing backoff.
+func TestFeedCacheBackoff(t *testing.T) {
+	fs := feedServer{status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(&fs)
+	defer srv.Close()
+
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	fc := newTestCache(srv, clk)
+
+	uri := srv.URL + "/feed"
+	ctx := context.Background()
+
+	t.Log("Given the need to back off from a failing feed.")
+	{
+		// The backoff doubles from 5s after every failure.
+		steps := []struct {
+			advance  time.Duration
+			requests int
+		}{
+			{0, 1},
+			{0, 1},
+			{4 * time.Second, 1},
+			{time.Second, 2},
+			{9 * time.Second, 2},
+			{time.Second, 3},
+			{20 * time.Second, 4},
+		}
+
+		for i, step := range steps {
+			t.Logf("\tTest %d:\tWhen loading the feed after %v.", i, step.advance)
+			{
+				clk.Advance(step.advance)
+
+				if _, err := fc.load(ctx, uri); err == nil {
+					t.Fatalf("\t%s\tTest %d:\tShould receive the error.", failed, i)
+				}
+				t.Logf("\t%s\tTest %d:\tShould receive the error.", succeed, i)
+
+				if n, _ := fs.counts(); n != step.requests {
+					t.Fatalf("\t%s\tTest %d:\tShould have made %d requests : made %d", failed, i, step.requests, n)
+				}
+				t.Logf("\t%s\tTest %d:\tShould have made %d requests.", succeed, i, step.requests)
+			}
+		}
+
+		t.Logf("\tTest %d:\tWhen the feed recovers.", len(steps))
+		{
+			fs.set(func(fs *feedServer) { fs.status, fs.title = 0, "back" })
+			clk.Advance(maxBackoff)
+
+			if feed, err := fc.load(ctx, uri); err != nil || feed.Title != "back" {
+				t.Fatalf("\t%s\tTest %d:\tShould load the feed : %v", failed, len(steps), err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould load the feed.", succeed, len(steps))
+		}
+	}
+}
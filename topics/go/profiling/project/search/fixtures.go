@@ -0,0 +1,158 @@
+package search
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Set of modes for fetching feeds. Live fetches every feed from the
+// network, record does the same but saves every feed under the fixtures
+// directory, and replay serves the feeds from the fixtures directory
+// without touching the network.
+const (
+	FixturesLive   = "live"
+	FixturesRecord = "record"
+	FixturesReplay = "replay"
+)
+
+// ErrNoFixture is returned in replay mode for a feed that wasn't recorded.
+var ErrNoFixture = errors.New("no fixture recorded")
+
+// UseFixtures switches how feeds are fetched. It must be called before any
+// searches are performed.
+func UseFixtures(mode string, dir string) error {
+	switch mode {
+	case "", FixturesLive:
+		feeds.client = http.DefaultClient
+		return nil
+	case FixturesRecord, FixturesReplay:
+	default:
+		return fmt.Errorf("unknown fixtures mode %q, want %s, %s or %s", mode, FixturesLive, FixturesRecord, FixturesReplay)
+	}
+
+	if dir == "" {
+		return errors.New("fixtures directory is required")
+	}
+	if mode == FixturesRecord {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	feeds.client = &http.Client{
+		Transport: &fixtureTransport{
+			mode: mode,
+			dir:  dir,
+			next: http.DefaultTransport,
+		},
+	}
+	return nil
+}
+
+// rgxUnsafe matches the characters that aren't kept in fixture names.
+var rgxUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FixturePath returns the file a feed is recorded in. The name is readable
+// but is made unique with a hash of the full URL.
+func FixturePath(dir string, uri string) string {
+	name := uri
+	if i := strings.Index(name, "://"); i != -1 {
+		name = name[i+3:]
+	}
+	name = strings.Trim(rgxUnsafe.ReplaceAllString(name, "_"), "_.")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+
+	sum := sha256.Sum256([]byte(uri))
+	return filepath.Join(dir, name+"-"+hex.EncodeToString(sum[:4])+".feed")
+}
+
+// fixtureTransport records feeds to, or replays them from, the fixtures
+// directory.
+type fixtureTransport struct {
+	mode string
+	dir  string
+	next http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (ft *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	uri := req.URL.String()
+	path := FixturePath(ft.dir, uri)
+
+	if ft.mode == FixturesReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				slog.Error("replaying feed", "feed", uri, "fixture", path, "error", ErrNoFixture)
+				return nil, fmt.Errorf("%w for %s: %s", ErrNoFixture, uri, path)
+			}
+			return nil, err
+		}
+
+		// Conditional requests are ignored, the fixture is always sent.
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{},
+			Body:          io.NopCloser(bytes.NewReader(data)),
+			ContentLength: int64(len(data)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := ft.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	// Read the feed so it can be saved and handed on.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", uri, err)
+	}
+
+	if err := writeFile(path, data); err != nil {
+		return nil, fmt.Errorf("recording %s: %w", uri, err)
+	}
+	slog.Info("recorded feed", "feed", uri, "fixture", path, "bytes", len(data))
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	return resp, nil
+}
+
+// writeFile replaces the file with the data, so a search replaying the
+// feed never reads a partly written file.
+func writeFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
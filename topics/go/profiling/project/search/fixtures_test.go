@@ -0,0 +1,82 @@
+package search
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// TestFixtures validates feeds are recorded to disk and replayed without
+// the network.
+func TestFixtures(t *testing.T) {
+	srv := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	uri := srv.URL + "/rss.xml"
+
+	want, err := os.ReadFile("testdata/rss.xml")
+	if err != nil {
+		t.Fatalf("\t%s\tShould be able to read the feed : %v", failed, err)
+	}
+
+	dir := t.TempDir()
+	client := func(mode string) *http.Client {
+		return &http.Client{Transport: &fixtureTransport{mode: mode, dir: dir, next: http.DefaultTransport}}
+	}
+
+	fetch := func(c *http.Client, uri string) ([]byte, error) {
+		resp, err := c.Get(uri)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		return io.ReadAll(resp.Body)
+	}
+
+	t.Log("Given the need to search feeds without the network.")
+	{
+		t.Log("\tTest 0:\tWhen recording a feed.")
+		{
+			got, err := fetch(client(FixturesRecord), uri)
+			if err != nil || !bytes.Equal(got, want) {
+				t.Fatalf("\t%s\tTest 0:\tShould pass the feed through : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould pass the feed through.", succeed)
+
+			saved, err := os.ReadFile(FixturePath(dir, uri))
+			if err != nil || !bytes.Equal(saved, want) {
+				t.Fatalf("\t%s\tTest 0:\tShould save the feed : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould save the feed.", succeed)
+		}
+
+		srv.Close()
+
+		t.Log("\tTest 1:\tWhen replaying a recorded feed.")
+		{
+			got, err := fetch(client(FixturesReplay), uri)
+			if err != nil || !bytes.Equal(got, want) {
+				t.Fatalf("\t%s\tTest 1:\tShould serve the feed from disk : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould serve the feed from disk.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen replaying a feed that wasn't recorded.")
+		{
+			if _, err := fetch(client(FixturesReplay), srv.URL+"/atom.xml"); !errors.Is(err, ErrNoFixture) {
+				t.Fatalf("\t%s\tTest 2:\tShould fail with ErrNoFixture : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 2:\tShould fail with ErrNoFixture.", succeed)
+		}
+
+		t.Log("\tTest 3:\tWhen asking for an unknown mode.")
+		{
+			if err := UseFixtures("rewind", dir); err == nil {
+				t.Fatalf("\t%s\tTest 3:\tShould be rejected.", failed)
+			}
+			t.Logf("\t%s\tTest 3:\tShould be rejected.", succeed)
+		}
+	}
+}
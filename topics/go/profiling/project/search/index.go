@@ -0,0 +1,288 @@
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BM25 tuning parameters. k1 controls how quickly repeating a term stops
+// adding to the score and b controls how much long documents are penalized.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// snippetWords is the number of words shown in a snippet.
+const snippetWords = 30
+
+// doc is an item that has been added to the index.
+type doc struct {
+	uri    string
+	item   Item
+	text   string   // Plain text of the description used for snippets.
+	terms  []string // Distinct terms in the document.
+	length int      // Number of terms in the title and description.
+}
+
+// hit is a document that matched a query.
+type hit struct {
+	item    Item
+	score   float64
+	snippet string
+}
+
+// index is an inverted index over the items of every feed that has been
+// loaded. Documents are scored using BM25 so results from different feeds
+// can be ranked against each other.
+type index struct {
+	mu       sync.RWMutex
+	nextID   int
+	docs     map[int]*doc
+	feeds    map[string][]int         // The documents for each feed.
+	postings map[string]map[int][]int // The positions of a term in each document.
+	totalLen int
+}
+
+// newIndex constructs an empty index.
+func newIndex() *index {
+	return &index{
+		docs:     make(map[int]*doc),
+		feeds:    make(map[string][]int),
+		postings: make(map[string]map[int][]int),
+	}
+}
+
+// idx indexes every feed that is loaded into the cache.
+var idx = newIndex()
+
+// update replaces the documents for the feed with the items.
+func (ix *index) update(uri string, items []Item) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(uri)
+
+	ids := make([]int, len(items))
+	for i, item := range items {
+		id := ix.nextID
+		ix.nextID++
+		ids[i] = id
+
+		d := doc{
+			uri:  uri,
+			item: item,
+			text: plainText(item.Description),
+		}
+
+		// The title and description are indexed as one field. A gap is
+		// left between them so a phrase can't match across the two.
+		title := terms(plainText(item.Title))
+		desc := terms(d.text)
+		for pos, t := range title {
+			d.terms = ix.add(d.terms, t, id, pos)
+		}
+		for pos, t := range desc {
+			d.terms = ix.add(d.terms, t, id, len(title)+1+pos)
+		}
+
+		d.length = len(title) + len(desc)
+		ix.docs[id] = &d
+		ix.totalLen += d.length
+	}
+
+	ix.feeds[uri] = ids
+}
+
+// add records the position of a term in a document, returning the
+// document's distinct terms with the term added if it's new.
+func (ix *index) add(distinct []string, term string, id int, pos int) []string {
+	p, exists := ix.postings[term]
+	if !exists {
+		p = make(map[int][]int)
+		ix.postings[term] = p
+	}
+
+	if _, found := p[id]; !found {
+		distinct = append(distinct, term)
+	}
+	p[id] = append(p[id], pos)
+
+	return distinct
+}
+
+// remove drops the documents for the feed. The caller must hold the lock.
+func (ix *index) remove(uri string) {
+	for _, id := range ix.feeds[uri] {
+		d := ix.docs[id]
+		ix.totalLen -= d.length
+		delete(ix.docs, id)
+
+		for _, t := range d.terms {
+			p := ix.postings[t]
+			delete(p, id)
+			if len(p) == 0 {
+				delete(ix.postings, t)
+			}
+		}
+	}
+	delete(ix.feeds, uri)
+}
+
+// search returns the documents from the feed that match the query, with
+// the best match first. A query with no terms, like an empty one, one with
+// only punctuation or one with only exclusions, matches every document it
+// doesn't exclude in the order of the feed, as an empty term always has.
+func (ix *index) search(q query, uri string) []hit {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var hits []hit
+	for _, id := range ix.feeds[uri] {
+		if !ix.matches(q, id) {
+			continue
+		}
+
+		d := ix.docs[id]
+		hits = append(hits, hit{
+			item:    d.item,
+			score:   ix.score(q, d, id),
+			snippet: snippet(d.text, q),
+		})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+
+	return hits
+}
+
+// matches reports if the document contains every term and phrase in the
+// query and none of the exclusions.
+func (ix *index) matches(q query, id int) bool {
+	for _, t := range q.terms {
+		if _, found := ix.postings[t][id]; !found {
+			return false
+		}
+	}
+
+	for _, p := range q.phrases {
+		if !ix.hasPhrase(p, id) {
+			return false
+		}
+	}
+
+	for _, p := range q.exclude {
+		if ix.hasPhrase(p, id) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasPhrase reports if the terms appear next to each other in the document.
+func (ix *index) hasPhrase(phrase []string, id int) bool {
+	for _, start := range ix.postings[phrase[0]][id] {
+		found := true
+		for i, t := range phrase[1:] {
+			if !contains(ix.postings[t][id], start+i+1) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports if the sorted positions include pos.
+func contains(positions []int, pos int) bool {
+	i := sort.SearchInts(positions, pos)
+	return i < len(positions) && positions[i] == pos
+}
+
+// score calculates the BM25 score of the document for the query.
+func (ix *index) score(q query, d *doc, id int) float64 {
+	n := float64(len(ix.docs))
+	avgLen := float64(ix.totalLen) / n
+
+	var score float64
+	for _, t := range q.terms {
+		p := ix.postings[t]
+		df := float64(len(p))
+		tf := float64(len(p[id]))
+
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(d.length)/avgLen))
+	}
+
+	return score
+}
+
+// =============================================================================
+
+// snippet returns a window of the text around the first word that matches
+// the query, as HTML with the matching words highlighted.
+func snippet(text string, q query) string {
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	want := make(map[string]bool, len(q.terms))
+	for _, t := range q.terms {
+		want[t] = true
+	}
+
+	// Center the window on the first match. The match may only be in the
+	// title, in which case the snippet is the start of the text.
+	first := -1
+	for i, tkn := range tokens {
+		if want[stem(tkn.term)] {
+			first = i
+			break
+		}
+	}
+
+	start := max(0, first-snippetWords/3)
+	end := min(len(tokens), start+snippetWords)
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("&hellip; ")
+	}
+
+	// Include any punctuation before the first or after the last word when
+	// the window reaches the edges of the text.
+	pos, last := tokens[start].start, tokens[end-1].end
+	if start == 0 {
+		pos = 0
+	}
+	if end == len(tokens) {
+		last = len(text)
+	}
+
+	for _, tkn := range tokens[start:end] {
+		if !want[stem(tkn.term)] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:tkn.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[tkn.start:tkn.end]))
+		b.WriteString("</mark>")
+		pos = tkn.end
+	}
+	b.WriteString(html.EscapeString(text[pos:last]))
+
+	if end < len(tokens) {
+		b.WriteString(" &hellip;")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,192 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestStem validates words are reduced to their Porter stems.
+func TestStem(t *testing.T) {
+	words := map[string]string{
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"running":         "run",
+		"hopping":         "hop",
+		"agreed":          "agre",
+		"happy":           "happi",
+		"relational":      "relat",
+		"connections":     "connect",
+		"generalizations": "gener",
+		"adjustment":      "adjust",
+		"controlling":     "control",
+		"go":              "go",
+		"café":            "café",
+		"2024":            "2024",
+	}
+
+	t.Log("Given the need to stem words.")
+	{
+		for word, exp := range words {
+			t.Logf("\tTest:\tWhen stemming %q.", word)
+			{
+				if got := stem(word); got != exp {
+					t.Fatalf("\t%s\tShould get %q : got %q", failed, exp, got)
+				}
+				t.Logf("\t%s\tShould get %q.", succeed, exp)
+			}
+		}
+	}
+}
+
+// TestParseQuery validates terms, phrases and exclusions are parsed.
+func TestParseQuery(t *testing.T) {
+	tt := []struct {
+		term string
+		exp  query
+	}{
+		{"Gophers running", query{terms: []string{"gopher", "run"}}},
+		{`"go conference" gophers`, query{terms: []string{"go", "confer", "gopher"}, phrases: [][]string{{"go", "confer"}}}},
+		{`go -java -"rust belt"`, query{terms: []string{"go"}, exclude: [][]string{{"java"}, {"rust", "belt"}}}},
+		{`e-mail "unclosed phrase`, query{terms: []string{"e", "mail", "unclos", "phrase"}, phrases: [][]string{{"e", "mail"}, {"unclos", "phrase"}}}},
+		{` - "" go go`, query{terms: []string{"go"}}},
+	}
+
+	t.Log("Given the need to parse search terms.")
+	{
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen parsing %q.", i, tst.term)
+			{
+				if got := parseQuery(tst.term); !reflect.DeepEqual(got, tst.exp) {
+					t.Fatalf("\t%s\tTest %d:\tShould get %+v : got %+v", failed, i, tst.exp, got)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the expected query.", succeed, i)
+			}
+		}
+	}
+}
+
+// TestIndexSearch validates documents are matched and ranked.
+func TestIndexSearch(t *testing.T) {
+	ix := newIndex()
+	ix.update("a", []Item{
+		{Title: "Gophers everywhere", Description: "<p>Gophers, gophers and more <b>gophers</b> at the go conference.</p>"},
+		{Title: "Weather", Description: "Rain is expected across the country."},
+		{Title: "Conference news", Description: "A gopher spoke at the conference about Java."},
+		{Title: "Long read", Description: "The conference had one gopher among many other long words that make this description much longer than the rest."},
+	})
+	ix.update("b", []Item{
+		{Title: "Go conference", Description: "Gophers met in Berlin."},
+	})
+
+	titles := func(hits []hit) []string {
+		var s []string
+		for _, h := range hits {
+			s = append(s, h.item.Title)
+		}
+		return s
+	}
+
+	t.Log("Given the need to search an index of feeds.")
+	{
+		t.Log("\tTest 0:\tWhen searching for a single term.")
+		{
+			exp := []string{"Gophers everywhere", "Conference news", "Long read"}
+			if got := titles(ix.search(parseQuery("gopher"), "a")); !reflect.DeepEqual(got, exp) {
+				t.Fatalf("\t%s\tTest 0:\tShould rank by term frequency and length : got %q", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould rank by term frequency and length.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen searching for a phrase.")
+		{
+			exp := []string{"Gophers everywhere"}
+			if got := titles(ix.search(parseQuery(`"go conference"`), "a")); !reflect.DeepEqual(got, exp) {
+				t.Fatalf("\t%s\tTest 1:\tShould only match the phrase : got %q", failed, got)
+			}
+			t.Logf("\t%s\tTest 1:\tShould only match the phrase.", succeed)
+
+			if got := ix.search(parseQuery(`"everywhere gophers"`), "a"); len(got) != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould not match across the title and description : got %q", failed, titles(got))
+			}
+			t.Logf("\t%s\tTest 1:\tShould not match across the title and description.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen excluding a term.")
+		{
+			exp := []string{"Gophers everywhere", "Long read"}
+			if got := titles(ix.search(parseQuery("conference -java"), "a")); !reflect.DeepEqual(got, exp) {
+				t.Fatalf("\t%s\tTest 2:\tShould drop the excluded documents : got %q", failed, got)
+			}
+			t.Logf("\t%s\tTest 2:\tShould drop the excluded documents.", succeed)
+		}
+
+		t.Log("\tTest 3:\tWhen searching another feed.")
+		{
+			exp := []string{"Go conference"}
+			if got := titles(ix.search(parseQuery("gophers conference"), "b")); !reflect.DeepEqual(got, exp) {
+				t.Fatalf("\t%s\tTest 3:\tShould only return documents from that feed : got %q", failed, got)
+			}
+			t.Logf("\t%s\tTest 3:\tShould only return documents from that feed.", succeed)
+		}
+
+		t.Log("\tTest 4:\tWhen the query has no terms.")
+		{
+			all := []string{"Gophers everywhere", "Weather", "Conference news", "Long read"}
+			for _, term := range []string{"", "  ", `?! "" -`} {
+				if got := titles(ix.search(parseQuery(term), "a")); !reflect.DeepEqual(got, all) {
+					t.Fatalf("\t%s\tTest 4:\tShould return every document for %q : got %q", failed, term, got)
+				}
+			}
+			t.Logf("\t%s\tTest 4:\tShould return every document in feed order.", succeed)
+
+			exp := []string{"Gophers everywhere", "Weather", "Long read"}
+			if got := titles(ix.search(parseQuery("-java"), "a")); !reflect.DeepEqual(got, exp) {
+				t.Fatalf("\t%s\tTest 4:\tShould return every document not excluded : got %q", failed, got)
+			}
+			t.Logf("\t%s\tTest 4:\tShould return every document not excluded.", succeed)
+		}
+
+		t.Log("\tTest 5:\tWhen a feed is reloaded.")
+		{
+			ix.update("a", []Item{{Title: "Fresh", Description: "Nothing about that animal."}})
+
+			if got := ix.search(parseQuery("gopher"), "a"); len(got) != 0 {
+				t.Fatalf("\t%s\tTest 5:\tShould replace the old documents : got %q", failed, titles(got))
+			}
+			t.Logf("\t%s\tTest 5:\tShould replace the old documents.", succeed)
+
+			if len(ix.docs) != 2 || ix.postings["rain"] != nil {
+				t.Fatalf("\t%s\tTest 5:\tShould clean up the old postings : %d docs", failed, len(ix.docs))
+			}
+			t.Logf("\t%s\tTest 5:\tShould clean up the old postings.", succeed)
+		}
+	}
+}
+
+// TestSnippet validates snippets are escaped and highlighted.
+func TestSnippet(t *testing.T) {
+	tt := []struct {
+		name string
+		text string
+		term string
+		exp  string
+	}{
+		{"highlight", "Gophers & friends meet a gopher.", "gopher", "<mark>Gophers</mark> &amp; friends meet a <mark>gopher</mark>."},
+		{"title only", "Nothing to see", "gopher", "Nothing to see"},
+		{"long", "one two three four five six seven eight nine ten eleven twelve thirteen fourteen fifteen sixteen seventeen eighteen nineteen twenty gopher a b c d e f g h i j k l m n o p q r s t",
+			"gopher", "&hellip; eleven twelve thirteen fourteen fifteen sixteen seventeen eighteen nineteen twenty <mark>gopher</mark> a b c d e f g h i j k l m n o p q r s &hellip;"},
+	}
+
+	t.Log("Given the need to show a snippet of a result.")
+	{
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen the text is %s.", i, tst.name)
+			{
+				if got := snippet(tst.text, parseQuery(tst.term)); got != tst.exp {
+					t.Fatalf("\t%s\tTest %d:\tShould get %q : got %q", failed, i, tst.exp, got)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the highlighted snippet.", succeed, i)
+			}
+		}
+	}
+}
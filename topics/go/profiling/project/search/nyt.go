@@ -1,7 +1,5 @@
 package search
 
-import "log"
-
 var nytFeeds = []string{
 	"http://rss.nytimes.com/services/xml/rss/nyt/HomePage.xml",
 	"http://rss.nytimes.com/services/xml/rss/nyt/US.xml",
@@ -9,27 +7,14 @@ var nytFeeds = []string{
 	"http://rss.nytimes.com/services/xml/rss/nyt/Business.xml",
 }
 
-// NYT provides support for NYT searches.
-type NYT struct{}
-
-// NewNYT returns a NYT Searcher value.
-func NewNYT() Searcher {
-	return NYT{}
+// init registers the NYT provider.
+func init() {
+	MustRegister(Provider{Name: "nyt", Title: "NY Times", Feeds: nytFeeds})
 }
 
-// Search performs a search against the NYT RSS feeds.
-func (NYT) Search(uid string, term string, found chan<- []Result) {
-	results := []Result{}
-
-	for _, feed := range nytFeeds {
-		res, err := rssSearch(uid, term, "NYT", feed)
-		if err != nil {
-			log.Println("ERROR: ", err)
-			continue
-		}
-
-		results = append(results, res...)
-	}
-
-	found <- results
+// NewNYT returns a NYT Searcher value using the feeds currently
+// registered for the provider.
+func NewNYT() Searcher {
+	p, _ := Lookup("nyt")
+	return p.Searcher()
 }
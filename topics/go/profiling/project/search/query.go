@@ -0,0 +1,72 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// query is a parsed search term. Every term and phrase must be found in a
+// document for it to match, and none of the exclusions may be.
+type query struct {
+	terms   []string   // Stemmed terms used to score the documents.
+	phrases [][]string // Stemmed terms that must appear in this order.
+	exclude [][]string // Terms and phrases that must not appear.
+}
+
+// parseQuery parses a search term. Words are separated by spaces, a phrase
+// is wrapped in double quotes and a word or phrase starting with a minus is
+// excluded, as in:
+//
+//	gophers "go conference" -java
+func parseQuery(s string) query {
+	var q query
+	seen := make(map[string]bool)
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		var exclude bool
+		if s[0] == '-' && len(s) > 1 {
+			exclude = true
+			s = s[1:]
+		}
+
+		var text string
+		if s[0] == '"' {
+			s = s[1:]
+			end := strings.IndexByte(s, '"')
+			if end < 0 {
+				end = len(s)
+			}
+			text, s = s[:end], s[min(end+1, len(s)):]
+		} else {
+			end := strings.IndexFunc(s, unicode.IsSpace)
+			if end < 0 {
+				end = len(s)
+			}
+			text, s = s[:end], s[end:]
+		}
+
+		// A word like "e-mail" is split into more than one term, in which
+		// case it's treated as a phrase.
+		stems := terms(text)
+		if len(stems) == 0 {
+			continue
+		}
+
+		if exclude {
+			q.exclude = append(q.exclude, stems)
+			continue
+		}
+
+		if len(stems) > 1 {
+			q.phrases = append(q.phrases, stems)
+		}
+		for _, t := range stems {
+			if !seen[t] {
+				seen[t] = true
+				q.terms = append(q.terms, t)
+			}
+		}
+	}
+
+	return q
+}
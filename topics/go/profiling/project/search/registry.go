@@ -0,0 +1,160 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Provider describes a news source that can be searched by name.
+type Provider struct {
+	Name  string   `yaml:"name"`  // Used in forms and Options, ex. "cnn".
+	Title string   `yaml:"title"` // Displayed to the user, ex. "CNN".
+	Feeds []string `yaml:"feeds"` // The RSS feeds to search.
+
+	// New constructs the Searcher for the provider. When nil, the
+	// provider's feeds are searched as RSS feeds.
+	New func(p Provider) Searcher `yaml:"-"`
+}
+
+// Searcher returns a Searcher for the provider.
+func (p Provider) Searcher() Searcher {
+	if p.New != nil {
+		return p.New(p)
+	}
+	return NewRSS(p.Title, p.Feeds)
+}
+
+// Set of errors returned when registering providers.
+var (
+	ErrInvalidProvider   = errors.New("invalid provider")
+	ErrDuplicateProvider = errors.New("provider already registered")
+)
+
+// rgxName validates a provider name can be used as a form field.
+var rgxName = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// reserved are form fields already used by the search form.
+var reserved = []string{"term", "first", "debug"}
+
+// registry maintains the set of known providers.
+var registry = struct {
+	sync.RWMutex
+	m map[string]Provider
+}{
+	m: make(map[string]Provider),
+}
+
+// Register adds a provider to the registry. It is an error to register two
+// providers with the same name.
+func Register(p Provider) error {
+	return register(p, false)
+}
+
+// MustRegister is like Register but panics on error. It is used by the
+// providers built into this package.
+func MustRegister(p Provider) {
+	if err := Register(p); err != nil {
+		panic(err)
+	}
+}
+
+// register validates the provider and adds it to the registry, replacing
+// an existing provider with the same name when replace is true. A provider
+// that replaces another keeps its New func, and its title and feeds unless
+// they're set, so only what's changed is replaced.
+func register(p Provider, replace bool) error {
+	if !rgxName.MatchString(p.Name) || slices.Contains(reserved, p.Name) {
+		return fmt.Errorf("%w: name %q", ErrInvalidProvider, p.Name)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	old, exists := registry.m[p.Name]
+	switch {
+	case exists && !replace:
+		return fmt.Errorf("%w: %q", ErrDuplicateProvider, p.Name)
+
+	case exists:
+		if p.New == nil {
+			p.New = old.New
+		}
+		if p.Title == "" {
+			p.Title = old.Title
+		}
+		if len(p.Feeds) == 0 {
+			p.Feeds = old.Feeds
+		}
+	}
+
+	if len(p.Feeds) == 0 && p.New == nil {
+		return fmt.Errorf("%w: %q has no feeds", ErrInvalidProvider, p.Name)
+	}
+	if p.Title == "" {
+		p.Title = strings.ToUpper(p.Name)
+	}
+	registry.m[p.Name] = p
+
+	return nil
+}
+
+// Lookup returns the provider registered under the specified name.
+func Lookup(name string) (Provider, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	p, exists := registry.m[name]
+	return p, exists
+}
+
+// Providers returns every registered provider sorted by name.
+func Providers() []Provider {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	providers := make([]Provider, 0, len(registry.m))
+	for _, p := range registry.m {
+		providers = append(providers, p)
+	}
+
+	slices.SortFunc(providers, func(a, b Provider) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return providers
+}
+
+// LoadProviders reads a YAML document of providers and registers them. A
+// provider with the same name as one already registered replaces its title
+// and feeds, which allows the feeds of the built in providers to be changed.
+// How the provider is searched doesn't change.
+//
+//	providers:
+//	  - name: npr
+//	    title: NPR
+//	    feeds:
+//	      - https://feeds.npr.org/1001/rss.xml
+func LoadProviders(r io.Reader) error {
+	var doc struct {
+		Providers []Provider `yaml:"providers"`
+	}
+
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return fmt.Errorf("decoding providers: %w", err)
+	}
+
+	for _, p := range doc.Providers {
+		if err := register(p, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package search
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const succeed = "✓"
+const failed = "✗"
+
+// TestRegistry validates providers can be registered and looked up.
+func TestRegistry(t *testing.T) {
+	t.Log("Given the need to register search providers.")
+	{
+		t.Log("\tTest 0:\tWhen using the built in providers.")
+		{
+			var names []string
+			for _, p := range Providers() {
+				names = append(names, p.Name)
+			}
+
+			builtin := slices.Contains(names, "bbc") && slices.Contains(names, "cnn") && slices.Contains(names, "nyt")
+			if !builtin || !slices.IsSorted(names) {
+				t.Fatalf("\t%s\tTest 0:\tShould have the built in providers sorted by name : %v", failed, names)
+			}
+			t.Logf("\t%s\tTest 0:\tShould have the built in providers sorted by name.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen registering invalid providers.")
+		{
+			tests := []struct {
+				p   Provider
+				err error
+			}{
+				{Provider{Name: "cnn", Feeds: []string{"http://example.com"}}, ErrDuplicateProvider},
+				{Provider{Name: "term", Feeds: []string{"http://example.com"}}, ErrInvalidProvider},
+				{Provider{Name: "Bad Name", Feeds: []string{"http://example.com"}}, ErrInvalidProvider},
+				{Provider{Name: "nofeeds"}, ErrInvalidProvider},
+			}
+
+			for _, tt := range tests {
+				if err := Register(tt.p); !errors.Is(err, tt.err) {
+					t.Fatalf("\t%s\tTest 1:\tShould reject provider %q : %v", failed, tt.p.Name, err)
+				}
+			}
+			t.Logf("\t%s\tTest 1:\tShould reject invalid providers.", succeed)
+		}
+
+		t.Log("\tTest 2:\tWhen loading providers from YAML.")
+		{
+			const doc = `
+providers:
+  - name: testfeed
+    title: Test Feed
+    feeds:
+      - http://example.com/one.xml
+      - http://example.com/two.xml
+  - name: bbc
+    title: BBC
+    feeds:
+      - http://example.com/bbc.xml
+`
+			if err := LoadProviders(strings.NewReader(doc)); err != nil {
+				t.Fatalf("\t%s\tTest 2:\tShould load the providers : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 2:\tShould load the providers.", succeed)
+
+			p, exists := Lookup("testfeed")
+			if !exists || p.Title != "Test Feed" || len(p.Feeds) != 2 {
+				t.Fatalf("\t%s\tTest 2:\tShould register a new provider : %+v", failed, p)
+			}
+			t.Logf("\t%s\tTest 2:\tShould register a new provider.", succeed)
+
+			p, _ = Lookup("bbc")
+			if len(p.Feeds) != 1 || p.Feeds[0] != "http://example.com/bbc.xml" {
+				t.Fatalf("\t%s\tTest 2:\tShould replace the feeds of a built in provider : %+v", failed, p)
+			}
+			t.Logf("\t%s\tTest 2:\tShould replace the feeds of a built in provider.", succeed)
+
+			// Put the built in feeds back for the other tests.
+			register(Provider{Name: "bbc", Title: "BBC", Feeds: bbcFeeds}, true)
+		}
+
+		t.Log("\tTest 3:\tWhen YAML overrides a provider with its own searcher.")
+		{
+			registerFake(t, "custom", found(0))
+			defer func() {
+				registry.Lock()
+				delete(registry.m, "custom")
+				registry.Unlock()
+			}()
+
+			const doc = `
+providers:
+  - name: custom
+    feeds:
+      - http://example.com/custom.xml
+`
+			if err := LoadProviders(strings.NewReader(doc)); err != nil {
+				t.Fatalf("\t%s\tTest 3:\tShould load the providers : %v", failed, err)
+			}
+
+			p, _ := Lookup("custom")
+			if p.New == nil || p.Title != "custom" || len(p.Feeds) != 1 {
+				t.Fatalf("\t%s\tTest 3:\tShould only replace the feeds : %+v", failed, p)
+			}
+			if _, ok := p.Searcher().(fakeSearcher); !ok {
+				t.Fatalf("\t%s\tTest 3:\tShould keep its own searcher : %T", failed, p.Searcher())
+			}
+			t.Logf("\t%s\tTest 3:\tShould only replace the feeds and keep its own searcher.", succeed)
+		}
+
+		t.Log("\tTest 4:\tWhen the YAML document is invalid.")
+		{
+			err := LoadProviders(strings.NewReader("providers:\n  - name: [oops"))
+			if err == nil {
+				t.Fatalf("\t%s\tTest 4:\tShould return an error.", failed)
+			}
+			t.Logf("\t%s\tTest 4:\tShould return an error : %v", succeed, err)
+		}
+	}
+}
@@ -1,130 +1,86 @@
 package search
 
 import (
-	"encoding/xml"
-	"log"
-	"net/http"
-	"strings"
-	"sync"
-	"time"
+	"context"
+	"errors"
+	"log/slog"
 
-	gc "github.com/patrickmn/go-cache"
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
 )
 
-// Maintain a cache of retrieved documents. The cache will maintain items for
-// fifteen seconds and then marked as expired. This is a very small cache so the
-// gc time will be every hour.
+// rssSearch is used against any RSS feeds.
+func rssSearch(ctx context.Context, uid, term, engine, uri string) ([]Result, error) {
+	// Make sure the feed is loaded and indexed.
+	if _, err := feeds.load(ctx, uri); err != nil {
+		return []Result{}, err
+	}
 
-const (
-	expiration = time.Minute * 15
-	cleanup    = time.Hour
-)
+	// Create an empty slice of results.
+	results := []Result{}
 
-var cache = gc.New(expiration, cleanup)
+	// Capture the data we need for our results from the documents in the
+	// feed that match the query, best match first.
+	for _, h := range idx.search(parseQuery(term), uri) {
+		results = append(results, Result{
+			Engine:    engine,
+			Title:     h.item.Title,
+			Link:      h.item.Link,
+			Content:   h.item.Description,
+			Published: h.item.Published,
+			Score:     h.score,
+			Snippet:   h.snippet,
+		})
+	}
 
-var fetch = struct {
-	sync.Mutex
-	m map[string]*sync.Mutex
-}{
-	m: make(map[string]*sync.Mutex),
+	return results, nil
 }
 
-type (
-
-	// Item defines the fields associated with the item tag in the RSS document.
-	Item struct {
-		XMLName     xml.Name `xml:"item"`
-		PubDate     string   `xml:"pubDate"`
-		Title       string   `xml:"title"`
-		Description string   `xml:"description"`
-		Link        string   `xml:"link"`
-	}
+// RSS provides support for searching a set of RSS feeds.
+type RSS struct {
+	engine string
+	feeds  []string
+}
 
-	// Image defines the fields associated with the image tag in the RSS document.
-	Image struct {
-		XMLName xml.Name `xml:"image"`
-		URL     string   `xml:"url"`
-		Title   string   `xml:"title"`
-		Link    string   `xml:"link"`
+// NewRSS returns a Searcher value for the specified feeds. The engine is the
+// name reported in the results.
+func NewRSS(engine string, feeds []string) Searcher {
+	return RSS{
+		engine: engine,
+		feeds:  feeds,
 	}
+}
 
-	// Channel defines the fields associated with the channel tag in the RSS document.
-	Channel struct {
-		XMLName xml.Name `xml:"channel"`
-		Image   Image    `xml:"image"`
-		Items   []Item   `xml:"item"`
-	}
+// Search performs a search against the RSS feeds. The feeds are searched
+// until the context is cancelled, and the errors for every feed that failed
+// are returned along with the results from the feeds that didn't. Every
+// feed is searched in a span of its own.
+func (r RSS) Search(ctx context.Context, uid string, term string) ([]Result, error) {
+	log := telemetry.Logger(ctx)
 
-	// Document defines the fields associated with the RSS document.
-	Document struct {
-		XMLName xml.Name `xml:"rss"`
-		Channel Channel  `xml:"channel"`
-	}
-)
+	results := []Result{}
+	var errs []error
 
-// rssSearch is used against any RSS feeds.
-func rssSearch(uid, term, engine, uri string) ([]Result, error) {
-	var mu *sync.Mutex
-	fetch.Lock()
-	{
-		var found bool
-		mu, found = fetch.m[uri]
-		if !found {
-			mu = &sync.Mutex{}
-			fetch.m[uri] = mu
+	for _, feed := range r.feeds {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
 		}
-	}
-	fetch.Unlock()
-
-	var d Document
-	mu.Lock()
-	{
-		// Look in the cache.
-		v, found := cache.Get(uri)
-
-		// Based on the cache lookup determine what to do.
-		switch {
-		case found:
-			d = v.(Document)
-
-		default:
-
-			// Pull down the rss feed.
-			resp, err := http.Get(uri)
-			if err != nil {
-				return []Result{}, err
-			}
-
-			// Schedule the close of the response body.
-			defer resp.Body.Close()
 
-			// Decode the results into a document.
-			if err := xml.NewDecoder(resp.Body).Decode(&d); err != nil {
-				return []Result{}, err
-			}
+		fctx, span := telemetry.Start(ctx, "feed", slog.String("feed.url", feed))
+		res, err := rssSearch(fctx, uid, term, r.engine, feed)
+		span.SetAttributes(slog.Int("search.results", len(res)))
+		span.SetError(err)
+		span.End()
 
-			// Save this document into the cache.
-			cache.Set(uri, d, expiration)
-
-			log.Println("reloaded cache", uri)
+		if err != nil {
+			log.Warn("searching feed", "feed", feed, "error", err)
+			errs = append(errs, err)
+			continue
 		}
-	}
-	mu.Unlock()
 
-	// Create an empty slice of results.
-	results := []Result{}
-
-	// Capture the data we need for our results if we find the search term.
-	for _, item := range d.Channel.Items {
-		if strings.Contains(strings.ToLower(item.Description), strings.ToLower(term)) {
-			results = append(results, Result{
-				Engine:  engine,
-				Title:   item.Title,
-				Link:    item.Link,
-				Content: item.Description,
-			})
-		}
+		results = append(results, res...)
 	}
 
-	return results, nil
+	log.Info("searched feeds", "feeds", len(r.feeds), "results", len(results), "errors", len(errs))
+	return results, errors.Join(errs...)
 }
@@ -4,7 +4,10 @@
 // Sample test to show how to write a basic unit test.
 package search
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 var final []Result
 
@@ -14,7 +17,7 @@ func BenchmarkRssSearch(b *testing.B) {
 	var err error
 
 	for i := 0; i < b.N; i++ {
-		result, err = rssSearch("1", "trump", "nyt", "http://rss.nytimes.com/services/xml/rss/nyt/HomePage.xml")
+		result, err = rssSearch(context.Background(), "1", "trump", "nyt", "http://rss.nytimes.com/services/xml/rss/nyt/HomePage.xml")
 		if err != nil {
 			b.FailNow()
 		}
@@ -2,23 +2,40 @@
 // news feeds.
 package search
 
-import "html/template"
-
-// Options provides the search options for performing searches.
+import (
+	"context"
+	"errors"
+	"html/template"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
+)
+
+// DefaultTimeout is the deadline for a search when Options doesn't
+// specify one.
+const DefaultTimeout = 5 * time.Second
+
+// Options provides the search options for performing searches. The Term
+// may contain words, quoted phrases and words or phrases to exclude that
+// start with a minus, as in: gophers "go conference" -java
 type Options struct {
-	Term  string
-	CNN   bool
-	NYT   bool
-	BBC   bool
-	First bool
+	Term      string
+	Providers []string      // Names of the registered providers to search.
+	First     bool          // Stop searching once any provider has results.
+	Timeout   time.Duration // Deadline for the whole search.
 }
 
 // Result represents a search result that was found.
 type Result struct {
-	Engine  string
-	Title   string
-	Link    string
-	Content string
+	Engine    string
+	Title     string
+	Link      string
+	Content   string
+	Published time.Time
+	Score     float64 // Relevance of the result to the term.
+	Snippet   string  // HTML extract of the content with the term highlighted.
 }
 
 // TitleHTML fixes encoding issues.
@@ -31,61 +48,178 @@ func (r *Result) ContentHTML() template.HTML {
 	return template.HTML(r.Content)
 }
 
+// SnippetHTML returns the highlighted snippet.
+func (r *Result) SnippetHTML() template.HTML {
+	return template.HTML(r.Snippet)
+}
+
 // Searcher declares an interface used to leverage different
-// search engines to find results.
+// search engines to find results. A Searcher must return once the
+// context is cancelled. Results found before an error occurred may
+// be returned along with the error.
 type Searcher interface {
-	Search(uid string, term string, found chan<- []Result)
+	Search(ctx context.Context, uid string, term string) ([]Result, error)
 }
 
-// Submit uses goroutines and channels to perform a search against the
-// feeds concurrently.
-func Submit(uid string, options Options) []Result {
-	searchers := make(map[string]Searcher)
+// Set of states a provider's search can finish in.
+const (
+	StateOK        = "ok"
+	StateError     = "error"
+	StateTimeout   = "timeout"
+	StateCancelled = "cancelled"
+)
+
+// Status reports how the search against a single provider went.
+type Status struct {
+	Name     string // Name of the provider.
+	Engine   string // Title of the provider.
+	State    string
+	Results  int
+	Duration time.Duration
+	Err      error
+}
 
-	// Create a CNN Searcher if checked.
-	if options.CNN {
-		searchers["cnn"] = NewCNN()
-	}
+// Response contains the results of a search and the status of every
+// provider that was searched.
+type Response struct {
+	Results []Result
+	Status  []Status
+}
 
-	// Create a NYT Searcher if checked.
-	if options.NYT {
-		searchers["nyt"] = NewNYT()
+// engine pairs a provider with the Searcher used to search it.
+type engine struct {
+	name     string
+	title    string
+	searcher Searcher
+}
+
+// outcome is what a searcher goroutine sends back to Submit.
+type outcome struct {
+	idx      int
+	results  []Result
+	err      error
+	duration time.Duration
+}
+
+// Submit uses goroutines and channels to perform a search against the
+// feeds concurrently. The search is bounded by the options timeout, and
+// the results found by the providers that finished in time are returned
+// along with the status of every provider. Each provider is searched in a
+// span of its own, with a logger tagged with the provider's name.
+func Submit(ctx context.Context, uid string, options Options) Response {
+	ctx, span := telemetry.Start(ctx, "search.Submit", slog.String("search.term", options.Term))
+	defer span.End()
+
+	log := telemetry.Logger(ctx)
+	var engines []engine
+
+	// Create a Searcher for every provider that was selected.
+	for _, name := range options.Providers {
+		p, exists := Lookup(name)
+		if !exists {
+			log.Error("unknown provider", "provider", name)
+			continue
+		}
+		engines = append(engines, engine{name: p.Name, title: p.Title, searcher: p.Searcher()})
 	}
 
-	// Create a BBC Searcher if checked.
-	if options.BBC {
-		searchers["bbc"] = NewBBC()
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
 	}
 
-	results := make(chan []Result)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	// Perform the searches concurrently. Using a map because
-	// it returns the searchers in a random order every time.
-	for _, searcher := range searchers {
-		go searcher.Search(uid, options.Term, results)
-	}
+	// The channel is buffered so a searcher that finishes after we have
+	// walked away is never blocked sending its results.
+	outcomes := make(chan outcome, len(engines))
 
-	var final []Result
+	start := time.Now()
+	for i, e := range engines {
+		go func() {
+			ctx, span := telemetry.Start(ctx, "search "+e.name, slog.String("search.engine", e.name))
+			ctx = telemetry.WithLogger(ctx, log.With("engine", e.name))
 
-	// Wait for the results to come back.
-	for search := 0; search < len(searchers); search++ {
+			results, err := e.searcher.Search(ctx, uid, options.Term)
 
-		// If we just want the first result, don't wait any longer by
-		// concurrently discarding the remaining results.
-		// Failing to do so will leave the Searchers blocked forever.
-		if options.First && (search > 0 && len(final) > 0) {
-			go func() {
-				<-results
-			}()
-			continue
-		}
+			span.SetAttributes(slog.Int("search.results", len(results)))
+			span.SetError(err)
+			span.End()
 
-		// Wait to receive results.
-		found := <-results
+			outcomes <- outcome{idx: i, results: results, err: err, duration: time.Since(start)}
+		}()
+	}
 
-		// Save the results to the final slice.
-		final = append(final, found...)
+	resp := Response{
+		Status: make([]Status, len(engines)),
+	}
+	for i, e := range engines {
+		resp.Status[i].Name = e.name
+		resp.Status[i].Engine = e.title
 	}
 
-	return final
+	// Wait for the results to come back or the deadline to pass.
+	pending := len(engines)
+	for pending > 0 {
+		select {
+		case o := <-outcomes:
+			pending--
+
+			st := &resp.Status[o.idx]
+			st.Duration = o.duration
+			st.Results = len(o.results)
+			st.Err = o.err
+			st.State = state(ctx, o.err)
+
+			// Save the results to the final slice, even if the provider
+			// failed part way through.
+			resp.Results = append(resp.Results, o.results...)
+
+			// If we just want the first result, cancel the searchers
+			// that are still running.
+			if options.First && len(resp.Results) > 0 {
+				cancel()
+			}
+
+		case <-ctx.Done():
+
+			// Mark the providers that didn't finish in time.
+			for i := range resp.Status {
+				if resp.Status[i].State == "" {
+					resp.Status[i].State = state(ctx, ctx.Err())
+					resp.Status[i].Err = ctx.Err()
+					resp.Status[i].Duration = time.Since(start)
+				}
+			}
+			rank(resp.Results)
+			span.SetAttributes(slog.Int("search.results", len(resp.Results)))
+			return resp
+		}
+	}
+
+	rank(resp.Results)
+	span.SetAttributes(slog.Int("search.results", len(resp.Results)))
+	return resp
+}
+
+// rank sorts the results from every provider by relevance, keeping results
+// with the same score in the order they arrived.
+func rank(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+}
+
+// state classifies the error returned by a searcher.
+func state(ctx context.Context, err error) string {
+	switch {
+	case err == nil:
+		return StateOK
+	case errors.Is(err, context.DeadlineExceeded):
+		return StateTimeout
+	case errors.Is(err, context.Canceled) || ctx.Err() == context.Canceled:
+		return StateCancelled
+	}
+	return StateError
 }
@@ -0,0 +1,142 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeSearcher provides a Searcher whose behavior is controlled by a
+// function.
+type fakeSearcher func(ctx context.Context, term string) ([]Result, error)
+
+func (f fakeSearcher) Search(ctx context.Context, uid string, term string) ([]Result, error) {
+	return f(ctx, term)
+}
+
+// registerFake registers a provider backed by the fake searcher.
+func registerFake(t *testing.T, name string, f fakeSearcher) {
+	t.Helper()
+	err := register(Provider{Name: name, Title: name, New: func(Provider) Searcher { return f }}, true)
+	if err != nil {
+		t.Fatalf("\t%s\tShould be able to register %s : %v", failed, name, err)
+	}
+}
+
+// found returns a fake searcher that finds one result after the delay.
+func found(delay time.Duration) fakeSearcher {
+	return func(ctx context.Context, term string) ([]Result, error) {
+		select {
+		case <-time.After(delay):
+			return []Result{{Title: term}}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// TestSubmit validates the deadline, first mode and status report.
+func TestSubmit(t *testing.T) {
+	cancelled := make(chan struct{})
+
+	registerFake(t, "fast", found(0))
+	registerFake(t, "slow", found(time.Hour))
+	registerFake(t, "watch", func(ctx context.Context, term string) ([]Result, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	})
+	registerFake(t, "partial", func(ctx context.Context, term string) ([]Result, error) {
+		return []Result{{Title: "partial"}}, errors.New("feed 2 is down")
+	})
+
+	t.Log("Given the need to search providers with a deadline.")
+	{
+		t.Log("\tTest 0:\tWhen one provider is too slow.")
+		{
+			opts := Options{Term: "go", Providers: []string{"fast", "slow", "partial"}, Timeout: 50 * time.Millisecond}
+			resp := Submit(context.Background(), "1", opts)
+
+			if len(resp.Results) != 2 {
+				t.Fatalf("\t%s\tTest 0:\tShould return the partial results : %+v", failed, resp.Results)
+			}
+			t.Logf("\t%s\tTest 0:\tShould return the partial results.", succeed)
+
+			exp := []string{StateOK, StateTimeout, StateError}
+			for i, st := range resp.Status {
+				if st.State != exp[i] || st.Engine != opts.Providers[i] {
+					t.Fatalf("\t%s\tTest 0:\tShould report the status of %s as %s : %+v", failed, opts.Providers[i], exp[i], st)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould report the status of every provider.", succeed)
+
+			if resp.Status[2].Results != 1 || resp.Status[2].Err == nil {
+				t.Fatalf("\t%s\tTest 0:\tShould report the error with the results : %+v", failed, resp.Status[2])
+			}
+			t.Logf("\t%s\tTest 0:\tShould report the error with the results.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen only the first result is wanted.")
+		{
+			opts := Options{Term: "go", Providers: []string{"watch", "fast"}, First: true, Timeout: time.Hour}
+
+			start := time.Now()
+			resp := Submit(context.Background(), "1", opts)
+
+			if time.Since(start) > time.Second || len(resp.Results) != 1 {
+				t.Fatalf("\t%s\tTest 1:\tShould return as soon as results are found : %+v", failed, resp)
+			}
+			t.Logf("\t%s\tTest 1:\tShould return as soon as results are found.", succeed)
+
+			select {
+			case <-cancelled:
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest 1:\tShould cancel the outstanding searchers.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould cancel the outstanding searchers.", succeed)
+
+			if st := resp.Status[0].State; st != StateCancelled {
+				t.Fatalf("\t%s\tTest 1:\tShould report the outstanding searcher as cancelled : %s", failed, st)
+			}
+			t.Logf("\t%s\tTest 1:\tShould report the outstanding searcher as cancelled.", succeed)
+		}
+	}
+}
+
+// TestSubmitRanked validates results from every provider are ranked by
+// their score.
+func TestSubmitRanked(t *testing.T) {
+	scored := func(scores ...float64) fakeSearcher {
+		return func(ctx context.Context, term string) ([]Result, error) {
+			var results []Result
+			for _, s := range scores {
+				results = append(results, Result{Score: s})
+			}
+			return results, nil
+		}
+	}
+
+	registerFake(t, "ranked1", scored(3, 1))
+	registerFake(t, "ranked2", scored(4, 2, 0))
+
+	t.Log("Given the need to rank the results of a search.")
+	{
+		t.Log("\tTest 0:\tWhen providers return scored results.")
+		{
+			resp := Submit(context.Background(), "1", Options{Term: "go", Providers: []string{"ranked1", "ranked2"}})
+
+			var got []float64
+			for _, r := range resp.Results {
+				got = append(got, r.Score)
+			}
+
+			exp := []float64{4, 3, 2, 1, 0}
+			if !reflect.DeepEqual(got, exp) {
+				t.Fatalf("\t%s\tTest 0:\tShould order the results by score : got %v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould order the results by score.", succeed)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a word found in a piece of text. The start and end are the byte
+// offsets of the word in the original text.
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize splits the text into lower case words. Any character that isn't
+// a letter or a digit separates words.
+func tokenize(text string) []token {
+	var tokens []token
+
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+
+		if start >= 0 {
+			tokens = append(tokens, token{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+
+	return tokens
+}
+
+// terms returns the stemmed terms in the text.
+func terms(text string) []string {
+	tokens := tokenize(text)
+
+	terms := make([]string, len(tokens))
+	for i, tkn := range tokens {
+		terms[i] = stem(tkn.term)
+	}
+
+	return terms
+}
+
+// plainText strips the tags from a piece of HTML and decodes the entities,
+// leaving the text a reader would see.
+func plainText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	var inTag bool
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+			b.WriteByte(' ')
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+
+	return strings.Join(strings.Fields(html.UnescapeString(b.String())), " ")
+}
+
+// =============================================================================
+
+// stem reduces an English word to its stem using the Porter stemming
+// algorithm, so "connected", "connecting" and "connections" are all
+// indexed as "connect". Words that aren't plain ASCII are returned as is.
+//
+// https://tartarus.org/martin/PorterStemmer/def.txt
+func stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+
+	for i := 0; i < len(word); i++ {
+		if word[i] >= utf8.RuneSelf || word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	w := []byte(word)
+	w = step1a(w)
+	w = step1b(w)
+	w = step1c(w)
+	w = step2(w)
+	w = step3(w)
+	w = step4(w)
+	w = step5(w)
+
+	return string(w)
+}
+
+// isConsonant reports if the letter at i is a consonant. A y is a
+// consonant when it follows a vowel or starts the word.
+func isConsonant(w []byte, i int) bool {
+	switch w[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !isConsonant(w, i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in the word, which is the
+// m in [C](VC){m}[V].
+func measure(w []byte) int {
+	var m int
+
+	i := 0
+	for i < len(w) && isConsonant(w, i) {
+		i++
+	}
+
+	for i < len(w) {
+		for i < len(w) && !isConsonant(w, i) {
+			i++
+		}
+		if i == len(w) {
+			break
+		}
+
+		m++
+		for i < len(w) && isConsonant(w, i) {
+			i++
+		}
+	}
+
+	return m
+}
+
+// hasVowel reports if the word contains a vowel.
+func hasVowel(w []byte) bool {
+	for i := range w {
+		if !isConsonant(w, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsDouble reports if the word ends with a double consonant.
+func endsDouble(w []byte) bool {
+	l := len(w)
+	return l >= 2 && w[l-1] == w[l-2] && isConsonant(w, l-1)
+}
+
+// endsCVC reports if the word ends consonant-vowel-consonant where the last
+// consonant isn't a w, x or y, as in "hop" but not "snow".
+func endsCVC(w []byte) bool {
+	l := len(w)
+	if l < 3 || !isConsonant(w, l-3) || isConsonant(w, l-2) || !isConsonant(w, l-1) {
+		return false
+	}
+
+	switch w[l-1] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// hasSuffix reports if the word ends with the suffix.
+func hasSuffix(w []byte, suffix string) bool {
+	return len(w) > len(suffix) && string(w[len(w)-len(suffix):]) == suffix
+}
+
+// rule replaces a suffix when the measure of the remaining stem is above min.
+type rule struct {
+	suffix  string
+	replace string
+}
+
+// applyRules replaces the first suffix from the rules that the word ends
+// with. Only that suffix is considered, so if the stem is too short the
+// word is left alone.
+func applyRules(w []byte, min int, rules []rule) []byte {
+	for _, r := range rules {
+		if !hasSuffix(w, r.suffix) {
+			continue
+		}
+
+		stem := w[:len(w)-len(r.suffix)]
+		if measure(stem) > min {
+			return append(stem, r.replace...)
+		}
+		return w
+	}
+	return w
+}
+
+// step1a removes plurals.
+func step1a(w []byte) []byte {
+	switch {
+	case hasSuffix(w, "sses"), hasSuffix(w, "ies"):
+		return w[:len(w)-2]
+	case hasSuffix(w, "ss"):
+		return w
+	case hasSuffix(w, "s"):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+// step1b removes -ed and -ing.
+func step1b(w []byte) []byte {
+	if hasSuffix(w, "eed") {
+		if measure(w[:len(w)-3]) > 0 {
+			return w[:len(w)-1]
+		}
+		return w
+	}
+
+	var stem []byte
+	switch {
+	case hasSuffix(w, "ed") && hasVowel(w[:len(w)-2]):
+		stem = w[:len(w)-2]
+	case hasSuffix(w, "ing") && hasVowel(w[:len(w)-3]):
+		stem = w[:len(w)-3]
+	default:
+		return w
+	}
+
+	switch {
+	case hasSuffix(stem, "at"), hasSuffix(stem, "bl"), hasSuffix(stem, "iz"):
+		return append(stem, 'e')
+	case endsDouble(stem):
+		switch stem[len(stem)-1] {
+		case 'l', 's', 'z':
+			return stem
+		}
+		return stem[:len(stem)-1]
+	case measure(stem) == 1 && endsCVC(stem):
+		return append(stem, 'e')
+	}
+	return stem
+}
+
+// step1c turns a terminal y into an i when there is another vowel.
+func step1c(w []byte) []byte {
+	if hasSuffix(w, "y") && hasVowel(w[:len(w)-1]) {
+		w[len(w)-1] = 'i'
+	}
+	return w
+}
+
+var step2Rules = []rule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"abli", "able"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+}
+
+// step2 maps double suffixes to single ones.
+func step2(w []byte) []byte {
+	return applyRules(w, 0, step2Rules)
+}
+
+var step3Rules = []rule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// step3 removes -ful, -ness and similar suffixes.
+func step3(w []byte) []byte {
+	return applyRules(w, 0, step3Rules)
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+// step4 removes the remaining suffixes from words with a long stem.
+func step4(w []byte) []byte {
+
+	// Find the longest suffix the word ends with.
+	var suffix string
+	for _, s := range step4Suffixes {
+		if hasSuffix(w, s) && len(s) > len(suffix) {
+			suffix = s
+		}
+	}
+	if suffix == "" {
+		return w
+	}
+
+	stem := w[:len(w)-len(suffix)]
+	if measure(stem) <= 1 {
+		return w
+	}
+
+	// The -ion suffix is only removed after an s or a t.
+	if suffix == "ion" && !hasSuffix(stem, "s") && !hasSuffix(stem, "t") {
+		return w
+	}
+
+	return stem
+}
+
+// step5 tidies up a final e and double l.
+func step5(w []byte) []byte {
+	if hasSuffix(w, "e") {
+		stem := w[:len(w)-1]
+		if m := measure(stem); m > 1 || (m == 1 && !endsCVC(stem)) {
+			w = stem
+		}
+	}
+
+	if measure(w) > 1 && endsDouble(w) && w[len(w)-1] == 'l' {
+		w = w[:len(w)-1]
+	}
+
+	return w
+}
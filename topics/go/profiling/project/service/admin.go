@@ -0,0 +1,80 @@
+package service
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime/trace"
+	"sync"
+	"time"
+)
+
+// maxTraceSeconds is the longest trace that can be captured.
+const maxTraceSeconds = 60
+
+// NewAdmin constructs the handler for the admin listener, which serves the
+// profiling endpoints and the published variables. It should only be
+// reachable by the people running the service.
+func NewAdmin() http.Handler {
+	mux := http.NewServeMux()
+
+	// The profiles from net/http/pprof, go tool pprof understands these.
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	// The published variables, such as the request and cache counters.
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	// Execution traces captured on demand.
+	mux.HandleFunc("/debug/trace", captureTrace)
+
+	return mux
+}
+
+// tracing makes sure only one trace is captured at a time.
+var tracing sync.Mutex
+
+// captureTrace handles GET /debug/trace?seconds=N. It captures an execution
+// trace for N seconds, defaulting to 5, and returns it as a download.
+//
+//	$ curl -o trace.out "http://localhost:6060/debug/trace?seconds=10"
+//	$ go tool trace trace.out
+func captureTrace(w http.ResponseWriter, r *http.Request) {
+	seconds, err := intParam(r.FormValue("seconds"), 5, 1, maxTraceSeconds)
+	if err != nil {
+		http.Error(w, "seconds "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !tracing.TryLock() {
+		http.Error(w, "a trace is already being captured", http.StatusConflict)
+		return
+	}
+	defer tracing.Unlock()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="trace-%s.out"`, time.Now().Format("20060102-150405")))
+
+	// The trace can't be started if it's being captured through the
+	// pprof endpoint or by a test run with -trace.
+	if err := trace.Start(w); err != nil {
+		w.Header().Del("Content-Disposition")
+		http.Error(w, "starting trace: "+err.Error(), http.StatusConflict)
+		return
+	}
+
+	// Stop early if the client goes away.
+	timer := time.NewTimer(time.Duration(seconds) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-r.Context().Done():
+	}
+
+	trace.Stop()
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestAdmin validates the profiling endpoints are served by the admin
+// listener and not by the service.
+func TestAdmin(t *testing.T) {
+	admin := httptest.NewServer(NewAdmin())
+	defer admin.Close()
+
+	srv := httptest.NewServer(New(Config{}))
+	defer srv.Close()
+
+	paths := []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/vars"}
+
+	t.Log("Given the need to profile the service.")
+	{
+		for i, path := range paths {
+			t.Logf("\tTest %d:\tWhen requesting %s.", i, path)
+			{
+				if resp := get(t, admin, path, "", nil); resp.StatusCode != http.StatusOK {
+					t.Fatalf("\t%s\tTest %d:\tShould be served by the admin listener : %d", failed, i, resp.StatusCode)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be served by the admin listener.", succeed, i)
+
+				if resp := get(t, srv, path, "", nil); resp.StatusCode != http.StatusNotFound {
+					t.Fatalf("\t%s\tTest %d:\tShould not be served by the service : %d", failed, i, resp.StatusCode)
+				}
+				t.Logf("\t%s\tTest %d:\tShould not be served by the service.", succeed, i)
+			}
+		}
+	}
+}
+
+// TestCaptureTrace validates execution traces can be captured on demand.
+func TestCaptureTrace(t *testing.T) {
+	admin := httptest.NewServer(NewAdmin())
+	defer admin.Close()
+
+	t.Log("Given the need to capture an execution trace.")
+	{
+		t.Log("\tTest 0:\tWhen two traces are requested at once.")
+		{
+			type result struct {
+				status int
+				body   string
+			}
+
+			var wg sync.WaitGroup
+			results := make([]result, 2)
+			for i := range results {
+				wg.Go(func() {
+					resp, err := http.Get(admin.URL + "/debug/trace?seconds=1")
+					if err != nil {
+						t.Errorf("\t%s\tTest 0:\tShould be able to make the request : %v", failed, err)
+						return
+					}
+					defer resp.Body.Close()
+
+					body, _ := io.ReadAll(resp.Body)
+					results[i] = result{resp.StatusCode, string(body)}
+				})
+			}
+			wg.Wait()
+
+			var ok, conflict int
+			for _, r := range results {
+				switch r.status {
+				case http.StatusOK:
+					if !strings.HasPrefix(r.body, "go 1.") {
+						t.Fatalf("\t%s\tTest 0:\tShould receive a trace : %.20q", failed, r.body)
+					}
+					ok++
+				case http.StatusConflict:
+					conflict++
+				}
+			}
+
+			if ok != 1 || conflict != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould capture one trace and reject the other : %d captured, %d rejected", failed, ok, conflict)
+			}
+			t.Logf("\t%s\tTest 0:\tShould capture one trace and reject the other.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the duration is too long.")
+		{
+			if resp := get(t, admin, "/debug/trace?seconds=3600", "", nil); resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("\t%s\tTest 1:\tShould receive a 400 : %d", failed, resp.StatusCode)
+			}
+			t.Logf("\t%s\tTest 1:\tShould receive a 400.", succeed)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/search"
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
+)
+
+// Pagination settings for the API.
+const (
+	defaultPerPage = 10
+	maxPerPage     = 100
+)
+
+// openAPI is the hand written OpenAPI 3 description of the API.
+//
+//go:embed openapi.json
+var openAPI []byte
+
+// apiResult is a search result in the API.
+type apiResult struct {
+	Engine    string     `json:"engine"`
+	Title     string     `json:"title"`
+	Link      string     `json:"link"`
+	Content   string     `json:"content"`
+	Snippet   string     `json:"snippet,omitempty"`
+	Score     float64    `json:"score"`
+	Published *time.Time `json:"published,omitempty"`
+}
+
+// apiStatus is the status of the search against a single engine.
+type apiStatus struct {
+	Name       string  `json:"name"`
+	Title      string  `json:"title"`
+	State      string  `json:"state"`
+	Results    int     `json:"results"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// apiResponse is the document returned by a search.
+type apiResponse struct {
+	Term       string      `json:"term"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"per_page"`
+	Total      int         `json:"total"`
+	TotalPages int         `json:"total_pages"`
+	TookMS     float64     `json:"took_ms"`
+	Results    []apiResult `json:"results"`
+	Engines    []apiStatus `json:"engines"`
+}
+
+// apiError is the document returned when a request fails.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// apiSearch handles GET /api/v1/search. The term is required and engines
+// is a comma separated list of provider names, which defaults to every
+// registered provider.
+func apiSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		respondError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	// Add a new counter for monitoring.
+	req.Add(1)
+
+	options, err := apiOptions(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	searchJSON(w, r, options)
+}
+
+// searchJSON performs the search and writes the requested page of the
+// results as JSON.
+func searchJSON(w http.ResponseWriter, r *http.Request, options search.Options) {
+	page, perPage, err := pagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	start := time.Now()
+	uid := telemetry.RequestID(r.Context())
+	sr := search.Submit(r.Context(), uid, options)
+
+	resp := apiResponse{
+		Term:       options.Term,
+		Page:       page,
+		PerPage:    perPage,
+		Total:      len(sr.Results),
+		TotalPages: (len(sr.Results) + perPage - 1) / perPage,
+		TookMS:     ms(time.Since(start)),
+		Results:    []apiResult{},
+		Engines:    make([]apiStatus, len(sr.Status)),
+	}
+
+	// Pick out the requested page of the ranked results.
+	from := min((page-1)*perPage, len(sr.Results))
+	to := min(from+perPage, len(sr.Results))
+	for _, res := range sr.Results[from:to] {
+		ar := apiResult{
+			Engine:  res.Engine,
+			Title:   res.Title,
+			Link:    res.Link,
+			Content: res.Content,
+			Snippet: res.Snippet,
+			Score:   res.Score,
+		}
+		if !res.Published.IsZero() {
+			ar.Published = &res.Published
+		}
+		resp.Results = append(resp.Results, ar)
+	}
+
+	for i, st := range sr.Status {
+		resp.Engines[i] = apiStatus{
+			Name:       st.Name,
+			Title:      st.Engine,
+			State:      st.State,
+			Results:    st.Results,
+			DurationMS: ms(st.Duration),
+		}
+		if st.Err != nil {
+			resp.Engines[i].Error = st.Err.Error()
+		}
+	}
+
+	respond(w, http.StatusOK, resp)
+}
+
+// apiOptions validates the query parameters of a search.
+func apiOptions(r *http.Request) (search.Options, error) {
+	q := r.URL.Query()
+
+	options := search.Options{
+		Term: strings.TrimSpace(q.Get("term")),
+	}
+	if options.Term == "" {
+		return search.Options{}, errors.New("term is required")
+	}
+
+	if engines := q.Get("engines"); engines != "" {
+		for _, name := range strings.Split(engines, ",") {
+			name = strings.TrimSpace(name)
+			if _, exists := search.Lookup(name); !exists {
+				return search.Options{}, fmt.Errorf("unknown engine %q", name)
+			}
+			options.Providers = append(options.Providers, name)
+		}
+	} else {
+		for _, p := range search.Providers() {
+			options.Providers = append(options.Providers, p.Name)
+		}
+	}
+
+	if first := q.Get("first"); first != "" {
+		v, err := strconv.ParseBool(first)
+		if err != nil {
+			return search.Options{}, errors.New("first must be a boolean")
+		}
+		options.First = v
+	}
+
+	return options, nil
+}
+
+// pagination validates the page and per_page parameters.
+func pagination(r *http.Request) (int, int, error) {
+	page, err := intParam(r.FormValue("page"), 1, 1, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("page %v", err)
+	}
+
+	perPage, err := intParam(r.FormValue("per_page"), defaultPerPage, 1, maxPerPage)
+	if err != nil {
+		return 0, 0, fmt.Errorf("per_page %v", err)
+	}
+
+	return page, perPage, nil
+}
+
+// intParam parses an integer parameter, returning the default when it's
+// not provided. A max of zero means there is no maximum.
+func intParam(s string, def int, lo int, hi int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	switch {
+	case err != nil:
+		return 0, errors.New("must be a number")
+	case v < lo:
+		return 0, fmt.Errorf("must be at least %d", lo)
+	case hi > 0 && v > hi:
+		return 0, fmt.Errorf("must be at most %d", hi)
+	}
+
+	return v, nil
+}
+
+// apiSpec handles GET /api/v1/openapi.json.
+func apiSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPI)
+}
+
+// wantsJSON reports if the client prefers JSON to HTML according to the
+// Accept header. Clients that don't send one get HTML.
+func wantsJSON(r *http.Request) bool {
+	best, bestQ := "", -1.0
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			q = v
+		}
+
+		switch mt {
+		case "application/json", "text/html":
+			if q > bestQ {
+				best, bestQ = mt, q
+			}
+		}
+	}
+
+	return best == "application/json"
+}
+
+// respond writes the value as a JSON document.
+func respond(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("encoding response", "error", err)
+	}
+}
+
+// respondError writes an error document.
+func respondError(w http.ResponseWriter, status int, msg string) {
+	respond(w, status, apiError{Error: msg})
+}
+
+// ms converts a duration to milliseconds.
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
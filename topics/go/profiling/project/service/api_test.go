@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/search"
+)
+
+const (
+	succeed = "\u2713"
+	failed  = "\u2717"
+)
+
+// fakeSearcher returns a fixed set of results or an error, after an
+// optional delay.
+type fakeSearcher struct {
+	results []search.Result
+	err     error
+	delay   time.Duration
+	started chan struct{} // Signaled when the search starts, if set.
+}
+
+func (f fakeSearcher) Search(ctx context.Context, uid string, term string) ([]search.Result, error) {
+	if f.started != nil {
+		select {
+		case f.started <- struct{}{}:
+		default:
+		}
+	}
+	time.Sleep(f.delay)
+	return f.results, f.err
+}
+
+// slowStarted is signaled when a search against the slow provider starts.
+var slowStarted = make(chan struct{}, 1)
+
+// init registers the fake providers used by the tests.
+func init() {
+	var results []search.Result
+	for i := range 15 {
+		results = append(results, search.Result{Engine: "Fake", Title: fmt.Sprintf("result %d", i), Score: float64(15 - i)})
+	}
+
+	search.MustRegister(search.Provider{Name: "fake", Title: "Fake", New: func(search.Provider) search.Searcher {
+		return fakeSearcher{results: results}
+	}})
+	search.MustRegister(search.Provider{Name: "broken", Title: "Broken", New: func(search.Provider) search.Searcher {
+		return fakeSearcher{err: errors.New("feed is down")}
+	}})
+	search.MustRegister(search.Provider{Name: "slow", Title: "Slow", New: func(search.Provider) search.Searcher {
+		return fakeSearcher{results: []search.Result{{Title: "slow"}}, delay: 200 * time.Millisecond, started: slowStarted}
+	}})
+}
+
+// get performs a request against the service and decodes the response.
+func get(t *testing.T, srv *httptest.Server, path string, accept string, v interface{}) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	if err != nil {
+		t.Fatalf("\t%s\tShould be able to create the request : %v", failed, err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("\t%s\tShould be able to make the request : %v", failed, err)
+	}
+	defer resp.Body.Close()
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("\t%s\tShould receive a JSON document : %v", failed, err)
+		}
+	}
+
+	return resp
+}
+
+// TestAPISearch validates the search endpoint of the JSON API.
+func TestAPISearch(t *testing.T) {
+	srv := httptest.NewServer(New(Config{}))
+	defer srv.Close()
+
+	t.Log("Given the need to search using the JSON API.")
+	{
+		t.Log("\tTest 0:\tWhen searching the engines.")
+		{
+			var doc apiResponse
+			resp := get(t, srv, "/api/v1/search?term=go&engines=fake,broken", "", &doc)
+
+			if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+				t.Fatalf("\t%s\tTest 0:\tShould receive a JSON 200 : %d %s", failed, resp.StatusCode, resp.Header.Get("Content-Type"))
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive a JSON 200.", succeed)
+
+			if doc.Total != 15 || doc.TotalPages != 2 || len(doc.Results) != 10 || doc.Results[0].Title != "result 0" {
+				t.Fatalf("\t%s\tTest 0:\tShould receive the first page of ranked results : %+v", failed, doc)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive the first page of ranked results.", succeed)
+
+			if len(doc.Engines) != 2 || doc.Engines[0].Name != "fake" || doc.Engines[0].State != search.StateOK ||
+				doc.Engines[1].State != search.StateError || doc.Engines[1].Error != "feed is down" {
+				t.Fatalf("\t%s\tTest 0:\tShould receive the status of every engine : %+v", failed, doc.Engines)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive the status of every engine.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen asking for the last page.")
+		{
+			var doc apiResponse
+			get(t, srv, "/api/v1/search?term=go&engines=fake&page=2&per_page=10", "", &doc)
+
+			if doc.Page != 2 || len(doc.Results) != 5 || doc.Results[0].Title != "result 10" {
+				t.Fatalf("\t%s\tTest 1:\tShould receive the remaining results : %+v", failed, doc)
+			}
+			t.Logf("\t%s\tTest 1:\tShould receive the remaining results.", succeed)
+
+			get(t, srv, "/api/v1/search?term=go&engines=fake&page=3", "", &doc)
+			if len(doc.Results) != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould receive no results past the last page : %+v", failed, doc.Results)
+			}
+			t.Logf("\t%s\tTest 1:\tShould receive no results past the last page.", succeed)
+		}
+
+		tt := []struct {
+			query string
+			msg   string
+		}{
+			{"engines=fake", "term is required"},
+			{"term=go&engines=nope", `unknown engine "nope"`},
+			{"term=go&engines=fake&page=0", "page must be at least 1"},
+			{"term=go&engines=fake&per_page=500", "per_page must be at most 100"},
+			{"term=go&engines=fake&first=maybe", "first must be a boolean"},
+		}
+
+		for i, tst := range tt {
+			i += 2
+			t.Logf("\tTest %d:\tWhen the parameters are %q.", i, tst.query)
+			{
+				var doc apiError
+				resp := get(t, srv, "/api/v1/search?"+tst.query, "", &doc)
+
+				if resp.StatusCode != http.StatusBadRequest || doc.Error != tst.msg {
+					t.Fatalf("\t%s\tTest %d:\tShould receive a 400 with %q : %d %q", failed, i, tst.msg, resp.StatusCode, doc.Error)
+				}
+				t.Logf("\t%s\tTest %d:\tShould receive a 400 with %q.", succeed, i, tst.msg)
+			}
+		}
+	}
+}
+
+// TestContentNegotiation validates the search page returns JSON to
+// clients that ask for it.
+func TestContentNegotiation(t *testing.T) {
+	srv := httptest.NewServer(New(Config{}))
+	defer srv.Close()
+
+	q := url.Values{"term": {"go"}, "fake": {"on"}}
+	path := "/search?" + q.Encode()
+
+	tt := []struct {
+		accept string
+		json   bool
+	}{
+		{"application/json", true},
+		{"text/html;q=0.5, application/json", true},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"", false},
+	}
+
+	t.Log("Given the need to negotiate the format of the search page.")
+	{
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen the Accept header is %q.", i, tst.accept)
+			{
+				resp := get(t, srv, path, tst.accept, nil)
+				isJSON := strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+
+				if resp.StatusCode != http.StatusOK || isJSON != tst.json {
+					t.Fatalf("\t%s\tTest %d:\tShould receive JSON %v : %d %s", failed, i, tst.json, resp.StatusCode, resp.Header.Get("Content-Type"))
+				}
+				t.Logf("\t%s\tTest %d:\tShould receive JSON %v.", succeed, i, tst.json)
+			}
+		}
+	}
+}
+
+// TestOpenAPI validates the OpenAPI document is served and describes
+// the search endpoint.
+func TestOpenAPI(t *testing.T) {
+	srv := httptest.NewServer(New(Config{}))
+	defer srv.Close()
+
+	t.Log("Given the need to describe the JSON API.")
+	{
+		t.Log("\tTest 0:\tWhen asking for the OpenAPI document.")
+		{
+			var doc struct {
+				OpenAPI string                     `json:"openapi"`
+				Paths   map[string]json.RawMessage `json:"paths"`
+			}
+			resp := get(t, srv, "/api/v1/openapi.json", "", &doc)
+
+			if resp.StatusCode != http.StatusOK || !strings.HasPrefix(doc.OpenAPI, "3.") {
+				t.Fatalf("\t%s\tTest 0:\tShould receive an OpenAPI 3 document : %d %q", failed, resp.StatusCode, doc.OpenAPI)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive an OpenAPI 3 document.", succeed)
+
+			if _, exists := doc.Paths["/api/v1/search"]; !exists {
+				t.Fatalf("\t%s\tTest 0:\tShould describe the search endpoint.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould describe the search endpoint.", succeed)
+		}
+	}
+}
@@ -7,15 +7,22 @@ import (
 	"net/http"
 
 	"github.com/ardanlabs/gotraining/topics/go/profiling/project/search"
-	"github.com/pborman/uuid"
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
 )
 
 // req keeps track of the number of requests.
 var req = expvar.NewInt("requests")
 
+// debugView is the extra detail shown about a search in debug mode.
+type debugView struct {
+	RequestID string
+	TraceID   string
+	Waterfall []bar
+}
+
 // handler handles the search route processing.
 func handler(w http.ResponseWriter, r *http.Request) {
-	uid := uuid.New()
+	uid := telemetry.RequestID(r.Context())
 
 	// Add a new counter for monitoring.
 	req.Add(1)
@@ -23,14 +30,35 @@ func handler(w http.ResponseWriter, r *http.Request) {
 	// Capture all the form values.
 	fv, options := formValues(r)
 
+	// Clients that ask for JSON get the same document as the API.
+	if wantsJSON(r) {
+		if options.Term == "" {
+			respondError(w, http.StatusBadRequest, "term is required")
+			return
+		}
+		searchJSON(w, r, options)
+		return
+	}
+
 	// If this is a post, perform a search.
-	var results []search.Result
+	var resp *search.Response
+	var debug *debugView
 	if r.Method == "POST" && options.Term != "" {
-		results = search.Submit(uid, options)
+		sr := search.Submit(r.Context(), uid, options)
+		resp = &sr
+
+		// Show how long every engine and feed took.
+		if fv["debug"] != "" {
+			debug = &debugView{
+				RequestID: uid,
+				TraceID:   telemetry.FromContext(r.Context()).TraceID(),
+				Waterfall: waterfall(telemetry.Finished(r.Context())),
+			}
+		}
 	}
 
 	// Render the search page.
-	markup := render(fv, results)
+	markup := render(fv, resp, debug)
 
 	// Write the final markup as the response.
 	fmt.Fprint(w, string(markup))
@@ -44,26 +72,21 @@ func formValues(r *http.Request) (map[string]interface{}, search.Options) {
 	fv["term"] = r.FormValue("term")
 	options.Term = r.FormValue("term")
 
-	if r.FormValue("cnn") == "on" {
-		fv["cnn"] = "checked"
-		options.CNN = true
-	} else {
-		fv["cnn"] = ""
-	}
-
-	if r.FormValue("nyt") == "on" {
-		fv["nyt"] = "checked"
-		options.NYT = true
-	} else {
-		fv["nyt"] = ""
-	}
-
-	if r.FormValue("bbc") == "on" {
-		fv["bbc"] = "checked"
-		options.BBC = true
-	} else {
-		fv["bbc"] = ""
+	// Build a checkbox for every registered provider.
+	var providers []map[string]interface{}
+	for _, p := range search.Providers() {
+		checked := r.FormValue(p.Name) == "on"
+		if checked {
+			options.Providers = append(options.Providers, p.Name)
+		}
+
+		providers = append(providers, map[string]interface{}{
+			"Name":    p.Name,
+			"Title":   p.Title,
+			"Checked": checked,
+		})
 	}
+	fv["providers"] = providers
 
 	if r.FormValue("first") == "on" {
 		fv["first"] = "checked"
@@ -72,15 +95,21 @@ func formValues(r *http.Request) (map[string]interface{}, search.Options) {
 		fv["first"] = ""
 	}
 
+	if r.FormValue("debug") == "on" {
+		fv["debug"] = "checked"
+	} else {
+		fv["debug"] = ""
+	}
+
 	return fv, options
 }
 
 // render generates the HTML response for this route.
-func render(fv map[string]interface{}, results []search.Result) []byte {
+func render(fv map[string]interface{}, resp *search.Response, debug *debugView) []byte {
 
 	// Generate the markup for the results template.
-	if results != nil {
-		vars := map[string]interface{}{"Items": results}
+	if resp != nil {
+		vars := map[string]interface{}{"Items": resp.Results, "Status": resp.Status, "Debug": debug}
 		markup := executeTemplate("results", vars)
 		fv["Results"] = template.HTML(string(markup))
 	}
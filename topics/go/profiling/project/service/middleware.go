@@ -0,0 +1,98 @@
+package service
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
+	"github.com/pborman/uuid"
+)
+
+// requestIDHeader carries the request ID. An ID sent by the client, such as
+// one set by a proxy, is used so the logs can be matched up.
+const requestIDHeader = "X-Request-ID"
+
+// traced wraps the handler for the route so every request gets a request ID,
+// a root span and a logger tagged with both, which are carried by the
+// request's context. The request is logged once it has been handled.
+func traced(rec *telemetry.Recorder, route string, h http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		ctx, span := rec.Start(r.Context(), r.Method+" "+route,
+			slog.String("http.method", r.Method),
+			slog.String("http.route", route),
+			slog.String("request.id", id),
+		)
+
+		log := slog.Default().With("request_id", id, "trace_id", span.TraceID())
+		ctx = telemetry.WithRequestID(ctx, id)
+		ctx = telemetry.WithLogger(ctx, log)
+
+		sw := statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h(&sw, r.WithContext(ctx))
+
+		span.SetAttributes(slog.Int("http.status_code", sw.status))
+		if sw.status >= 500 {
+			span.SetError(errorStatus(sw.status))
+		}
+		span.End()
+
+		log.Info("request", "method", r.Method, "path", r.URL.Path, "status", sw.status, "duration", time.Since(start))
+	})
+}
+
+// validRequestID reports if the ID from a client is safe to log and echo
+// back: not too long and only printable characters without spaces.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// errorStatus reports a response status as an error.
+type errorStatus int
+
+// Error implements the error interface.
+func (e errorStatus) Error() string {
+	return http.StatusText(int(e))
+}
+
+// statusWriter records the status code written by a handler.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+// WriteHeader records the status code before writing it.
+func (sw *statusWriter) WriteHeader(code int) {
+	if !sw.wrote {
+		sw.status, sw.wrote = code, true
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+// Write marks the header as written.
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.wrote = true
+	return sw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
+)
+
+// spanSink is an exporter that keeps the spans.
+type spanSink struct {
+	mu    sync.Mutex
+	spans []telemetry.SpanData
+}
+
+func (s *spanSink) Export(ctx context.Context, service string, spans []telemetry.SpanData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.spans = append(s.spans, spans...)
+	return nil
+}
+
+// TestTraced validates every request gets a request ID and a trace with a
+// span for the search and every engine.
+func TestTraced(t *testing.T) {
+	var sink spanSink
+	rec := telemetry.NewRecorder("search", &sink)
+
+	srv := httptest.NewServer(New(Config{Recorder: rec}))
+	defer srv.Close()
+
+	t.Log("Given the need to follow a request through the search pipeline.")
+	{
+		t.Log("\tTest 0:\tWhen the client doesn't send a request ID.")
+		{
+			resp := get(t, srv, "/api/v1/search?term=go&engines=fake,broken", "", nil)
+
+			id := resp.Header.Get("X-Request-ID")
+			if id == "" {
+				t.Fatalf("\t%s\tTest 0:\tShould receive a request ID.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive a request ID : %s", succeed, id)
+
+			// The root span ends after the response is sent, closing the
+			// server waits for the handler to return.
+			srv.Close()
+			rec.Close()
+
+			spans := map[string]telemetry.SpanData{}
+			for _, sd := range sink.spans {
+				spans[sd.Name] = sd
+			}
+
+			root, found := spans["GET /api/v1/search"]
+			if !found || root.ParentSpanID != "" {
+				t.Fatalf("\t%s\tTest 0:\tShould record a root span for the request : %v", failed, sink.spans)
+			}
+			t.Logf("\t%s\tTest 0:\tShould record a root span for the request.", succeed)
+
+			submit := spans["search.Submit"]
+			if submit.ParentSpanID != root.SpanID || submit.TraceID != root.TraceID {
+				t.Fatalf("\t%s\tTest 0:\tShould record the search under the request : %+v", failed, submit)
+			}
+			t.Logf("\t%s\tTest 0:\tShould record the search under the request.", succeed)
+
+			for name, status := range map[string]telemetry.StatusCode{"search fake": telemetry.StatusOK, "search broken": telemetry.StatusError} {
+				sd := spans[name]
+				if sd.ParentSpanID != submit.SpanID || sd.Status != status {
+					t.Fatalf("\t%s\tTest 0:\tShould record %q under the search with status %d : %+v", failed, name, status, sd)
+				}
+				t.Logf("\t%s\tTest 0:\tShould record %q under the search with status %d.", succeed, name, status)
+			}
+		}
+
+		srv := httptest.NewServer(New(Config{}))
+		defer srv.Close()
+
+		for i, tst := range []struct {
+			sent string
+			echo bool
+		}{
+			{"proxy-1234", true},
+			{"has spaces", false},
+		} {
+			t.Logf("\tTest %d:\tWhen the client sends the request ID %q.", i+1, tst.sent)
+			{
+				req, _ := http.NewRequest(http.MethodGet, srv.URL+"/search", nil)
+				req.Header.Set("X-Request-ID", tst.sent)
+
+				resp, err := srv.Client().Do(req)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to make the request : %v", failed, i+1, err)
+				}
+				resp.Body.Close()
+
+				if got := resp.Header.Get("X-Request-ID"); (got == tst.sent) != tst.echo || got == "" {
+					t.Fatalf("\t%s\tTest %d:\tShould echo the ID only if it's valid : got %q", failed, i+1, got)
+				}
+				t.Logf("\t%s\tTest %d:\tShould echo the ID only if it's valid.", succeed, i+1)
+			}
+		}
+	}
+}
+
+// TestDebugWaterfall validates the search page shows the timing of every
+// engine in debug mode.
+func TestDebugWaterfall(t *testing.T) {
+	srv := httptest.NewServer(New(Config{}))
+	defer srv.Close()
+
+	t.Log("Given the need to see where the time of a search went.")
+	{
+		for i, debug := range []bool{true, false} {
+			t.Logf("\tTest %d:\tWhen searching with debug %v.", i, debug)
+			{
+				form := url.Values{"term": {"go"}, "fake": {"on"}, "broken": {"on"}}
+				if debug {
+					form.Set("debug", "on")
+				}
+
+				resp, err := srv.Client().PostForm(srv.URL+"/search", form)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to search : %v", failed, i, err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+
+				page := string(body)
+				shown := strings.Contains(page, "waterfall-bar") && strings.Contains(page, "search fake") && strings.Contains(page, `class="span-error"`)
+				if shown != debug {
+					t.Fatalf("\t%s\tTest %d:\tShould show the waterfall only in debug mode : shown %v", failed, i, shown)
+				}
+				t.Logf("\t%s\tTest %d:\tShould show the waterfall only in debug mode.", succeed, i)
+
+				if debug && strings.Contains(page, "ZgotmplZ") {
+					t.Fatalf("\t%s\tTest %d:\tShould lay out the bars : %s", failed, i, page)
+				}
+			}
+		}
+	}
+}
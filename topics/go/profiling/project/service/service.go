@@ -2,65 +2,157 @@
 package service
 
 import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
+	"net"
 	"net/http"
-	"os"
-	"os/signal"
 	"time"
 
-	"github.com/braintree/manners"
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
 )
 
-// init binds the routes and handlers for the web service.
-func init() {
+// static contains the CSS and other files served under /static/.
+//
+//go:embed static
+var static embed.FS
+
+// Config provides the settings for the web service.
+type Config struct {
+	Addr            string        // Address to listen on, defaults to 0.0.0.0:5000.
+	AdminAddr       string        // Address for the admin listener, empty disables it.
+	ReadTimeout     time.Duration // Defaults to 10 seconds.
+	WriteTimeout    time.Duration // Defaults to 31 seconds.
+	ShutdownTimeout time.Duration // Time allowed for requests to finish, defaults to 60 seconds.
+
+	// Recorder collects the spans of every request. Defaults to a recorder
+	// that only keeps them in memory for the debug view.
+	Recorder *telemetry.Recorder
+}
+
+// defaults returns the config with the zero values replaced by defaults.
+func (cfg Config) defaults() Config {
+	if cfg.Addr == "" {
+		cfg.Addr = "0.0.0.0:5000"
+	}
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = 10 * time.Second
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 31 * time.Second
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		cfg.ShutdownTimeout = 60 * time.Second
+	}
+	if cfg.Recorder == nil {
+		cfg.Recorder = telemetry.NewRecorder("search", nil)
+	}
+	return cfg
+}
+
+// New constructs the handler with the routes for the web service. The
+// search routes are traced with the configured recorder.
+func New(cfg Config) http.Handler {
+	cfg = cfg.defaults()
+	mux := http.NewServeMux()
 
 	// Setup a route for our static files.
 	//
-	// Because our static directory is set as the root of the FileSystem,
-	// we need to strip off the /static/ prefix from the request path
-	// before searching the FileSystem for the given file.
-	fs := http.FileServer(http.Dir("static"))
-	http.Handle("/static/", http.StripPrefix("/static/", fs))
+	// Because the static directory is the root of the FileSystem, we need
+	// to strip off the /static/ prefix from the request path before
+	// searching the FileSystem for the given file.
+	root, _ := fs.Sub(static, "static")
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServerFS(root)))
 
 	// Setup a route for the home page.
-	http.HandleFunc("/search", handler)
+	mux.Handle("/search", traced(cfg.Recorder, "/search", handler))
+
+	// Setup the routes for the JSON API.
+	mux.Handle("/api/v1/search", traced(cfg.Recorder, "/api/v1/search", apiSearch))
+	mux.HandleFunc("/api/v1/openapi.json", apiSpec)
+
+	return mux
 }
 
-// Run binds the service to a port and starts listening for requests.
-func Run() {
-	host := "0.0.0.0:5000"
-	readTimeout := 10 * time.Second
-	writeTimeout := 31 * time.Second
+// Run binds the service to the configured address and serves requests
+// until the context is cancelled. The admin listener is started as well
+// when it's configured. The servers are then shut down, giving the requests
+// in flight until the shutdown timeout to finish.
+func Run(ctx context.Context, cfg Config) error {
+	cfg = cfg.defaults()
 
 	// Create a new server and set timeout values.
-	s := manners.NewWithServer(&http.Server{
-		Addr:           host,
-		Handler:        http.DefaultServeMux,
-		ReadTimeout:    readTimeout,
-		WriteTimeout:   writeTimeout,
-		MaxHeaderBytes: 1 << 20,
-	})
-
-	// Support for shutting down cleanly.
-	go func() {
-
-		// Listen for an interrupt signal from the OS.
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt)
-		<-sigChan
-
-		// We have been asked to shutdown the server.
-		log.Println("Starting shutdown...")
-		s.Close()
-
-		// For now until I deal with manners handling static files.
+	servers := []*http.Server{
+		{
+			Addr:           cfg.Addr,
+			Handler:        New(cfg),
+			ReadTimeout:    cfg.ReadTimeout,
+			WriteTimeout:   cfg.WriteTimeout,
+			MaxHeaderBytes: 1 << 20,
+		},
+	}
+
+	// The admin server needs to write for as long as a profile or trace
+	// is being captured.
+	if cfg.AdminAddr != "" {
+		servers = append(servers, &http.Server{
+			Addr:           cfg.AdminAddr,
+			Handler:        NewAdmin(),
+			ReadTimeout:    cfg.ReadTimeout,
+			WriteTimeout:   (maxTraceSeconds + 10) * time.Second,
+			MaxHeaderBytes: 1 << 20,
+		})
+	}
+
+	// Bind every listener before serving so a bad address is reported
+	// without starting anything.
+	listeners := make([]net.Listener, len(servers))
+	for i, srv := range servers {
+		l, err := net.Listen("tcp", srv.Addr)
+		if err != nil {
+			for _, l := range listeners[:i] {
+				l.Close()
+			}
+			return err
+		}
+		listeners[i] = l
+	}
+
+	serverErrors := make(chan error, len(servers))
+	for i, srv := range servers {
 		go func() {
-			time.Sleep(time.Second * 60)
-			log.Println("Killed Service")
-			os.Exit(1)
+			log.Println("Listening on:", listeners[i].Addr())
+			serverErrors <- srv.Serve(listeners[i])
 		}()
-	}()
+	}
+
+	var err error
+	select {
+	case err = <-serverErrors:
+	case <-ctx.Done():
+	}
+
+	// We have been asked to shutdown the servers, or one of them failed.
+	log.Println("Starting shutdown...")
+
+	sctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	errs := []error{err}
+	for _, srv := range servers {
+		if err := srv.Shutdown(sctx); err != nil {
+			srv.Close()
+			errs = append(errs, fmt.Errorf("shutting down %s: %w", srv.Addr, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
 
-	log.Println("Listening on:", host)
-	s.ListenAndServe()
+	log.Println("Shutdown complete")
+	return nil
 }
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNew validates the embedded assets are served.
+func TestNew(t *testing.T) {
+	srv := httptest.NewServer(New(Config{}))
+	defer srv.Close()
+
+	tt := []struct {
+		path        string
+		contentType string
+	}{
+		{"/static/css/main.css", "text/css"},
+		{"/search", "text/html"},
+	}
+
+	t.Log("Given the need to serve the web site from any working directory.")
+	{
+		for i, tst := range tt {
+			t.Logf("\tTest %d:\tWhen requesting %s.", i, tst.path)
+			{
+				resp := get(t, srv, tst.path, "", nil)
+
+				if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), tst.contentType) {
+					t.Fatalf("\t%s\tTest %d:\tShould receive a 200 with %s : %d %s", failed, i, tst.contentType, resp.StatusCode, resp.Header.Get("Content-Type"))
+				}
+				t.Logf("\t%s\tTest %d:\tShould receive a 200 with %s.", succeed, i, tst.contentType)
+			}
+		}
+	}
+}
+
+// TestRun validates the server finishes the requests in flight when it's
+// shut down.
+func TestRun(t *testing.T) {
+	// Find a free port for the server.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("\t%s\tShould be able to find a free port : %v", failed, err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(ctx, Config{Addr: addr, ShutdownTimeout: 5 * time.Second})
+	}()
+
+	t.Log("Given the need to shutdown the server cleanly.")
+	{
+		t.Log("\tTest 0:\tWhen a request is in flight.")
+		{
+			status := make(chan int, 1)
+			go func() {
+				for range 100 {
+					resp, err := http.Get("http://" + addr + "/api/v1/search?term=go&engines=slow")
+					if err != nil {
+						time.Sleep(10 * time.Millisecond)
+						continue
+					}
+					resp.Body.Close()
+					status <- resp.StatusCode
+					return
+				}
+				status <- 0
+			}()
+
+			select {
+			case <-slowStarted:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("\t%s\tTest 0:\tShould start the request.", failed)
+			}
+			cancel()
+
+			if code := <-status; code != http.StatusOK {
+				t.Fatalf("\t%s\tTest 0:\tShould finish the request : got %d", failed, code)
+			}
+			t.Logf("\t%s\tTest 0:\tShould finish the request.", succeed)
+
+			if err := <-done; err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould shutdown without an error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould shutdown without an error.", succeed)
+
+			if _, err := http.Get("http://" + addr + "/search"); err == nil {
+				t.Fatalf("\t%s\tTest 0:\tShould stop accepting requests.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould stop accepting requests.", succeed)
+		}
+	}
+}
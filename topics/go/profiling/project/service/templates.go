@@ -4,29 +4,31 @@ package service
 
 import (
 	"bytes"
+	"embed"
 	"html/template"
-	"io/ioutil"
 	"log"
-	"os"
 )
 
+// viewFiles contains the HTML templates, which are embedded so the service
+// can run from any working directory.
+//
+//go:embed views
+var viewFiles embed.FS
+
 // views contains a map of templates for rendering views.
 var views = make(map[string]*template.Template)
 
 // init loads the existing templates for use by routing code.
 func init() {
-	// In order for the endpoint tests to run this needs to be
-	// physically located. Trying to avoid configuration for now.
-	pwd, _ := os.Getwd()
-	loadTemplate("layout", pwd+"/views/basic-layout.html")
-	loadTemplate("search", pwd+"/views/search.html")
-	loadTemplate("results", pwd+"/views/results.html")
+	loadTemplate("layout", "views/basic-layout.html")
+	loadTemplate("search", "views/search.html")
+	loadTemplate("results", "views/results.html")
 }
 
 // loadTemplate reads the specified template file for use.
 func loadTemplate(name string, path string) {
 	// Read the html template file.
-	data, err := ioutil.ReadFile(path)
+	data, err := viewFiles.ReadFile(path)
 	if err != nil {
 		log.Fatalln(err)
 	}
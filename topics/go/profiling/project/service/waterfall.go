@@ -0,0 +1,83 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
+)
+
+// bar is a row of the timing waterfall shown in debug mode. Offset and Width
+// are percentages of the time taken by the whole search.
+type bar struct {
+	Label    string
+	Indent   int
+	Offset   float64
+	Width    float64
+	Duration time.Duration
+	Failed   bool
+}
+
+// waterfall lays out the spans under the search.Submit span, so the time
+// every engine and feed took can be compared. The spans of engines that
+// were still running when the search finished are missing.
+func waterfall(spans []telemetry.SpanData) []bar {
+	i := slices.IndexFunc(spans, func(sd telemetry.SpanData) bool {
+		return sd.Name == "search.Submit"
+	})
+	if i == -1 {
+		return nil
+	}
+	root := spans[i]
+
+	total := root.Duration()
+	if total <= 0 {
+		total = 1
+	}
+
+	// Parents start before their children, so walking the spans in the
+	// order they started finds the depth of every parent first.
+	spans = slices.Clone(spans)
+	slices.SortStableFunc(spans, func(a, b telemetry.SpanData) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	depth := map[string]int{root.SpanID: 0}
+	var bars []bar
+	for _, sd := range spans {
+		d, found := depth[sd.ParentSpanID]
+		switch {
+		case sd.SpanID == root.SpanID:
+			d = 0
+		case found:
+			d++
+		default:
+			continue
+		}
+		depth[sd.SpanID] = d
+
+		label := sd.Name
+		for _, a := range sd.Attributes {
+			if a.Key == "feed.url" {
+				label = a.Value.String()
+			}
+		}
+
+		bars = append(bars, bar{
+			Label:    label,
+			Indent:   d * 16,
+			Offset:   percent(sd.Start.Sub(root.Start), total),
+			Width:    max(percent(sd.Duration(), total), 0.5),
+			Duration: sd.Duration().Round(time.Microsecond),
+			Failed:   sd.Status == telemetry.StatusError,
+		})
+	}
+
+	return bars
+}
+
+// percent returns d as a percentage of total, rounded to two places.
+func percent(d time.Duration, total time.Duration) float64 {
+	p := float64(d) / float64(total) * 100
+	return float64(int(p*100)) / 100
+}
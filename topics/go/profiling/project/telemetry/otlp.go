@@ -0,0 +1,327 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// The types below are the subset of the OTLP/JSON trace format the recorder
+// produces. IDs are hex encoded and 64 bit integers are strings, as the
+// specification requires.
+type (
+	otlpTraces struct {
+		ResourceSpans []resourceSpans `json:"resourceSpans"`
+	}
+
+	resourceSpans struct {
+		Resource   resource     `json:"resource"`
+		ScopeSpans []scopeSpans `json:"scopeSpans"`
+	}
+
+	resource struct {
+		Attributes []keyValue `json:"attributes"`
+	}
+
+	scopeSpans struct {
+		Scope scope      `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+
+	scope struct {
+		Name string `json:"name"`
+	}
+
+	otlpSpan struct {
+		TraceID           string     `json:"traceId"`
+		SpanID            string     `json:"spanId"`
+		ParentSpanID      string     `json:"parentSpanId,omitempty"`
+		Name              string     `json:"name"`
+		Kind              SpanKind   `json:"kind"`
+		StartTimeUnixNano string     `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+		Attributes        []keyValue `json:"attributes,omitempty"`
+		Status            otlpStatus `json:"status"`
+	}
+
+	otlpStatus struct {
+		Code    StatusCode `json:"code,omitempty"`
+		Message string     `json:"message,omitempty"`
+	}
+
+	keyValue struct {
+		Key   string   `json:"key"`
+		Value anyValue `json:"value"`
+	}
+
+	anyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+	}
+)
+
+// scopeName identifies the recorder as the source of the spans.
+const scopeName = "github.com/ardanlabs/gotraining/topics/go/profiling/project/telemetry"
+
+// MarshalOTLP encodes the spans as an OTLP/JSON trace export request.
+func MarshalOTLP(service string, spans []SpanData) ([]byte, error) {
+	ss := make([]otlpSpan, len(spans))
+	for i, sd := range spans {
+		ss[i] = otlpSpan{
+			TraceID:           sd.TraceID,
+			SpanID:            sd.SpanID,
+			ParentSpanID:      sd.ParentSpanID,
+			Name:              sd.Name,
+			Kind:              sd.Kind,
+			StartTimeUnixNano: strconv.FormatInt(sd.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(sd.End.UnixNano(), 10),
+			Attributes:        encodeAttrs(sd.Attributes),
+			Status:            otlpStatus{Code: sd.Status, Message: sd.StatusMessage},
+		}
+	}
+
+	doc := otlpTraces{
+		ResourceSpans: []resourceSpans{
+			{
+				Resource:   resource{Attributes: encodeAttrs([]slog.Attr{slog.String("service.name", service)})},
+				ScopeSpans: []scopeSpans{{Scope: scope{Name: scopeName}, Spans: ss}},
+			},
+		},
+	}
+
+	return json.Marshal(doc)
+}
+
+// UnmarshalOTLP decodes an OTLP/JSON trace export request.
+func UnmarshalOTLP(data []byte) ([]SpanData, error) {
+	var doc otlpTraces
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var spans []SpanData
+	for _, rs := range doc.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				start, err := parseNano(s.StartTimeUnixNano)
+				if err != nil {
+					return nil, fmt.Errorf("span %s: start: %w", s.SpanID, err)
+				}
+				end, err := parseNano(s.EndTimeUnixNano)
+				if err != nil {
+					return nil, fmt.Errorf("span %s: end: %w", s.SpanID, err)
+				}
+
+				spans = append(spans, SpanData{
+					TraceID:       s.TraceID,
+					SpanID:        s.SpanID,
+					ParentSpanID:  s.ParentSpanID,
+					Name:          s.Name,
+					Kind:          s.Kind,
+					Start:         start,
+					End:           end,
+					Attributes:    decodeAttrs(s.Attributes),
+					Status:        s.Status.Code,
+					StatusMessage: s.Status.Message,
+				})
+			}
+		}
+	}
+
+	return spans, nil
+}
+
+// encodeAttrs converts the attributes to OTLP key values. Kinds OTLP has
+// no type for are sent as strings.
+func encodeAttrs(attrs []slog.Attr) []keyValue {
+	kvs := make([]keyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v anyValue
+		switch val := a.Value.Resolve(); val.Kind() {
+		case slog.KindInt64:
+			s := strconv.FormatInt(val.Int64(), 10)
+			v.IntValue = &s
+		case slog.KindFloat64:
+			f := val.Float64()
+			v.DoubleValue = &f
+		case slog.KindBool:
+			b := val.Bool()
+			v.BoolValue = &b
+		default:
+			s := val.String()
+			v.StringValue = &s
+		}
+		kvs = append(kvs, keyValue{Key: a.Key, Value: v})
+	}
+	return kvs
+}
+
+// decodeAttrs converts OTLP key values back to attributes.
+func decodeAttrs(kvs []keyValue) []slog.Attr {
+	var attrs []slog.Attr
+	for _, kv := range kvs {
+		switch v := kv.Value; {
+		case v.IntValue != nil:
+			n, _ := strconv.ParseInt(*v.IntValue, 10, 64)
+			attrs = append(attrs, slog.Int64(kv.Key, n))
+		case v.DoubleValue != nil:
+			attrs = append(attrs, slog.Float64(kv.Key, *v.DoubleValue))
+		case v.BoolValue != nil:
+			attrs = append(attrs, slog.Bool(kv.Key, *v.BoolValue))
+		case v.StringValue != nil:
+			attrs = append(attrs, slog.String(kv.Key, *v.StringValue))
+		}
+	}
+	return attrs
+}
+
+// parseNano parses a time in nanoseconds since the epoch.
+func parseNano(s string) (time.Time, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, n), nil
+}
+
+// =============================================================================
+
+// FileExporter appends each batch of spans to a file as a line of OTLP/JSON,
+// the format written by the OpenTelemetry Collector's file exporter.
+type FileExporter struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// NewFileExporter opens the file for appending, creating it if needed.
+func NewFileExporter(path string) (*FileExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{f: f}, nil
+}
+
+// Export implements the Exporter interface.
+func (fe *FileExporter) Export(ctx context.Context, service string, spans []SpanData) error {
+	data, err := MarshalOTLP(service, spans)
+	if err != nil {
+		return err
+	}
+
+	fe.mu.Lock()
+	defer fe.mu.Unlock()
+
+	_, err = fe.f.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file.
+func (fe *FileExporter) Close() error {
+	return fe.f.Close()
+}
+
+// HTTPExporter posts each batch of spans as OTLP/JSON to a collector, such
+// as http://localhost:4318/v1/traces.
+type HTTPExporter struct {
+	URL    string
+	Client *http.Client // Defaults to http.DefaultClient.
+}
+
+// Export implements the Exporter interface.
+func (he *HTTPExporter) Export(ctx context.Context, service string, spans []SpanData) error {
+	data, err := MarshalOTLP(service, spans)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, he.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := he.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("exporting to %s: %s", he.URL, resp.Status)
+	}
+	return nil
+}
+
+// =============================================================================
+
+// Collector is a stand in for an OpenTelemetry Collector during development.
+// It accepts OTLP/JSON on POST /v1/traces and keeps the spans it receives.
+type Collector struct {
+	// OnSpans, if set, is called with each batch received.
+	OnSpans func(spans []SpanData)
+
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/traces" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	spans, err := UnmarshalOTLP(data)
+	if err != nil {
+		http.Error(w, "decoding spans: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	c.spans = append(c.spans, spans...)
+	c.mu.Unlock()
+
+	if c.OnSpans != nil {
+		c.OnSpans(spans)
+	}
+
+	// An empty partial success, as the specification asks for.
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+}
+
+// Spans returns the spans received so far.
+func (c *Collector) Spans() []SpanData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]SpanData(nil), c.spans...)
+}
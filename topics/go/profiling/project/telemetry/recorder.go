@@ -0,0 +1,170 @@
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Exporter sends finished spans somewhere, such as a file or a collector.
+type Exporter interface {
+	Export(ctx context.Context, service string, spans []SpanData) error
+}
+
+const (
+	// keep is the number of finished spans kept for looking up traces.
+	keep = 4096
+
+	// batchSize is the number of spans that triggers an export.
+	batchSize = 512
+
+	// flushInterval is the longest a span waits to be exported.
+	flushInterval = 5 * time.Second
+)
+
+// Recorder starts the root span of each trace and collects the spans when
+// they end. It keeps the most recent spans so a trace can be looked up while
+// the request is still being handled, and exports them in batches.
+type Recorder struct {
+	service  string
+	exporter Exporter
+
+	mu      sync.Mutex
+	recent  []SpanData
+	next    int
+	pending []SpanData
+
+	flush chan struct{}
+	done  chan struct{}
+	once  sync.Once
+	wg    sync.WaitGroup
+}
+
+// NewRecorder constructs a recorder for the named service. The exporter can
+// be nil if the spans only need to be kept in memory.
+func NewRecorder(service string, exporter Exporter) *Recorder {
+	rec := Recorder{
+		service:  service,
+		exporter: exporter,
+		recent:   make([]SpanData, 0, keep),
+		flush:    make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+
+	if exporter != nil {
+		rec.wg.Add(1)
+		go rec.export()
+	}
+
+	return &rec
+}
+
+// Start begins the root span of a new trace, returning a context carrying
+// the span.
+func (rec *Recorder) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, *Span) {
+	s := Span{
+		rec: rec,
+		data: SpanData{
+			TraceID:    newID(16),
+			SpanID:     newID(8),
+			Name:       name,
+			Kind:       KindServer,
+			Start:      time.Now(),
+			Attributes: attrs,
+		},
+	}
+
+	return context.WithValue(ctx, spanKey, &s), &s
+}
+
+// Trace returns the finished spans of the trace that are still being kept,
+// in the order they ended.
+func (rec *Recorder) Trace(traceID string) []SpanData {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	// Walk the ring from the oldest span.
+	var spans []SpanData
+	for i := range len(rec.recent) {
+		sd := rec.recent[(rec.next+i)%len(rec.recent)]
+		if sd.TraceID == traceID {
+			spans = append(spans, sd)
+		}
+	}
+	return spans
+}
+
+// Close exports the pending spans and stops the recorder.
+func (rec *Recorder) Close() error {
+	rec.once.Do(func() {
+		close(rec.done)
+	})
+	rec.wg.Wait()
+	return nil
+}
+
+// record keeps the finished span and queues it for export.
+func (rec *Recorder) record(sd SpanData) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if len(rec.recent) < keep {
+		rec.recent = append(rec.recent, sd)
+	} else {
+		rec.recent[rec.next] = sd
+		rec.next = (rec.next + 1) % keep
+	}
+
+	if rec.exporter == nil {
+		return
+	}
+
+	rec.pending = append(rec.pending, sd)
+	if len(rec.pending) >= batchSize {
+		select {
+		case rec.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// export sends the pending spans to the exporter when a batch is full, on
+// an interval, and when the recorder is closed.
+func (rec *Recorder) export() {
+	defer rec.wg.Done()
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-rec.flush:
+		case <-ticker.C:
+		case <-rec.done:
+			rec.send()
+			return
+		}
+		rec.send()
+	}
+}
+
+// send exports the pending spans. Failed batches are dropped so a missing
+// collector can't make the service run out of memory.
+func (rec *Recorder) send() {
+	rec.mu.Lock()
+	spans := rec.pending
+	rec.pending = nil
+	rec.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := rec.exporter.Export(ctx, rec.service, spans); err != nil {
+		slog.Error("exporting spans", "spans", len(spans), "error", err)
+	}
+}
@@ -0,0 +1,162 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// SpanKind describes the relationship of a span to the rest of the trace,
+// using the values from the OTLP specification.
+type SpanKind int
+
+// Set of span kinds used by the recorder.
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+)
+
+// StatusCode reports if the operation a span covers succeeded, using the
+// values from the OTLP specification.
+type StatusCode int
+
+// Set of span status codes.
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// SpanData is a finished span.
+type SpanData struct {
+	TraceID       string // 32 hex characters.
+	SpanID        string // 16 hex characters.
+	ParentSpanID  string // Empty for the root span of a trace.
+	Name          string
+	Kind          SpanKind
+	Start         time.Time
+	End           time.Time
+	Attributes    []slog.Attr
+	Status        StatusCode
+	StatusMessage string
+}
+
+// Duration returns how long the span took.
+func (sd SpanData) Duration() time.Duration {
+	return sd.End.Sub(sd.Start)
+}
+
+// Span records the timing of an operation. A span is ended exactly once,
+// after which it's handed to the recorder. A span that isn't part of a
+// trace does nothing, so code can always create spans.
+type Span struct {
+	rec *Recorder
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// Start begins a span that's a child of the span in the context, returning
+// a context carrying the new span. If the context doesn't carry a span the
+// returned span does nothing.
+func Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, *Span) {
+	parent := FromContext(ctx)
+	if parent.rec == nil {
+		return ctx, parent
+	}
+
+	s := Span{
+		rec: parent.rec,
+		data: SpanData{
+			TraceID:      parent.data.TraceID,
+			SpanID:       newID(8),
+			ParentSpanID: parent.data.SpanID,
+			Name:         name,
+			Kind:         KindInternal,
+			Start:        time.Now(),
+			Attributes:   attrs,
+		},
+	}
+
+	return context.WithValue(ctx, spanKey, &s), &s
+}
+
+// FromContext returns the span carried by the context. If there isn't one
+// a span that does nothing is returned.
+func FromContext(ctx context.Context) *Span {
+	if s, ok := ctx.Value(spanKey).(*Span); ok {
+		return s
+	}
+	return &Span{}
+}
+
+// TraceID returns the ID of the trace the span belongs to.
+func (s *Span) TraceID() string {
+	return s.data.TraceID
+}
+
+// SpanID returns the ID of the span.
+func (s *Span) SpanID() string {
+	return s.data.SpanID
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...slog.Attr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// SetError marks the operation as failed. A nil error marks it as
+// successful.
+func (s *Span) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		s.data.Status, s.data.StatusMessage = StatusOK, ""
+		return
+	}
+	s.data.Status, s.data.StatusMessage = StatusError, err.Error()
+}
+
+// End finishes the span and hands it to the recorder. Calls after the
+// first are ignored.
+func (s *Span) End() {
+	s.mu.Lock()
+	if s.ended || s.rec == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.rec.record(data)
+}
+
+// newID returns a random ID of n bytes as hex.
+func newID(n int) string {
+	b := make([]byte, 0, 16)
+	for len(b) < n {
+		b = binary.LittleEndian.AppendUint64(b, rand.Uint64())
+	}
+	return hex.EncodeToString(b[:n])
+}
+
+// Finished returns the spans of the trace carried by the context that have
+// ended, such as the ones for the work a request has finished so far.
+func Finished(ctx context.Context) []SpanData {
+	s := FromContext(ctx)
+	if s.rec == nil {
+		return nil
+	}
+	return s.rec.Trace(s.data.TraceID)
+}
@@ -0,0 +1,44 @@
+// Package telemetry provides request scoped logging and a lightweight span
+// recorder for tracing a request through the search pipeline. Spans are
+// exported as OTLP JSON so they can be loaded by OpenTelemetry tools.
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ctxKey is the type of the keys for the values stored in a context.
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	loggerKey
+	spanKey
+)
+
+// WithRequestID returns a context that carries the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the request ID carried by the context, or an empty
+// string if there isn't one.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithLogger returns a context that carries the logger.
+func WithLogger(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, log)
+}
+
+// Logger returns the logger carried by the context, which is tagged with
+// the request's details. The default logger is returned if there isn't one.
+func Logger(ctx context.Context) *slog.Logger {
+	if log, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return log
+	}
+	return slog.Default()
+}
@@ -0,0 +1,145 @@
+package telemetry
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	succeed = "\u2713"
+	failed  = "\u2717"
+)
+
+// TestSpans validates spans are recorded as children of the span in the
+// context.
+func TestSpans(t *testing.T) {
+	rec := NewRecorder("test", nil)
+
+	t.Log("Given the need to record the spans of a trace.")
+	{
+		t.Log("\tTest 0:\tWhen the context carries a span.")
+		{
+			ctx, root := rec.Start(context.Background(), "GET /search")
+			_, child := Start(ctx, "search cnn", slog.String("search.engine", "cnn"))
+			child.SetError(errors.New("feed is down"))
+			child.End()
+			child.End()
+
+			spans := Finished(ctx)
+			if len(spans) != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould record the child once it ends : %d spans", failed, len(spans))
+			}
+			t.Logf("\t%s\tTest 0:\tShould record the child once it ends.", succeed)
+
+			sd := spans[0]
+			if sd.TraceID != root.TraceID() || sd.ParentSpanID != root.SpanID() || len(sd.TraceID) != 32 || len(sd.SpanID) != 16 {
+				t.Fatalf("\t%s\tTest 0:\tShould be a child of the root span : %+v", failed, sd)
+			}
+			t.Logf("\t%s\tTest 0:\tShould be a child of the root span.", succeed)
+
+			if sd.Status != StatusError || sd.StatusMessage != "feed is down" {
+				t.Fatalf("\t%s\tTest 0:\tShould record the error : %+v", failed, sd)
+			}
+			t.Logf("\t%s\tTest 0:\tShould record the error.", succeed)
+
+			root.End()
+			if spans := rec.Trace(root.TraceID()); len(spans) != 2 || spans[1].Kind != KindServer {
+				t.Fatalf("\t%s\tTest 0:\tShould record the root span as a server span : %+v", failed, spans)
+			}
+			t.Logf("\t%s\tTest 0:\tShould record the root span as a server span.", succeed)
+		}
+
+		t.Log("\tTest 1:\tWhen the context doesn't carry a span.")
+		{
+			ctx, span := Start(context.Background(), "orphan")
+			span.SetAttributes(slog.Int("n", 1))
+			span.End()
+
+			if FromContext(ctx).TraceID() != "" || Finished(ctx) != nil {
+				t.Fatalf("\t%s\tTest 1:\tShould not record anything.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould not record anything.", succeed)
+		}
+	}
+}
+
+// TestExport validates spans survive being exported as OTLP JSON to a file
+// and to a collector.
+func TestExport(t *testing.T) {
+	var c Collector
+	srv := httptest.NewServer(&c)
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	fe, err := NewFileExporter(path)
+	if err != nil {
+		t.Fatalf("\t%s\tShould be able to create the file : %v", failed, err)
+	}
+	defer fe.Close()
+
+	exporters := []Exporter{fe, &HTTPExporter{URL: srv.URL + "/v1/traces", Client: srv.Client()}}
+
+	t.Log("Given the need to export spans as OTLP JSON.")
+	{
+		for i, exp := range exporters {
+			t.Logf("\tTest %d:\tWhen exporting with %T.", i, exp)
+			{
+				rec := NewRecorder("search", exp)
+				ctx, root := rec.Start(context.Background(), "GET /search", slog.String("request.id", "abc"))
+				_, child := Start(ctx, "feed", slog.Int("search.results", 3), slog.Bool("cached", true), slog.Float64("score", 1.5))
+				child.End()
+				root.End()
+				rec.Close()
+
+				var spans []SpanData
+				switch exp.(type) {
+				case *FileExporter:
+					f, err := os.Open(path)
+					if err != nil {
+						t.Fatalf("\t%s\tTest %d:\tShould be able to open the file : %v", failed, i, err)
+					}
+					defer f.Close()
+
+					s := bufio.NewScanner(f)
+					for s.Scan() {
+						batch, err := UnmarshalOTLP(s.Bytes())
+						if err != nil {
+							t.Fatalf("\t%s\tTest %d:\tShould write OTLP JSON lines : %v", failed, i, err)
+						}
+						spans = append(spans, batch...)
+					}
+				default:
+					spans = c.Spans()
+				}
+
+				if len(spans) != 2 {
+					t.Fatalf("\t%s\tTest %d:\tShould export both spans : %d", failed, i, len(spans))
+				}
+				t.Logf("\t%s\tTest %d:\tShould export both spans.", succeed, i)
+
+				sd := spans[0]
+				if sd.TraceID != root.TraceID() || sd.ParentSpanID != root.SpanID() || sd.Start.IsZero() || sd.Duration() < 0 {
+					t.Fatalf("\t%s\tTest %d:\tShould keep the IDs and times : %+v", failed, i, sd)
+				}
+				t.Logf("\t%s\tTest %d:\tShould keep the IDs and times.", succeed, i)
+
+				want := []slog.Attr{slog.Int64("search.results", 3), slog.Bool("cached", true), slog.Float64("score", 1.5)}
+				if len(sd.Attributes) != len(want) {
+					t.Fatalf("\t%s\tTest %d:\tShould keep the attributes : %v", failed, i, sd.Attributes)
+				}
+				for j := range want {
+					if !sd.Attributes[j].Equal(want[j]) {
+						t.Fatalf("\t%s\tTest %d:\tShould keep the attributes : got %v, want %v", failed, i, sd.Attributes[j], want[j])
+					}
+				}
+				t.Logf("\t%s\tTest %d:\tShould keep the attributes.", succeed, i)
+			}
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ardanlabs/gotraining/topics/go/testing/tests/example4/handlers"
 )
 
+//runexamples:skip listens for connections
 func main() {
 	handlers.Routes()
 
@@ -10,6 +10,7 @@ func byHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, Founder)
 }
 
+//runexamples:skip listens for connections
 func main() {
 	http.HandleFunc("/by", byHandler)
 
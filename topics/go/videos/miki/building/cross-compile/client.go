@@ -9,6 +9,7 @@ import (
 	"os"
 )
 
+//runexamples:skip needs a network connection
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "usage: quotes")
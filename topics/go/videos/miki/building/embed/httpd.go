@@ -54,6 +54,7 @@ func (s *Server) getMetrics(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+//runexamples:skip listens for connections
 func main() {
 	dsn := os.Getenv("DSN")
 	if dsn == "" {
@@ -15,6 +15,7 @@ func handler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, counter)
 }
 
+//runexamples:skip listens for connections
 func main() {
 	http.HandleFunc("/", handler)
 
@@ -18,6 +18,7 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, "OK")
 }
 
+//runexamples:skip listens for connections
 func main() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", healthHandler)
@@ -33,6 +33,7 @@ func (s *Server) dailyReportHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+//runexamples:skip listens for connections
 func main() {
 	s := Server{db: &DB{}}
 	http.HandleFunc("/daily", s.dailyReportHandler)
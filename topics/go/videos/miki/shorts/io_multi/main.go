@@ -8,6 +8,7 @@ import (
 	"os"
 )
 
+//runexamples:skip writes files next to its source
 func main() {
 	flag := os.O_APPEND | os.O_CREATE | os.O_WRONLY
 	file, err := os.OpenFile("app.log", flag, 0644)
@@ -23,6 +23,7 @@ func handler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "read %d bytes\n", len(data))
 }
 
+//runexamples:skip listens for connections
 func main() {
 	http.HandleFunc("/", handler)
 	log.Printf("INFO: max request size = %d", maxSize)
@@ -21,6 +21,7 @@ func rated(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, "OK")
 }
 
+//runexamples:skip listens for connections
 func main() {
 	http.HandleFunc("/", rated)
 
@@ -32,6 +32,7 @@ func handler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "ID: %v\n", rid)
 }
 
+//runexamples:skip listens for connections
 func main() {
 	h := RequestID(http.HandlerFunc(handler))
 	http.Handle("/", h)
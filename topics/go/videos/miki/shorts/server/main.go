@@ -9,6 +9,7 @@ import (
 	"net/http"
 )
 
+//runexamples:skip listens for connections
 func main() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", healthHandler)
@@ -11,6 +11,7 @@ func handler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintln(w, "OK")
 }
 
+//runexamples:skip listens for connections
 func main() {
 	defer func() {
 		slog.Info("Heghlu'meH QaQ jajvam")